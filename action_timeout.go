@@ -0,0 +1,114 @@
+package pokertable
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/weedbox/pokerface"
+)
+
+var (
+	ErrTableActionTimerExpired = errors.New("table: action timer is expired")
+)
+
+/*
+refreshActionTimer 重設玩家動作計時器
+  - 適用時機: 遊戲狀態更新時
+  - 只有 TableMeta.ActionTimeoutEnforced 開啟時才會由桌次引擎處理超時
+  - 暫離玩家不等待動作時間，直接自動動作
+  - 每次重設都會遞增計時器序號，超時處理時序號不同表示計時器已過期
+*/
+func (te *tableEngine) refreshActionTimer(event pokerface.GameEvent, gs *pokerface.GameState) {
+	if !te.table.Meta.ActionTimeoutEnforced || te.table.Meta.ActionTime <= 0 {
+		return
+	}
+
+	timerSerial := atomic.AddInt64(&te.actionTimerSerial, 1)
+
+	if !te.isWagerActionRequired(event, gs) {
+		te.tbForAction.Cancel()
+		return
+	}
+
+	gameID := gs.GameID
+	gamePlayerIdx := gs.Status.CurrentPlayer
+	playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gamePlayerIdx)
+	if playerIdx == UnsetValue {
+		return
+	}
+
+	isSitOut := te.table.State.PlayerStates[playerIdx].IsSitOut
+	duration := time.Unix(te.table.State.CurrentActionEndAt, 0).Sub(te.clock.Now())
+	if isSitOut || duration < 0 {
+		duration = 0
+	}
+
+	te.tbForAction.NewTask(duration, func(isCancelled bool) {
+		if isCancelled {
+			return
+		}
+
+		// 暫離玩家的計時器會在遊戲狀態更新流程中直接觸發，改由另一個 goroutine 執行玩家動作
+		go te.handleActionTimeout(gameID, gamePlayerIdx, timerSerial, isSitOut)
+	})
+}
+
+/*
+handleActionTimeout 玩家動作超時，自動過牌或棄牌
+  - 計時器序號不同表示設定計時器後遊戲狀態已更新 (玩家已動作或已輪到下一個動作)，不處理
+  - 可以過牌時過牌，否則棄牌
+  - 連續超時達 TableMeta.MaxActionTimeoutCount 次時設為暫離
+*/
+func (te *tableEngine) handleActionTimeout(gameID string, gamePlayerIdx int, timerSerial int64, isSitOut bool) (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+
+	gs := te.table.State.GameState
+	if te.isReleased || gs == nil || gs.GameID != gameID || gs.Status.CurrentPlayer != gamePlayerIdx || atomic.LoadInt64(&te.actionTimerSerial) != timerSerial {
+		return ErrTableActionTimerExpired
+	}
+
+	playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gamePlayerIdx)
+	if playerIdx == UnsetValue {
+		return ErrGamePlayerNotFound
+	}
+	playerState := te.table.State.PlayerStates[playerIdx]
+
+	// 暫離玩家的自動動作於重播時由計時器重新觸發，不需記錄
+	if !isSitOut {
+		args := map[string]interface{}{
+			"game_id":         gameID,
+			"game_player_idx": gamePlayerIdx,
+			"timer_serial":    timerSerial,
+		}
+		defer te.recordCommand("PlayerActionTimeout", playerState.PlayerID, args)(&err)
+	}
+
+	timeoutCount := playerState.ActionTimeoutCount
+	if gs.HasAction(gamePlayerIdx, WagerAction_Check) {
+		err = te.playerCheck(playerState.PlayerID)
+	} else {
+		err = te.playerFold(playerState.PlayerID)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 暫離玩家的自動動作不算超時
+	if isSitOut {
+		return nil
+	}
+
+	playerState.ActionTimeoutCount = timeoutCount + 1
+	if te.table.Meta.MaxActionTimeoutCount > 0 && playerState.ActionTimeoutCount >= te.table.Meta.MaxActionTimeoutCount {
+		if err := te.sitOutPlayer(playerState); err != nil {
+			te.emitErrorEvent("handleActionTimeout#sitOutPlayer", playerState.PlayerID, err)
+		}
+	}
+
+	te.emitTablePlayerActionTimeoutEvent(playerState)
+	te.emitTablePlayerStateEvent(playerState)
+	te.emitEvent("PlayerActionTimeout", playerState.PlayerID)
+	return nil
+}
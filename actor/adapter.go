@@ -12,6 +12,7 @@ type Adapter interface {
 	UpdateTableState(t *pokertable.Table) error
 	GetGamePlayerIndex(playerID string) int
 	GetGameState() *pokerface.GameState
+	GetLegalActions(playerID string) *pokertable.TableLegalActions
 
 	// Player actions
 	Pass(playerID string) error
@@ -194,13 +194,13 @@ func (br *botRunner) requestMove(gs *pokerface.GameState, playerIdx int) error {
 	}
 
 	if !br.isHumanized || br.tableInfo.Meta.ActionTime == 0 {
-		return br.requestAI(gs, playerIdx)
+		return br.requestAI()
 	}
 
 	// For simulating human-like behavior, to incorporate random delays when performing actions.
 	thinkingTime := rand.Intn(br.tableInfo.Meta.ActionTime)
 	if thinkingTime == 0 {
-		return br.requestAI(gs, playerIdx)
+		return br.requestAI()
 	}
 
 	return br.timebank.NewTask(time.Duration(thinkingTime)*time.Second, func(isCancelled bool) {
@@ -209,7 +209,7 @@ func (br *botRunner) requestMove(gs *pokerface.GameState, playerIdx int) error {
 			return
 		}
 
-		br.requestAI(gs, playerIdx)
+		br.requestAI()
 	})
 }
 
@@ -256,19 +256,19 @@ func (br *botRunner) calcAction(actions []string) string {
 	return actions[len(actions)-1]
 }
 
-func (br *botRunner) requestAI(gs *pokerface.GameState, playerIdx int) error {
+func (br *botRunner) requestAI() error {
 
-	player := gs.Players[playerIdx]
+	legalActions := br.actor.GetTable().GetLegalActions(br.playerID)
 
 	// None of actions is allowed
-	if len(player.AllowedActions) == 0 {
+	if legalActions == nil || len(legalActions.Actions) == 0 {
 		return nil
 	}
 
-	action := player.AllowedActions[0]
+	action := legalActions.Actions[0]
 
-	if len(player.AllowedActions) > 1 {
-		action = br.calcAction(player.AllowedActions)
+	if len(legalActions.Actions) > 1 {
+		action = br.calcAction(legalActions.Actions)
 	}
 
 	// Calculate chips
@@ -278,9 +278,9 @@ func (br *botRunner) requestAI(gs *pokerface.GameState, playerIdx int) error {
 		// Debugging messages
 		defer func() {
 			if chips > 0 {
-				fmt.Printf("Action %s %v %s(%d)\n", br.playerID, player.AllowedActions, action, chips)
+				fmt.Printf("Action %s %v %s(%d)\n", br.playerID, legalActions.Actions, action, chips)
 			} else {
-				fmt.Printf("Action %s %v %s\n", br.playerID, player.AllowedActions, action)
+				fmt.Printf("Action %s %v %s\n", br.playerID, legalActions.Actions, action)
 			}
 		}()
 	*/
@@ -288,13 +288,14 @@ func (br *botRunner) requestAI(gs *pokerface.GameState, playerIdx int) error {
 	switch action {
 	case "bet":
 
-		minBet := gs.Status.MiniBet
+		minBet := legalActions.MinBet
+		maxBet := legalActions.MaxBet
 
-		if player.InitialStackSize <= minBet {
-			return br.actions.Bet(player.InitialStackSize)
+		if maxBet <= minBet {
+			return br.actions.Bet(maxBet)
 		}
 
-		chips = rand.Int63n(player.InitialStackSize-minBet) + minBet
+		chips = rand.Int63n(maxBet-minBet) + minBet
 
 		err := br.actions.Bet(chips)
 		if err != nil {
@@ -305,8 +306,8 @@ func (br *botRunner) requestAI(gs *pokerface.GameState, playerIdx int) error {
 		return nil
 	case "raise":
 
-		maxChipLevel := player.InitialStackSize
-		minChipLevel := gs.Status.CurrentWager + gs.Status.PreviousRaiseSize
+		maxChipLevel := legalActions.MaxRaise
+		minChipLevel := legalActions.MinRaise
 
 		if maxChipLevel <= minChipLevel {
 			err := br.actions.Raise(maxChipLevel)
@@ -328,19 +329,12 @@ func (br *botRunner) requestAI(gs *pokerface.GameState, playerIdx int) error {
 		br.updateWagerAction(pokertable.WagerAction_Raise, chips)
 		return nil
 	case "call":
-		// TODO: should move logic to pokertable
-		wager := int64(0)
-		gamePlayerIdx := br.tableInfo.FindGamePlayerIdx(br.playerID)
-		if gamePlayerIdx >= 0 && br.tableInfo != nil && br.tableInfo.State.GameState != nil && gamePlayerIdx < len(br.tableInfo.State.GameState.Players) {
-			wager = br.tableInfo.State.GameState.Status.CurrentWager - br.tableInfo.State.GameState.GetPlayer(gamePlayerIdx).Wager
-		}
-
 		err := br.actions.Call()
 		if err != nil {
 			return err
 		}
 
-		br.updateWagerAction(pokertable.WagerAction_Call, wager)
+		br.updateWagerAction(pokertable.WagerAction_Call, legalActions.CallAmount)
 		return nil
 	case "check":
 		err := br.actions.Check()
@@ -351,19 +345,12 @@ func (br *botRunner) requestAI(gs *pokerface.GameState, playerIdx int) error {
 		br.updateWagerAction(pokertable.WagerAction_Check, 0)
 		return nil
 	case "allin":
-		// TODO: should move logic to pokertable
-		wager := int64(0)
-		gamePlayerIdx := br.tableInfo.FindGamePlayerIdx(br.playerID)
-		if gamePlayerIdx >= 0 && br.tableInfo != nil && br.tableInfo.State.GameState != nil && gamePlayerIdx < len(br.tableInfo.State.GameState.Players) {
-			wager = br.tableInfo.State.GameState.GetPlayer(gamePlayerIdx).StackSize
-		}
-
 		err := br.actions.Allin()
 		if err != nil {
 			return err
 		}
 
-		br.updateWagerAction(pokertable.WagerAction_AllIn, wager)
+		br.updateWagerAction(pokertable.WagerAction_AllIn, legalActions.AllinAmount)
 		return nil
 	}
 
@@ -27,18 +27,17 @@ func (obr *observerRunner) EnabledSystemMode(enabled bool) {
 
 func (obr *observerRunner) UpdateTableState(tableInfo *pokertable.Table) error {
 
-	obr.tableInfo = tableInfo
-
 	if !obr.systemMode {
-		// Filtering private information for observer
-		switch tableInfo.State.Status {
-		case pokertable.TableStateStatus_TableGamePlaying:
-			fallthrough
-		case pokertable.TableStateStatus_TableGameSettled:
-			tableInfo.State.GameState.AsObserver()
+		// Filtering private information for observer without touching the shared table
+		view, err := tableInfo.ViewForObserver()
+		if err != nil {
+			return err
 		}
+		tableInfo = view
 	}
 
+	obr.tableInfo = tableInfo
+
 	// Emit event
 	obr.onTableStateUpdated(tableInfo)
 
@@ -48,7 +48,13 @@ func (pr *playerRunner) SetActor(a Actor) {
 	pr.actions = NewActions(a, pr.playerID)
 }
 
-func (pr *playerRunner) UpdateTableState(table *pokertable.Table) error {
+func (pr *playerRunner) UpdateTableState(tableInfo *pokertable.Table) error {
+
+	// Filtering private information for player without touching the shared table
+	table, err := tableInfo.ViewFor(pr.playerID)
+	if err != nil {
+		return err
+	}
 
 	gs := table.State.GameState
 	pr.tableInfo = table
@@ -99,9 +105,6 @@ func (pr *playerRunner) UpdateTableState(table *pokertable.Table) error {
 			return nil
 		}
 
-		// Filtering private information fpr player
-		gs.AsPlayer(gamePlayerIdx)
-
 		// We have actions allowed by game engine
 		player := gs.GetPlayer(gamePlayerIdx)
 		if len(player.AllowedActions) > 0 {
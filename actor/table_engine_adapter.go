@@ -53,6 +53,10 @@ func (tea *tableEngineAdapter) GetGamePlayerIndex(playerID string) int {
 	return tea.table.GamePlayerIndex(playerID)
 }
 
+func (tea *tableEngineAdapter) GetLegalActions(playerID string) *pokertable.TableLegalActions {
+	return tea.table.LegalActions(playerID)
+}
+
 func (tea *tableEngineAdapter) Pass(playerID string) error {
 	return tea.engine.PlayerPass(playerID)
 }
@@ -90,6 +94,6 @@ func (tea *tableEngineAdapter) Raise(playerID string, chipLevel int64) error {
 }
 
 func (tea *tableEngineAdapter) ExtendTime(playerID string, duration time.Duration) error {
-	//TODO: need to be implemented
-	return nil
+	_, err := tea.engine.PlayerExtendActionDeadline(playerID, int(duration.Seconds()))
+	return err
 }
@@ -0,0 +1,69 @@
+package pokertable
+
+import (
+	"errors"
+
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+)
+
+var (
+	ErrTableInvalidAnteMode = errors.New("table: invalid ante mode")
+)
+
+var SupportedAnteModes = []string{
+	AnteMode_PerPlayer,
+	AnteMode_BigBlind,
+	AnteMode_Button,
+}
+
+func isValidAnteMode(mode string) bool {
+	return mode == "" || funk.ContainsString(SupportedAnteModes, mode)
+}
+
+/*
+addGameAntePost 計算本手代付全桌前注
+  - 只有大盲代付 (BB ante) 與按鈕位代付 (button ante) 需要計算，每位玩家各自支付時由遊戲的前注階段處理
+  - 代付玩家籌碼不足以同時支付盲注與前注時，優先支付盲注，前注只付剩餘籌碼
+  - 前注與死盲相同，從代付玩家籌碼扣除後於結算時併入主池
+*/
+func (te *tableEngine) addGameAntePost(posts map[int]GameBlindPost, players []*pokerface.PlayerSetting) {
+	blind := te.table.State.BlindState
+	if !blind.IsTableAnte() || blind.Ante <= 0 {
+		return
+	}
+
+	payerPosition := Position_BB
+	if blind.AnteMode == AnteMode_Button {
+		payerPosition = Position_Dealer
+	}
+
+	for gamePlayerIdx, player := range players {
+		if !funk.ContainsString(player.Positions, payerPosition) {
+			continue
+		}
+
+		// 代付玩家本手需支付的盲注
+		blindDue := int64(0)
+		if blind.BB > 0 && funk.ContainsString(player.Positions, Position_BB) {
+			blindDue = blind.BB
+		} else if blind.SB > 0 && funk.ContainsString(player.Positions, Position_SB) {
+			blindDue = blind.SB
+		} else if blind.Dealer > 0 && funk.ContainsString(player.Positions, Position_Dealer) {
+			blindDue = blind.Dealer
+		}
+
+		post := posts[gamePlayerIdx]
+		available := player.Bankroll - blindDue - post.Live - post.Dead
+		if available <= 0 {
+			return
+		}
+
+		post.Ante = blind.Ante
+		if post.Ante > available {
+			post.Ante = available
+		}
+		posts[gamePlayerIdx] = post
+		return
+	}
+}
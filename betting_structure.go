@@ -0,0 +1,227 @@
+package pokertable
+
+import (
+	"errors"
+
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+)
+
+var (
+	ErrTablePlayerWagerOverLimit  = errors.New("table: player wager exceeds betting structure limit")
+	ErrTablePlayerWagerUnderLimit = errors.New("table: player wager is less than betting structure limit")
+	ErrTablePlayerRaiseCapReached = errors.New("table: player raise cap reached in this round")
+)
+
+const (
+	// 固定限注每條街預設加注次數上限 (一次下注 + 三次加注)
+	DefaultFixedLimitRaiseCap = 3
+)
+
+var SupportedBettingStructures = []string{
+	BettingStructure_NoLimit,
+	BettingStructure_PotLimit,
+	BettingStructure_FixedLimit,
+}
+
+/*
+NewTableWagerLimit 計算玩家在當前回合的下注限制
+  - 無限注: 最多可以下注/加注到全下
+  - 底池限注: 最多可以加注到「跟注後的底池大小」
+  - 固定限注: Preflop & Flop 每次下注/加注小注 (1 BB)，Turn & River 每次下注/加注大注 (2 BB)，每條街加注次數有上限
+  - roundBetCount: 本回合完整下注/加注次數 (TableState.GameRoundBetCount)
+*/
+func NewTableWagerLimit(meta TableMeta, gs *pokerface.GameState, gamePlayerIdx int, roundBetCount int) *TableWagerLimit {
+	player := gs.GetPlayer(gamePlayerIdx)
+	if player == nil {
+		return nil
+	}
+
+	stack := player.InitialStackSize
+	minRaiseSize := gs.Status.PreviousRaiseSize
+	if minRaiseSize < gs.Status.MiniBet {
+		minRaiseSize = gs.Status.MiniBet
+	}
+
+	limit := &TableWagerLimit{
+		MinBet:   gs.Status.MiniBet,
+		MaxBet:   stack,
+		MinRaise: gs.Status.CurrentWager + minRaiseSize,
+		MaxRaise: stack,
+	}
+
+	switch meta.BettingStructure {
+	case BettingStructure_PotLimit:
+		// 跟注後的底池大小
+		pot := gs.Status.CurrentWager - player.Wager
+		for _, p := range gs.Players {
+			pot += p.Pot + p.Wager
+		}
+		limit.MaxBet = pot
+		limit.MaxRaise = gs.Status.CurrentWager + pot
+	case BettingStructure_FixedLimit:
+		betSize := FixedLimitBetSize(gs.Meta.Blind.BB, gs.Status.Round)
+		limit.MinBet = betSize
+		limit.MaxBet = betSize
+		limit.MinRaise = gs.Status.CurrentWager + betSize
+		limit.MaxRaise = gs.Status.CurrentWager + betSize
+
+		// 本回合加注次數 (Preflop 大盲視為第一次下注，其他回合第一次完整下注不算加注)
+		raiseCap := meta.FixedLimitRaiseCap
+		if raiseCap <= 0 {
+			raiseCap = DefaultFixedLimitRaiseCap
+		}
+		raiseCount := roundBetCount
+		if gs.Status.Round != GameRound_Preflop {
+			raiseCount--
+		}
+		limit.IsRaiseCapped = raiseCount >= raiseCap
+	}
+
+	// 籌碼不足時只能全下
+	limit.MaxBet = minInt64(limit.MaxBet, stack)
+	limit.MinBet = minInt64(limit.MinBet, limit.MaxBet)
+	limit.MaxRaise = minInt64(limit.MaxRaise, stack)
+	limit.MinRaise = minInt64(limit.MinRaise, limit.MaxRaise)
+
+	return limit
+}
+
+/*
+FixedLimitBetSize 固定限注的每次下注/加注量
+  - 小注 (Preflop & Flop): 1 BB
+  - 大注 (Turn & River): 2 BB
+*/
+func FixedLimitBetSize(bb int64, round string) int64 {
+	if funk.ContainsString([]string{GameRound_Turn, GameRound_River}, round) {
+		return bb * 2
+	}
+	return bb
+}
+
+func (te *tableEngine) bettingStructure() string {
+	if te.table.Meta.BettingStructure == "" {
+		return BettingStructure_NoLimit
+	}
+	return te.table.Meta.BettingStructure
+}
+
+// refreshWagerLimit 更新當前動作玩家的下注限制
+func (te *tableEngine) refreshWagerLimit(event pokerface.GameEvent, gs *pokerface.GameState) {
+	te.table.State.WagerLimit = nil
+
+	if event != pokerface.GameEvent_RoundStarted {
+		return
+	}
+
+	p := gs.GetPlayer(gs.Status.CurrentPlayer)
+	if p == nil || !funk.ContainsString(p.AllowedActions, WagerAction_Bet) && !funk.ContainsString(p.AllowedActions, WagerAction_Raise) {
+		return
+	}
+
+	te.table.State.WagerLimit = NewTableWagerLimit(te.table.Meta, gs, gs.Status.CurrentPlayer, te.table.State.GameRoundBetCount)
+}
+
+// fixedLimitWager 固定限注的下注/加注量由引擎決定，忽略玩家帶入的籌碼量
+func (te *tableEngine) fixedLimitWager(gamePlayerIdx int, action string, chips int64) int64 {
+	if te.bettingStructure() != BettingStructure_FixedLimit {
+		return chips
+	}
+
+	gs := te.game.GetGameState()
+	if gs == nil {
+		return chips
+	}
+
+	limit := NewTableWagerLimit(te.table.Meta, gs, gamePlayerIdx, te.table.State.GameRoundBetCount)
+	if limit == nil {
+		return chips
+	}
+
+	if action == WagerAction_Bet {
+		return limit.MaxBet
+	}
+	return limit.MaxRaise
+}
+
+/*
+validateWager 檢查玩家下注/加注/全下是否符合下注結構
+  - chipLevel: 動作後玩家在本回合的下注總量
+  - 無限注不限制 (維持 pokerface 原本的行為)
+*/
+func (te *tableEngine) validateWager(gamePlayerIdx int, action string, chipLevel int64) error {
+	if te.bettingStructure() == BettingStructure_NoLimit {
+		return nil
+	}
+
+	gs := te.game.GetGameState()
+	if gs == nil || gs.GetPlayer(gamePlayerIdx) == nil {
+		return ErrGamePlayerNotFound
+	}
+	player := gs.GetPlayer(gamePlayerIdx)
+
+	limit := NewTableWagerLimit(te.table.Meta, gs, gamePlayerIdx, te.table.State.GameRoundBetCount)
+	isBet := gs.Status.CurrentWager == 0
+	isRaise := !isBet && (action == WagerAction_Raise || player.InitialStackSize > gs.Status.CurrentWager)
+	if isRaise && limit.IsRaiseCapped {
+		return ErrTablePlayerRaiseCapReached
+	}
+
+	minChipLevel, maxChipLevel := limit.MinRaise, limit.MaxRaise
+	if isBet {
+		minChipLevel, maxChipLevel = limit.MinBet, limit.MaxBet
+	}
+
+	switch action {
+	case WagerAction_AllIn:
+		// 全下但沒有超過當前下注量 (跟注全下) 不受限制
+		if player.InitialStackSize > gs.Status.CurrentWager && player.InitialStackSize > maxChipLevel {
+			return ErrTablePlayerWagerOverLimit
+		}
+	default:
+		if chipLevel > maxChipLevel {
+			return ErrTablePlayerWagerOverLimit
+		}
+
+		if chipLevel < minChipLevel {
+			return ErrTablePlayerWagerUnderLimit
+		}
+	}
+
+	return nil
+}
+
+/*
+countRoundBet 記錄本回合完整下注/加注次數
+  - 適用時機: 遊戲狀態更新時，於產生下注範圍之前 (prev 為上一次更新的遊戲狀態，需持有 te.lock)
+  - 只計算等待玩家下注時 (RoundStarted) 的狀態變化，盲注與 Straddle 不計入
+  - 本回合最高下注量增加達一次完整下注/加注 (固定限注為當前回合的下注量，其他為最小加注量) 才計入，不足額的全下不計入
+*/
+func (te *tableEngine) countRoundBet(prev, gs *pokerface.GameState) {
+	if prev == nil || gs == nil || prev.Status.Round != gs.Status.Round || prev.GameID != gs.GameID {
+		return
+	}
+
+	if prev.Status.CurrentEvent != pokerface.GameEventSymbols[pokerface.GameEvent_RoundStarted] {
+		return
+	}
+
+	fullSize := prev.Status.PreviousRaiseSize
+	if fullSize < prev.Status.MiniBet {
+		fullSize = prev.Status.MiniBet
+	}
+	if te.bettingStructure() == BettingStructure_FixedLimit {
+		fullSize = FixedLimitBetSize(prev.Meta.Blind.BB, prev.Status.Round)
+	}
+
+	if fullSize > 0 && gs.Status.CurrentWager-prev.Status.CurrentWager >= fullSize {
+		te.table.State.GameRoundBetCount++
+	}
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
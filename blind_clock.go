@@ -0,0 +1,361 @@
+package pokertable
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrBlindClockAlreadyStarted = errors.New("blind clock: already started")
+	ErrBlindClockNotRunning     = errors.New("blind clock: not running")
+	ErrBlindClockNotPaused      = errors.New("blind clock: not paused")
+)
+
+type BlindClockStatus string
+
+const (
+	BlindClockStatus_Created  BlindClockStatus = "created"  // 尚未開始
+	BlindClockStatus_Running  BlindClockStatus = "running"  // 計時中
+	BlindClockStatus_Paused   BlindClockStatus = "paused"   // 暫停中
+	BlindClockStatus_Finished BlindClockStatus = "finished" // 最後一個等級已結束
+	BlindClockStatus_Stopped  BlindClockStatus = "stopped"  // 已停止
+)
+
+const (
+	BlindClockEvent_LevelStarted       = "LevelStarted"       // 進入新的盲注等級
+	BlindClockEvent_NextLevelAnnounced = "NextLevelAnnounced" // 預告下一個盲注等級
+	BlindClockEvent_Paused             = "Paused"             // 暫停
+	BlindClockEvent_Resumed            = "Resumed"            // 繼續
+	BlindClockEvent_Finished           = "Finished"           // 最後一個等級已結束
+	BlindClockEvent_Stopped            = "Stopped"            // 停止
+)
+
+type BlindClockOpt func(*blindClock)
+
+/*
+BlindClock 賽事盲注時鐘
+  - 依 BlindSchedule 計時升盲，並對同一賽事的所有桌次呼叫 UpdateBlind
+  - 中場休息結束時，因中場休息而暫停的桌次會自動開下一手
+  - 最後一個等級結束後停留在最後一個等級
+*/
+type BlindClock interface {
+	Start() error                 // 從第一個等級開始計時
+	Pause() error                 // 暫停計時 (保留剩餘時間)
+	Resume() error                // 繼續計時
+	Stop()                        // 停止計時
+	State() BlindClockState       // 目前時鐘狀態
+	ApplyTo(tableID string) error // 套用目前的盲注等級到指定桌次 (例如新開的桌次)
+}
+
+type BlindClockState struct {
+	CompetitionID    string           `json:"competition_id"`       // 賽事 ID
+	Status           BlindClockStatus `json:"status"`               // 時鐘狀態
+	LevelIndex       int              `json:"level_index"`          // 目前等級在 BlindSchedule 中的 index
+	CurrentLevel     BlindLevel       `json:"current_level"`        // 目前盲注等級
+	NextLevel        *BlindLevel      `json:"next_level,omitempty"` // 下一個盲注等級 (沒有則為 nil)
+	LevelEndAt       int64            `json:"level_end_at"`         // 目前等級結束時間 (Seconds，暫停中或不會結束時為 0)
+	RemainingSeconds int64            `json:"remaining_seconds"`    // 目前等級剩餘秒數 (不會結束時為 0)
+}
+
+// WithBlindClockUpdated 時鐘事件通知 (event 為 BlindClockEvent_*)
+func WithBlindClockUpdated(fn func(event string, state BlindClockState)) BlindClockOpt {
+	return func(c *blindClock) {
+		c.onUpdated = fn
+	}
+}
+
+// WithBlindClockErrorUpdated 套用盲注到桌次失敗時通知
+func WithBlindClockErrorUpdated(fn func(tableID string, err error)) BlindClockOpt {
+	return func(c *blindClock) {
+		c.onErrorUpdated = fn
+	}
+}
+
+// WithBlindClockAnnounceBefore 在等級結束前指定秒數預告下一個盲注等級
+func WithBlindClockAnnounceBefore(seconds int) BlindClockOpt {
+	return func(c *blindClock) {
+		c.announceBefore = time.Duration(seconds) * time.Second
+	}
+}
+
+type blindClock struct {
+	mu             sync.Mutex
+	manager        Manager
+	competitionID  string
+	schedule       BlindSchedule
+	status         BlindClockStatus
+	levelIdx       int
+	levelEndAt     time.Time
+	remaining      time.Duration // 暫停時保留的剩餘時間
+	announceBefore time.Duration
+	generation     int // 每次重新排程時遞增，用來忽略過期的計時器
+	timers         []*time.Timer
+	onUpdated      func(event string, state BlindClockState)
+	onErrorUpdated func(tableID string, err error)
+}
+
+func NewBlindClock(manager Manager, competitionID string, schedule BlindSchedule, opts ...BlindClockOpt) (BlindClock, error) {
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := &blindClock{
+		manager:        manager,
+		competitionID:  competitionID,
+		schedule:       schedule,
+		status:         BlindClockStatus_Created,
+		levelIdx:       0,
+		timers:         make([]*time.Timer, 0),
+		onUpdated:      func(event string, state BlindClockState) {},
+		onErrorUpdated: func(tableID string, err error) {},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+func (c *blindClock) Start() error {
+	c.mu.Lock()
+	if c.status != BlindClockStatus_Created {
+		c.mu.Unlock()
+		return ErrBlindClockAlreadyStarted
+	}
+
+	c.status = BlindClockStatus_Running
+	c.startLevel(0)
+	state := c.state()
+	c.mu.Unlock()
+
+	c.applyToTables(0)
+	c.onUpdated(BlindClockEvent_LevelStarted, state)
+	return nil
+}
+
+func (c *blindClock) Pause() error {
+	c.mu.Lock()
+	if c.status != BlindClockStatus_Running {
+		c.mu.Unlock()
+		return ErrBlindClockNotRunning
+	}
+
+	c.status = BlindClockStatus_Paused
+	c.remaining = 0
+	if !c.levelEndAt.IsZero() {
+		c.remaining = time.Until(c.levelEndAt)
+	}
+	c.stopTimers()
+	state := c.state()
+	c.mu.Unlock()
+
+	c.onUpdated(BlindClockEvent_Paused, state)
+	return nil
+}
+
+func (c *blindClock) Resume() error {
+	c.mu.Lock()
+	if c.status != BlindClockStatus_Paused {
+		c.mu.Unlock()
+		return ErrBlindClockNotPaused
+	}
+
+	c.status = BlindClockStatus_Running
+	c.levelEndAt = time.Time{}
+	if c.schedule.Levels[c.levelIdx].Duration > 0 {
+		c.scheduleLevelEnd(c.remaining)
+	}
+	c.remaining = 0
+	state := c.state()
+	c.mu.Unlock()
+
+	c.onUpdated(BlindClockEvent_Resumed, state)
+	return nil
+}
+
+func (c *blindClock) Stop() {
+	c.mu.Lock()
+	if c.status == BlindClockStatus_Stopped {
+		c.mu.Unlock()
+		return
+	}
+
+	c.status = BlindClockStatus_Stopped
+	c.stopTimers()
+	state := c.state()
+	c.mu.Unlock()
+
+	c.onUpdated(BlindClockEvent_Stopped, state)
+}
+
+func (c *blindClock) State() BlindClockState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.state()
+}
+
+func (c *blindClock) ApplyTo(tableID string) error {
+	c.mu.Lock()
+	blind := c.schedule.TableBlindState(c.levelIdx)
+	c.mu.Unlock()
+
+	return c.updateTableBlind(tableID, blind)
+}
+
+// updateTableBlind 更新桌次盲注 (等級未指定前注模式時沿用桌次設定)
+func (c *blindClock) updateTableBlind(tableID string, blind TableBlindState) error {
+	if err := c.manager.UpdateBlind(tableID, blind.Level, blind.Ante, blind.Dealer, blind.SB, blind.BB); err != nil {
+		return err
+	}
+
+	if blind.AnteMode == "" {
+		return nil
+	}
+
+	return c.manager.UpdateAnteMode(tableID, blind.AnteMode)
+}
+
+// startLevel 進入指定等級並排程結束時間 (呼叫前需持有鎖)
+func (c *blindClock) startLevel(levelIdx int) {
+	c.levelIdx = levelIdx
+	c.levelEndAt = time.Time{}
+	c.stopTimers()
+
+	if duration := c.schedule.Levels[levelIdx].Duration; duration > 0 {
+		c.scheduleLevelEnd(time.Duration(duration) * time.Second)
+	}
+}
+
+// scheduleLevelEnd 排程目前等級的預告與結束 (呼叫前需持有鎖)
+func (c *blindClock) scheduleLevelEnd(remaining time.Duration) {
+	c.stopTimers()
+	c.levelEndAt = time.Now().Add(remaining)
+	generation := c.generation
+
+	if c.announceBefore > 0 && c.schedule.NextLevel(c.levelIdx) != nil {
+		announceAfter := remaining - c.announceBefore
+		if announceAfter < 0 {
+			announceAfter = 0
+		}
+		c.timers = append(c.timers, time.AfterFunc(announceAfter, func() {
+			c.announce(generation)
+		}))
+	}
+
+	c.timers = append(c.timers, time.AfterFunc(remaining, func() {
+		c.advance(generation)
+	}))
+}
+
+func (c *blindClock) stopTimers() {
+	for _, timer := range c.timers {
+		timer.Stop()
+	}
+	c.timers = make([]*time.Timer, 0)
+	c.generation++
+}
+
+func (c *blindClock) announce(generation int) {
+	c.mu.Lock()
+	if c.status != BlindClockStatus_Running || c.generation != generation {
+		c.mu.Unlock()
+		return
+	}
+	state := c.state()
+	c.mu.Unlock()
+
+	c.onUpdated(BlindClockEvent_NextLevelAnnounced, state)
+}
+
+/*
+advance 目前等級結束
+  - 進入下一個等級並套用到所有桌次
+  - 沒有下一個等級時停留在目前等級
+*/
+func (c *blindClock) advance(generation int) {
+	c.mu.Lock()
+	if c.status != BlindClockStatus_Running || c.generation != generation {
+		c.mu.Unlock()
+		return
+	}
+
+	if c.schedule.NextLevel(c.levelIdx) == nil {
+		c.status = BlindClockStatus_Finished
+		c.stopTimers()
+		c.levelEndAt = time.Time{}
+		state := c.state()
+		c.mu.Unlock()
+
+		c.onUpdated(BlindClockEvent_Finished, state)
+		return
+	}
+
+	levelIdx := c.levelIdx + 1
+	c.startLevel(levelIdx)
+	state := c.state()
+	c.mu.Unlock()
+
+	c.applyToTables(levelIdx)
+	c.onUpdated(BlindClockEvent_LevelStarted, state)
+}
+
+// applyToTables 套用盲注等級到此賽事所有未關閉的桌次
+func (c *blindClock) applyToTables(levelIdx int) {
+	blind := c.schedule.TableBlindState(levelIdx)
+	for _, tableEngine := range c.manager.GetTableEngines() {
+		table, err := snapshotTable(tableEngine)
+		if err != nil || table.Meta.CompetitionID != c.competitionID || table.State.Status == TableStateStatus_TableClosed {
+			continue
+		}
+
+		if err := c.updateTableBlind(table.ID, blind); err != nil {
+			c.onErrorUpdated(table.ID, err)
+			continue
+		}
+
+		// 中場休息結束 (依套用盲注後的桌次判斷是否開下一手)
+		if !blind.IsBreaking() {
+			updated, err := snapshotTable(tableEngine)
+			if err == nil {
+				err = resumeTableGame(c.manager, updated)
+			}
+			if err != nil {
+				c.onErrorUpdated(table.ID, err)
+			}
+		}
+	}
+}
+
+// snapshotTable 經由快照讀取桌次 (時鐘在計時器的 goroutine 觸發，不可直接讀取桌次)
+func snapshotTable(tableEngine TableEngine) (*Table, error) {
+	snapshot, err := tableEngine.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Table, nil
+}
+
+func (c *blindClock) state() BlindClockState {
+	state := BlindClockState{
+		CompetitionID: c.competitionID,
+		Status:        c.status,
+		LevelIndex:    c.levelIdx,
+		CurrentLevel:  c.schedule.Levels[c.levelIdx],
+		NextLevel:     c.schedule.NextLevel(c.levelIdx),
+	}
+
+	switch {
+	case c.status == BlindClockStatus_Paused:
+		state.RemainingSeconds = int64(c.remaining.Round(time.Second) / time.Second)
+	case c.status == BlindClockStatus_Running && !c.levelEndAt.IsZero():
+		state.LevelEndAt = c.levelEndAt.Unix()
+		if remaining := time.Until(c.levelEndAt); remaining > 0 {
+			state.RemainingSeconds = int64(remaining.Round(time.Second) / time.Second)
+		}
+	}
+
+	return state
+}
@@ -0,0 +1,111 @@
+package pokertable
+
+import (
+	"errors"
+)
+
+var (
+	ErrBlindScheduleEmpty        = errors.New("blind schedule: no levels")
+	ErrBlindScheduleInvalidLevel = errors.New("blind schedule: invalid level")
+)
+
+const (
+	BlindLevel_Break = -1 // 中場休息的盲注等級
+)
+
+type BlindLevel struct {
+	Level    int    `json:"level"`               // 盲注等級 (-1 表示中場休息)
+	Ante     int64  `json:"ante"`                // 前注籌碼量
+	AnteMode string `json:"ante_mode,omitempty"` // 前注模式 (空值表示沿用桌次設定)
+	Dealer   int64  `json:"dealer"`              // 庄位籌碼量
+	SB       int64  `json:"sb"`                  // 小盲籌碼量
+	BB       int64  `json:"bb"`                  // 大盲籌碼量
+	Duration int    `json:"duration"`            // 持續秒數 (<= 0 表示不會結束)
+}
+
+type BlindSchedule struct {
+	Levels []BlindLevel `json:"levels"` // 依序進行的盲注等級 (含中場休息)
+}
+
+// NewBreakLevel 建立中場休息 (桌次沿用上一個等級的籌碼量)
+func NewBreakLevel(duration int) BlindLevel {
+	return BlindLevel{
+		Level:    BlindLevel_Break,
+		Duration: duration,
+	}
+}
+
+func (l BlindLevel) IsBreak() bool {
+	return l.Level == BlindLevel_Break
+}
+
+/*
+Validate 檢查盲注結構
+  - 至少一個盲注等級
+  - 一般等級: Level > 0，BB >= SB > 0，Ante 與 Dealer 不可為負數，AnteMode 需為支援的前注模式
+  - 中場休息必須有持續秒數，且不能是第一個或最後一個等級
+*/
+func (s BlindSchedule) Validate() error {
+	if len(s.Levels) == 0 {
+		return ErrBlindScheduleEmpty
+	}
+
+	for idx, level := range s.Levels {
+		if level.IsBreak() {
+			if level.Duration <= 0 || idx == 0 || idx == len(s.Levels)-1 {
+				return ErrBlindScheduleInvalidLevel
+			}
+			continue
+		}
+
+		if level.Level <= 0 || level.Ante < 0 || level.Dealer < 0 || level.SB <= 0 || level.BB < level.SB || !isValidAnteMode(level.AnteMode) {
+			return ErrBlindScheduleInvalidLevel
+		}
+	}
+
+	return nil
+}
+
+/*
+NextLevel 取得指定等級之後的下一個盲注等級
+  - 沒有下一個等級時回傳 nil
+*/
+func (s BlindSchedule) NextLevel(levelIdx int) *BlindLevel {
+	if levelIdx+1 >= len(s.Levels) {
+		return nil
+	}
+
+	level := s.Levels[levelIdx+1]
+	return &level
+}
+
+/*
+TableBlindState 取得桌次要套用的盲注資訊
+  - 中場休息沿用上一個一般等級的籌碼量與前注模式
+*/
+func (s BlindSchedule) TableBlindState(levelIdx int) TableBlindState {
+	level := s.Levels[levelIdx]
+	blind := TableBlindState{
+		Level:    level.Level,
+		Ante:     level.Ante,
+		AnteMode: level.AnteMode,
+		Dealer:   level.Dealer,
+		SB:       level.SB,
+		BB:       level.BB,
+	}
+
+	if level.IsBreak() {
+		for i := levelIdx - 1; i >= 0; i-- {
+			if prev := s.Levels[i]; !prev.IsBreak() {
+				blind.Ante = prev.Ante
+				blind.AnteMode = prev.AnteMode
+				blind.Dealer = prev.Dealer
+				blind.SB = prev.SB
+				blind.BB = prev.BB
+				break
+			}
+		}
+	}
+
+	return blind
+}
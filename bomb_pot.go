@@ -0,0 +1,86 @@
+package pokertable
+
+import "errors"
+
+var (
+	ErrTableBombPotNotAllowed = errors.New("table: bomb pot is not allowed")
+	ErrTableBombPotVoteClosed = errors.New("table: bomb pot vote is closed")
+)
+
+/*
+isValidBombPotSetting 檢查 Bomb Pot 設定
+  - 只開放現金桌，且需設定定期 (Interval) 或開放投票 (VoteEnabled) 其中一種觸發方式
+*/
+func isValidBombPotSetting(meta TableMeta) bool {
+	setting := meta.BombPot
+	if setting.Amount < 0 || setting.Interval < 0 {
+		return false
+	}
+
+	if setting.Amount == 0 {
+		return setting.Interval == 0 && !setting.VoteEnabled
+	}
+
+	return meta.Mode == CompetitionMode_Cash && (setting.Interval > 0 || setting.VoteEnabled)
+}
+
+/*
+calcGameBombPot 計算本手 Bomb Pot 每位玩家支付的籌碼量 (0 表示非 Bomb Pot)
+  - 適用時機: 開局時 (GameCount 已更新)
+  - 定期: 每 Interval 手進行一次
+  - 投票: 本手所有參與的玩家皆已投票同意
+*/
+func (te *tableEngine) calcGameBombPot(table *Table) int64 {
+	setting := table.Meta.BombPot
+	if !isValidBombPotSetting(table.Meta) || setting.Amount == 0 {
+		return 0
+	}
+
+	if setting.Interval > 0 && table.State.GameCount%setting.Interval == 0 {
+		return setting.Amount
+	}
+
+	if setting.VoteEnabled && te.isBombPotVotePassed(table) {
+		return setting.Amount
+	}
+
+	return 0
+}
+
+// isBombPotVotePassed 本手參與的玩家是否皆已投票同意 Bomb Pot (每次設定開局時會清除投票)
+func (te *tableEngine) isBombPotVotePassed(table *Table) bool {
+	state := te.ogm.GetState()
+	if len(table.State.GamePlayerIndexes) < 2 {
+		return false
+	}
+
+	for _, playerIdx := range table.State.GamePlayerIndexes {
+		participant, exist := state.Participants[table.State.PlayerStates[playerIdx].PlayerID]
+		if !exist || !participant.IsBombPotVoted {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+isBombPotVoteOpen 是否可投票 (SetUpTableGame 之後，開局之前)
+  - 第一手以目前 GameCount 設定開局，之後每手以 GameCount+1 設定開局
+*/
+func (te *tableEngine) isBombPotVoteOpen() bool {
+	switch te.table.State.Status {
+	case TableStateStatus_TableGameOpened, TableStateStatus_TableGamePlaying, TableStateStatus_TableGameSettled:
+		return false
+	}
+
+	if te.table.State.GameState != nil {
+		return false
+	}
+
+	setUpGameCount := te.ogm.GetState().GameCount
+	if te.table.State.GameCount == 0 {
+		return setUpGameCount == 0 && te.table.State.StartAt != UnsetValue
+	}
+	return setUpGameCount > te.table.State.GameCount
+}
@@ -14,22 +14,40 @@ const (
 	CompetitionRule_ShortDeck = "short_deck" // 短牌
 	CompetitionRule_Omaha     = "omaha"      // 奧瑪哈
 
+	// BettingStructure
+	BettingStructure_NoLimit    = "no_limit"    // 無限注
+	BettingStructure_PotLimit   = "pot_limit"   // 底池限注
+	BettingStructure_FixedLimit = "fixed_limit" // 固定限注
+
+	// AnteMode
+	AnteMode_PerPlayer = "per_player" // 每位玩家各自支付前注
+	AnteMode_BigBlind  = "big_blind"  // 大盲代付全桌前注 (BB ante)
+	AnteMode_Button    = "button"     // 按鈕位代付全桌前注 (button ante)
+
+	// StraddleMode
+	StraddleMode_UTG         = "utg"         // 只有槍口位可以 Straddle
+	StraddleMode_Mississippi = "mississippi" // 大小盲以外的玩家皆可 Straddle (mississippi straddle)
+
 	// Position
-	Position_Unknown = "unknown"
-	Position_Dealer  = "dealer"
-	Position_SB      = "sb"
-	Position_BB      = "bb"
-	Position_UG      = "ug"
-	Position_UG2     = "ug2"
-	Position_UG3     = "ug3"
-	Position_MP      = "mp"
-	Position_MP2     = "mp2"
-	Position_HJ      = "hj"
-	Position_CO      = "co"
+	Position_Unknown  = "unknown"
+	Position_Dealer   = "dealer"
+	Position_SB       = "sb"
+	Position_BB       = "bb"
+	Position_UG       = "ug"
+	Position_UG2      = "ug2"
+	Position_UG3      = "ug3"
+	Position_MP       = "mp"
+	Position_MP2      = "mp2"
+	Position_HJ       = "hj"
+	Position_CO       = "co"
+	Position_Straddle = "straddle"
 
 	// Action
-	Action_Ready = "ready"
-	Action_Pay   = "pay"
+	Action_Ready         = "ready"
+	Action_Pay           = "pay"
+	Action_Pass          = "pass"
+	Action_RunItMultiple = "run_it_multiple"
+	Action_Straddle      = "straddle"
 
 	// Wager Action
 	WagerAction_Fold  = "fold"
@@ -0,0 +1,135 @@
+package pokertable
+
+import (
+	"github.com/thoas/go-funk"
+)
+
+/*
+newGameBlindPosts 計算本手錯過盲注玩家的補盲注
+  - 只有現金桌需要補盲注
+  - 錯過大盲: 補活大盲 (計入本輪下注)
+  - 錯過小盲: 補死小盲 (結算時併入主池)
+  - 本手輪到盲注位置的玩家不需補盲注
+  - 籌碼不足時補剩餘籌碼 (優先補活大盲)，仍視為已補盲注
+*/
+func (te *tableEngine) newGameBlindPosts() map[int]GameBlindPost {
+	posts := make(map[int]GameBlindPost)
+	if te.table.Meta.Mode != CompetitionMode_Cash {
+		return posts
+	}
+
+	blind := te.table.State.BlindState
+	for gamePlayerIdx, playerIdx := range te.table.State.GamePlayerIndexes {
+		player := te.table.State.PlayerStates[playerIdx]
+		if !player.MissedSB && !player.MissedBB {
+			continue
+		}
+
+		if funk.Contains(player.Positions, Position_SB) || funk.Contains(player.Positions, Position_BB) {
+			continue
+		}
+
+		// 扣除前注後的可用籌碼 (代付全桌前注時其他玩家不需支付前注)
+		available := player.Bankroll
+		if !blind.IsTableAnte() {
+			available -= blind.Ante
+		}
+		if available <= 0 {
+			continue
+		}
+
+		post := GameBlindPost{}
+		if player.MissedBB {
+			post.Live = blind.BB
+			if post.Live > available {
+				post.Live = available
+			}
+		}
+		if player.MissedSB {
+			post.Dead = blind.SB
+			if post.Dead > available-post.Live {
+				post.Dead = available - post.Live
+			}
+		}
+		posts[gamePlayerIdx] = post
+	}
+
+	return posts
+}
+
+/*
+clearPostedMissedBlinds 記錄本手補盲注，並清除補盲注玩家的錯過盲注紀錄
+  - 適用時機: 開始本手遊戲前
+  - 只有代付全桌前注的玩家不清除錯過盲注紀錄
+*/
+func (te *tableEngine) clearPostedMissedBlinds(posts map[int]GameBlindPost) {
+	te.table.State.GameBlindPosts = make(map[int]GameBlindPost)
+	for gamePlayerIdx, post := range posts {
+		playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gamePlayerIdx)
+		if playerIdx == UnsetValue {
+			continue
+		}
+
+		if post.Live > 0 || post.Dead > 0 {
+			player := te.table.State.PlayerStates[playerIdx]
+			if err := te.sm.ClearMissedBlinds(player.PlayerID); err != nil {
+				te.emitErrorEvent("clearPostedMissedBlinds#ClearMissedBlinds", player.PlayerID, err)
+				continue
+			}
+
+			player.MissedSB = false
+			player.MissedBB = false
+		}
+		te.table.State.GameBlindPosts[gamePlayerIdx] = post
+	}
+}
+
+/*
+settleDeadBlinds 將本手死盲與代付全桌前注併入主池
+  - 死盲與代付前注由主池贏家平分，餘數給第一位贏家 (多次發牌時再隨主池依各次公牌重新分配)
+  - 補死盲或代付前注玩家的輸贏籌碼需扣除該籌碼
+*/
+func (te *tableEngine) settleDeadBlinds() {
+	result := te.table.State.GameState.Result
+	if result == nil || len(result.Pots) == 0 || len(result.Pots[0].Winners) == 0 {
+		return
+	}
+
+	deadBlinds := int64(0)
+	for gamePlayerIdx, post := range te.table.State.GameBlindPosts {
+		dead := post.Dead + post.Ante
+		if dead <= 0 {
+			continue
+		}
+
+		deadBlinds += dead
+		for _, player := range result.Players {
+			if player.Idx == gamePlayerIdx {
+				player.Changed -= dead
+			}
+		}
+	}
+
+	if deadBlinds == 0 {
+		return
+	}
+
+	mainPot := result.Pots[0]
+	mainPot.Total += deadBlinds
+	based := deadBlinds / int64(len(mainPot.Winners))
+	remainder := deadBlinds % int64(len(mainPot.Winners))
+	for i, winner := range mainPot.Winners {
+		reward := based
+		if int64(i) < remainder {
+			reward += 1
+		}
+
+		winner.Withdraw += reward
+		for _, player := range result.Players {
+			if player.Idx == winner.Idx {
+				player.Final += reward
+				player.Changed += reward
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package equity
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokerface/combination"
+)
+
+var (
+	ErrNotEnoughPlayers = errors.New("equity: not enough players")
+	ErrInvalidHoleCards = errors.New("equity: invalid hole cards")
+	ErrInvalidBoard     = errors.New("equity: invalid board")
+	ErrDuplicateCards   = errors.New("equity: duplicate cards")
+	ErrNotEnoughCards   = errors.New("equity: not enough cards in deck")
+)
+
+const (
+	// 窮舉剩餘公牌組合數上限，超過時改用蒙地卡羅模擬
+	DefaultMaxEnumerations = 50000
+
+	// 蒙地卡羅模擬次數
+	DefaultIterations = 10000
+)
+
+type Calculator interface {
+	Calculate(holeCards [][]string, board []string) (*Result, error)
+}
+
+type CalculatorOpt func(*calculator)
+
+type calculator struct {
+	deck                   []string
+	combinationPowers      combination.PowerRankings
+	requiredHoleCardsCount int
+	maxEnumerations        int
+	iterations             int
+	random                 *rand.Rand
+}
+
+type Result struct {
+	Players      []PlayerEquity `json:"players"`       // 各玩家勝率 (順序同傳入的底牌)
+	Samples      int            `json:"samples"`       // 計算的公牌組合數
+	IsExhaustive bool           `json:"is_exhaustive"` // 是否為窮舉 (否則為蒙地卡羅模擬)
+}
+
+type PlayerEquity struct {
+	Win    float64 `json:"win"`    // 獨贏機率
+	Tie    float64 `json:"tie"`    // 平手機率
+	Equity float64 `json:"equity"` // 期望勝率 (平手時依平手人數平分)
+}
+
+func NewCalculator(opts ...CalculatorOpt) Calculator {
+	c := &calculator{
+		deck:                   pokerface.NewStandardDeckCards(),
+		combinationPowers:      combination.CombinationPowerStandard,
+		requiredHoleCardsCount: 0,
+		maxEnumerations:        DefaultMaxEnumerations,
+		iterations:             DefaultIterations,
+		random:                 rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithDeck 指定整副牌 (短牌使用 pokerface.NewShortDeckCards)
+func WithDeck(cards []string) CalculatorOpt {
+	return func(c *calculator) {
+		c.deck = cards
+	}
+}
+
+// WithCombinationPowers 指定牌型大小順序 (同 pokerface GameState.Meta.CombinationPowers)
+func WithCombinationPowers(pr combination.PowerRankings) CalculatorOpt {
+	return func(c *calculator) {
+		c.combinationPowers = pr
+	}
+}
+
+// WithRequiredHoleCardsCount 指定組成牌型必須使用的底牌數 (奧瑪哈為 2)
+func WithRequiredHoleCardsCount(count int) CalculatorOpt {
+	return func(c *calculator) {
+		c.requiredHoleCardsCount = count
+	}
+}
+
+// WithMaxEnumerations 指定窮舉剩餘公牌組合數上限
+func WithMaxEnumerations(count int) CalculatorOpt {
+	return func(c *calculator) {
+		c.maxEnumerations = count
+	}
+}
+
+// WithIterations 指定蒙地卡羅模擬次數
+func WithIterations(count int) CalculatorOpt {
+	return func(c *calculator) {
+		c.iterations = count
+	}
+}
+
+// WithRandom 指定蒙地卡羅模擬使用的亂數產生器
+func WithRandom(r *rand.Rand) CalculatorOpt {
+	return func(c *calculator) {
+		c.random = r
+	}
+}
+
+/*
+Calculate 計算各玩家在剩餘公牌下的勝率
+  - holeCards: 各玩家底牌 (至少兩位玩家)
+  - board: 已發出的公牌 (0 ~ 5 張)
+  - 剩餘公牌組合數不超過上限時窮舉 (不論是否為奧瑪哈)，否則使用蒙地卡羅模擬
+*/
+func (c *calculator) Calculate(holeCards [][]string, board []string) (*Result, error) {
+	if len(holeCards) < 2 {
+		return nil, ErrNotEnoughPlayers
+	}
+
+	if len(board) > 5 {
+		return nil, ErrInvalidBoard
+	}
+
+	// 已知的牌不可重複
+	knownCards := make(map[string]bool)
+	for _, cards := range append(append([][]string{}, holeCards...), board) {
+		for _, card := range cards {
+			if knownCards[card] {
+				return nil, ErrDuplicateCards
+			}
+			knownCards[card] = true
+		}
+	}
+
+	for _, cards := range holeCards {
+		if len(cards) == 0 || len(cards) < c.requiredHoleCardsCount {
+			return nil, ErrInvalidHoleCards
+		}
+	}
+
+	remainingCards := make([]string, 0, len(c.deck))
+	for _, card := range c.deck {
+		if !knownCards[card] {
+			remainingCards = append(remainingCards, card)
+		}
+	}
+
+	needCount := 5 - len(board)
+	if needCount > len(remainingCards) {
+		return nil, ErrNotEnoughCards
+	}
+
+	result := &Result{
+		Players: make([]PlayerEquity, len(holeCards)),
+	}
+
+	if countCombinations(len(remainingCards), needCount) <= c.maxEnumerations {
+		result.IsExhaustive = true
+		enumerateCombinations(remainingCards, needCount, func(cards []string) {
+			c.evaluate(result, holeCards, append(append([]string{}, board...), cards...))
+		})
+	} else {
+		cards := append([]string{}, remainingCards...)
+		for i := 0; i < c.iterations; i++ {
+			// 只需洗出前 needCount 張
+			for j := 0; j < needCount; j++ {
+				k := j + c.random.Intn(len(cards)-j)
+				cards[j], cards[k] = cards[k], cards[j]
+			}
+			c.evaluate(result, holeCards, append(append([]string{}, board...), cards[:needCount]...))
+		}
+	}
+
+	if result.Samples > 0 {
+		for i := range result.Players {
+			result.Players[i].Win /= float64(result.Samples)
+			result.Players[i].Tie /= float64(result.Samples)
+			result.Players[i].Equity /= float64(result.Samples)
+		}
+	}
+
+	return result, nil
+}
+
+// evaluate 以一組完整公牌比較各玩家牌力並累計勝負次數
+func (c *calculator) evaluate(result *Result, holeCards [][]string, board []string) {
+	winners := make([]int, 0)
+	best := uint64(0)
+	for idx, cards := range holeCards {
+		score := c.bestScore(cards, board)
+		if len(winners) == 0 || score > best {
+			best = score
+			winners = []int{idx}
+		} else if score == best {
+			winners = append(winners, idx)
+		}
+	}
+
+	for _, idx := range winners {
+		if len(winners) == 1 {
+			result.Players[idx].Win++
+		} else {
+			result.Players[idx].Tie++
+		}
+		result.Players[idx].Equity += 1 / float64(len(winners))
+	}
+	result.Samples++
+}
+
+func (c *calculator) bestScore(holeCards []string, board []string) uint64 {
+	best := uint64(0)
+	for _, cards := range combination.GetAllPossibleCombinations(board, holeCards, c.requiredHoleCardsCount) {
+		if ps := combination.CalculatePower(c.combinationPowers, cards); ps.Score > best {
+			best = ps.Score
+		}
+	}
+	return best
+}
+
+// countCombinations 計算 C(n, k)，超過 int 範圍前就會超過窮舉上限
+func countCombinations(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+
+	count := 1
+	for i := 0; i < k; i++ {
+		count = count * (n - i) / (i + 1)
+	}
+	return count
+}
+
+// enumerateCombinations 依序列出從 cards 取 k 張的所有組合
+func enumerateCombinations(cards []string, k int, fn func([]string)) {
+	picked := make([]string, k)
+	var pick func(start, depth int)
+	pick = func(start, depth int) {
+		if depth == k {
+			fn(picked)
+			return
+		}
+
+		for i := start; i <= len(cards)-(k-depth); i++ {
+			picked[depth] = cards[i]
+			pick(i+1, depth+1)
+		}
+	}
+	pick(0, 0)
+}
@@ -0,0 +1,90 @@
+package equity
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculate_River(t *testing.T) {
+	c := NewCalculator()
+
+	result, err := c.Calculate([][]string{{"SA", "HA"}, {"SK", "HK"}}, []string{"DA", "CK", "S2", "H7", "D9"})
+	assert.NoError(t, err)
+	assert.True(t, result.IsExhaustive)
+	assert.Equal(t, 1, result.Samples)
+	assert.Equal(t, PlayerEquity{Win: 1, Tie: 0, Equity: 1}, result.Players[0])
+	assert.Equal(t, PlayerEquity{Win: 0, Tie: 0, Equity: 0}, result.Players[1])
+}
+
+func TestCalculate_Turn_Exhaustive(t *testing.T) {
+	c := NewCalculator()
+
+	// KK 只剩最後一張 K 可以成四條
+	result, err := c.Calculate([][]string{{"SA", "HA"}, {"SK", "HK"}}, []string{"DA", "CK", "S2", "H7"})
+	assert.NoError(t, err)
+	assert.True(t, result.IsExhaustive)
+	assert.Equal(t, 44, result.Samples)
+	assert.InDelta(t, 43.0/44.0, result.Players[0].Win, 1e-9)
+	assert.InDelta(t, 1.0/44.0, result.Players[1].Win, 1e-9)
+	assert.InDelta(t, 1.0, result.Players[0].Equity+result.Players[1].Equity, 1e-9)
+}
+
+func TestCalculate_Tie(t *testing.T) {
+	c := NewCalculator()
+
+	// 公牌同花大順，平分底池
+	result, err := c.Calculate([][]string{{"H2", "D3"}, {"C4", "D5"}}, []string{"SA", "SK", "SQ", "SJ", "ST"})
+	assert.NoError(t, err)
+	for _, player := range result.Players {
+		assert.Equal(t, PlayerEquity{Win: 0, Tie: 1, Equity: 0.5}, player)
+	}
+}
+
+func TestCalculate_Preflop_MonteCarlo(t *testing.T) {
+	c := NewCalculator(WithIterations(3000), WithRandom(rand.New(rand.NewSource(1))))
+
+	// 剩餘公牌組合數超過窮舉上限，改用蒙地卡羅模擬 (AA 對 KK 約 82%)
+	result, err := c.Calculate([][]string{{"SA", "HA"}, {"SK", "HK"}}, []string{})
+	assert.NoError(t, err)
+	assert.False(t, result.IsExhaustive)
+	assert.Equal(t, 3000, result.Samples)
+	assert.InDelta(t, 0.82, result.Players[0].Equity, 0.04)
+	assert.InDelta(t, 1.0, result.Players[0].Equity+result.Players[1].Equity, 1e-9)
+}
+
+func TestCalculate_Omaha_Exhaustive(t *testing.T) {
+	c := NewCalculator(WithRequiredHoleCardsCount(2))
+
+	// 奧瑪哈必須使用兩張底牌: 公牌四張紅心，只有一張紅心底牌的玩家不能成同花
+	result, err := c.Calculate([][]string{{"HA", "HK", "C2", "D3"}, {"H2", "SQ", "CQ", "DJ"}}, []string{"H5", "H7", "H9", "HJ"})
+	assert.NoError(t, err)
+	assert.True(t, result.IsExhaustive)
+	assert.Equal(t, 40, result.Samples)
+	assert.Equal(t, 1.0, result.Players[0].Win)
+}
+
+func TestCalculate_Omaha_MonteCarlo(t *testing.T) {
+	c := NewCalculator(WithRequiredHoleCardsCount(2), WithIterations(500), WithRandom(rand.New(rand.NewSource(1))))
+
+	// 翻牌前剩餘公牌組合數超過窮舉上限
+	result, err := c.Calculate([][]string{{"HA", "HK", "C2", "D3"}, {"H2", "SQ", "CQ", "DJ"}}, []string{})
+	assert.NoError(t, err)
+	assert.False(t, result.IsExhaustive)
+	assert.Equal(t, 500, result.Samples)
+	assert.InDelta(t, 1.0, result.Players[0].Equity+result.Players[1].Equity, 1e-9)
+}
+
+func TestCalculate_InvalidInput(t *testing.T) {
+	c := NewCalculator()
+
+	_, err := c.Calculate([][]string{{"SA", "HA"}}, []string{})
+	assert.ErrorIs(t, err, ErrNotEnoughPlayers)
+
+	_, err = c.Calculate([][]string{{"SA", "HA"}, {"SA", "HK"}}, []string{})
+	assert.ErrorIs(t, err, ErrDuplicateCards)
+
+	_, err = c.Calculate([][]string{{"SA", "HA"}, {"SK", "HK"}}, []string{"S2", "S3", "S4", "S5", "S6", "S7"})
+	assert.ErrorIs(t, err, ErrInvalidBoard)
+}
@@ -2,7 +2,6 @@ package pokertable
 
 import (
 	"fmt"
-	"time"
 )
 
 const (
@@ -11,50 +10,166 @@ const (
 	TableStateEvent_GameUpdated   = "GameUpdated"
 	TableStateEvent_GameSettled   = "GameSettled"
 	TableStateEvent_PlayersLeave  = "PlayersLeave"
+	TableStateEvent_Restored      = "Restored"
 )
 
+/*
+emitEvent 更新桌次並發出桌次更新事件 (需持有 te.lock)
+  - 監聽者收到的是桌次複本，不會與之後的桌次更新互相影響
+*/
 func (te *tableEngine) emitEvent(eventName string, playerID string) {
+	te.publishTableUpdate(te.refreshTable(eventName, playerID))
+}
+
+// tableUpdate 桌次更新 (發出事件前於 te.lock 內產生)
+type tableUpdate struct {
+	eventName string
+	playerID  string
+	table     *Table
+	patch     *TablePatch
+}
+
+/*
+refreshTable 更新桌次序號並記錄、保存與產生差異 (需持有 te.lock)
+  - 回傳的桌次更新可在釋放 te.lock 後再發出 (publishTableUpdate)，監聽者可以直接執行桌次動作
+*/
+func (te *tableEngine) refreshTable(eventName string, playerID string) *tableUpdate {
 	// refresh table
-	te.table.UpdateAt = time.Now().Unix()
+	te.table.UpdateAt = te.clock.Now().Unix()
 	te.table.UpdateSerial++
 
-	// emit event
-	fmt.Printf("->[c: %s][t: %s][#%d][%d][%s] emit Event: %s\n", te.table.Meta.CompetitionID, te.table.ID, te.table.UpdateSerial, te.table.State.GameCount, playerID, eventName)
-	te.onTableUpdated(te.table)
+	te.recordTable(eventName, playerID)
+	te.persistTable()
+
+	return &tableUpdate{
+		eventName: eventName,
+		playerID:  playerID,
+		table:     te.cloneTable(),
+		patch:     te.encodeTablePatch(),
+	}
+}
+
+// publishTableUpdate 發出桌次更新事件
+func (te *tableEngine) publishTableUpdate(update *tableUpdate) {
+	table := update.table
+	fmt.Printf("->[c: %s][t: %s][#%d][%d][%s] emit Event: %s\n", table.Meta.CompetitionID, table.ID, table.UpdateSerial, table.State.GameCount, update.playerID, update.eventName)
+
+	// 先發出差異，避免監聽器內觸發的下一次更新讓差異順序錯亂
+	if update.patch != nil {
+		te.onTablePatched(*update.patch)
+		te.publishEvent(TableEvent{Type: TableEventType_TablePatched, Patch: update.patch})
+	}
+	te.onTableUpdated(table)
+	te.publishEvent(TableEvent{Type: TableEventType_TableUpdated, Table: table})
+}
+
+// cloneTable 發送給監聽者的桌次複本 (需持有 te.lock)
+func (te *tableEngine) cloneTable() *Table {
+	table, err := te.table.Clone()
+	if err != nil {
+		fmt.Printf("->[t: %s] clone table error: %v\n", te.table.ID, err)
+		return te.table
+	}
+	return table
 }
 
 // TODO: replace err(error) with errMsg(string)
 func (te *tableEngine) emitErrorEvent(eventName string, playerID string, err error) {
 	fmt.Printf("->[c: %s][t: %s][#%d][%d][%s] emit ERROR Event: %s, Error: %v\n", te.table.Meta.CompetitionID, te.table.ID, te.table.UpdateSerial, te.table.State.GameCount, playerID, eventName, err)
 	te.onTableErrorUpdated(te.table, err)
+	te.publishEvent(TableEvent{Type: TableEventType_TableErrorUpdated, Table: te.table, Error: err})
 }
 
+// emitTableStateEvent 發出桌次狀態事件 (需持有 te.lock)
 func (te *tableEngine) emitTableStateEvent(eventName string) {
+	te.publishTableStateEvent(eventName, te.cloneTable())
+}
+
+// publishTableStateEvent 發出桌次狀態事件 (table 為 te.lock 內產生的桌次複本)
+func (te *tableEngine) publishTableStateEvent(eventName string, table *Table) {
 	// emit event
 	// fmt.Printf("->emit state Event: %s\n", eventName)
-	te.onTableStateUpdated(eventName, te.table)
+	te.onTableStateUpdated(eventName, table)
+	te.publishEvent(TableEvent{Type: TableEventType_TableStateUpdated, Table: table, StateEvent: eventName})
 }
 
 func (te *tableEngine) emitTablePlayerStateEvent(player *TablePlayerState) {
 	// emit event
 	// fmt.Printf("->emit player state Event: %s\n", player.PlayerID)
 	te.onTablePlayerStateUpdated(te.table.Meta.CompetitionID, te.table.ID, player)
+	te.publishEvent(TableEvent{Type: TableEventType_TablePlayerStateUpdated, PlayerState: player})
 }
 
 func (te *tableEngine) emitTablePlayerReservedEvent(player *TablePlayerState) {
 	// emit event
 	// fmt.Printf("->emit player reserved Event: %s\n", player.PlayerID)
 	te.onTablePlayerReserved(te.table.Meta.CompetitionID, te.table.ID, player)
+	te.publishEvent(TableEvent{Type: TableEventType_TablePlayerReserved, PlayerState: player})
 }
 
 func (te *tableEngine) emitGamePlayerActionEvent(gameAction TablePlayerGameAction) {
 	// emit event
 	// fmt.Printf("->emit player game action Event: %s %s %d\n", gameAction.PlayerID, gameAction.Action, gameAction.Chips)
 	te.onGamePlayerActionUpdated(gameAction)
+	te.publishEvent(TableEvent{Type: TableEventType_GamePlayerActionUpdated, GameAction: &gameAction})
+}
+
+func (te *tableEngine) emitTablePlayerTimeBankUsedEvent(player *TablePlayerState, duration int) {
+	// emit event
+	// fmt.Printf("->emit player time bank used Event: %s %d\n", player.PlayerID, duration)
+	te.onTablePlayerTimeBankUsed(te.table.Meta.CompetitionID, te.table.ID, player, duration)
+	te.publishEvent(TableEvent{Type: TableEventType_TablePlayerTimeBankUsed, PlayerState: player, Duration: duration})
+}
+
+func (te *tableEngine) emitTablePlayerActionTimeoutEvent(player *TablePlayerState) {
+	// emit event
+	// fmt.Printf("->emit player action timeout Event: %s\n", player.PlayerID)
+	te.onTablePlayerActionTimeout(te.table.Meta.CompetitionID, te.table.ID, player)
+	te.publishEvent(TableEvent{Type: TableEventType_TablePlayerActionTimeout, PlayerState: player})
+}
+
+// encodeTablePatch 產生與上一次桌次的差異 (需持有 te.lock)
+func (te *tableEngine) encodeTablePatch() *TablePatch {
+	if te.patchEncoder == nil {
+		return nil
+	}
+
+	patch, err := te.patchEncoder.Encode(te.table)
+	if err != nil {
+		te.emitErrorEvent("encodeTablePatch#Encode", "", err)
+		return nil
+	}
+	return patch
+}
+
+func (te *tableEngine) emitGameSettledEvent(result TableGameResult) {
+	// emit event
+	// fmt.Printf("->emit game settled Event: %s\n", result.GameID)
+	te.onGameSettled(result)
+	te.publishEvent(TableEvent{Type: TableEventType_GameSettled, GameResult: &result})
 }
 
 func (te *tableEngine) emitReadyOpenFirstTableGame(gameCount int, playerStates []*TablePlayerState) {
 	// emit event
 	// fmt.Printf("->emit ready open first table game: %d players\n", len(playerStates))
 	te.onReadyOpenFirstTableGame(te.table.Meta.CompetitionID, te.table.ID, gameCount, playerStates)
+	te.publishEvent(TableEvent{Type: TableEventType_ReadyOpenFirstTableGame, GameCount: gameCount, PlayerStates: playerStates})
+}
+
+func (te *tableEngine) emitAutoGameOpenEndEvent() {
+	// emit event
+	// fmt.Printf("->emit auto game open end Event: %s\n", te.table.ID)
+	te.onAutoGameOpenEnd(te.table.Meta.CompetitionID, te.table.ID)
+	te.publishEvent(TableEvent{Type: TableEventType_AutoGameOpenEnd})
+}
+
+// publishEvent 發布事件到桌次引擎與共用的事件匯流排
+func (te *tableEngine) publishEvent(event TableEvent) {
+	event.CompetitionID = te.table.Meta.CompetitionID
+	event.TableID = te.table.ID
+
+	te.bus.Publish(event)
+	for _, bus := range te.sharedBuses {
+		bus.Publish(event)
+	}
 }
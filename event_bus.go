@@ -0,0 +1,227 @@
+package pokertable
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	TableEventType_TableUpdated             = "TableUpdated"             // 桌次更新
+	TableEventType_TableErrorUpdated        = "TableErrorUpdated"        // 錯誤更新
+	TableEventType_TableStateUpdated        = "TableStateUpdated"        // 桌次狀態更新
+	TableEventType_TablePlayerStateUpdated  = "TablePlayerStateUpdated"  // 桌次玩家狀態更新
+	TableEventType_TablePlayerReserved      = "TablePlayerReserved"      // 桌次玩家確認座位
+	TableEventType_GamePlayerActionUpdated  = "GamePlayerActionUpdated"  // 遊戲玩家動作更新
+	TableEventType_AutoGameOpenEnd          = "AutoGameOpenEnd"          // 自動開桌結束
+	TableEventType_ReadyOpenFirstTableGame  = "ReadyOpenFirstTableGame"  // 開始第一手遊戲
+	TableEventType_TablePlayerTimeBankUsed  = "TablePlayerTimeBankUsed"  // 玩家使用時間銀行
+	TableEventType_TablePlayerActionTimeout = "TablePlayerActionTimeout" // 玩家動作超時
+	TableEventType_GameSettled              = "GameSettled"              // 本手結算結果
+	TableEventType_TablePatched             = "TablePatched"             // 桌次差異
+)
+
+type TableEvent struct {
+	Type          string                 `json:"type"`                    // 事件類型
+	CompetitionID string                 `json:"competition_id"`          // 賽事 ID
+	TableID       string                 `json:"table_id"`                // 桌次 ID
+	Table         *Table                 `json:"table,omitempty"`         // 桌次 (非同步訂閱時為複本)
+	Error         error                  `json:"-"`                       // 錯誤 (TableErrorUpdated)
+	StateEvent    string                 `json:"state_event,omitempty"`   // 桌次狀態事件 (TableStateUpdated)
+	PlayerState   *TablePlayerState      `json:"player_state,omitempty"`  // 玩家狀態 (TablePlayerStateUpdated, TablePlayerReserved, TablePlayerTimeBankUsed, TablePlayerActionTimeout)
+	PlayerStates  []*TablePlayerState    `json:"player_states,omitempty"` // 玩家狀態陣列 (ReadyOpenFirstTableGame)
+	GameCount     int                    `json:"game_count,omitempty"`    // 執行牌局遊戲次數 (ReadyOpenFirstTableGame)
+	Duration      int                    `json:"duration,omitempty"`      // 使用的時間銀行秒數 (TablePlayerTimeBankUsed)
+	GameAction    *TablePlayerGameAction `json:"game_action,omitempty"`   // 玩家動作 (GamePlayerActionUpdated)
+	GameResult    *TableGameResult       `json:"game_result,omitempty"`   // 本手結算結果 (GameSettled)
+	Patch         *TablePatch            `json:"patch,omitempty"`         // 桌次差異 (TablePatched)
+}
+
+type TableEventHandler func(event TableEvent)
+
+type TableEventSubscribeOpt func(*tableEventSubscriber)
+
+type TableEventBus interface {
+	Subscribe(handler TableEventHandler, opts ...TableEventSubscribeOpt) TableEventSubscription // 訂閱事件
+	Publish(event TableEvent)                                                                   // 發布事件
+	Close()                                                                                     // 取消所有訂閱
+}
+
+type TableEventSubscription interface {
+	Unsubscribe()        // 取消訂閱
+	DroppedCount() int64 // 非同步訂閱因緩衝區已滿而丟棄的事件數
+}
+
+// WithTableEventTypes 只訂閱指定類型的事件 (未指定則訂閱全部)
+func WithTableEventTypes(types ...string) TableEventSubscribeOpt {
+	return func(s *tableEventSubscriber) {
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+}
+
+/*
+WithTableEventAsync 以非同步方式遞送事件
+  - bufferSize: 緩衝區大小，已滿時丟棄新事件 (不阻塞桌次引擎)
+  - 事件中的桌次與玩家狀態為發布當下的複本
+*/
+func WithTableEventAsync(bufferSize int) TableEventSubscribeOpt {
+	return func(s *tableEventSubscriber) {
+		if bufferSize < 1 {
+			bufferSize = 1
+		}
+		s.events = make(chan TableEvent, bufferSize)
+	}
+}
+
+type tableEventBus struct {
+	mu          sync.RWMutex
+	nextID      int64
+	subscribers map[int64]*tableEventSubscriber
+}
+
+func NewTableEventBus() TableEventBus {
+	return &tableEventBus{
+		subscribers: make(map[int64]*tableEventSubscriber),
+	}
+}
+
+func (b *tableEventBus) Subscribe(handler TableEventHandler, opts ...TableEventSubscribeOpt) TableEventSubscription {
+	s := &tableEventSubscriber{
+		bus:     b,
+		handler: handler,
+		types:   make(map[string]bool),
+		done:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	s.id = b.nextID
+	b.subscribers[s.id] = s
+	b.mu.Unlock()
+
+	if s.events != nil {
+		go s.run()
+	}
+
+	return s
+}
+
+/*
+Publish 發布事件給所有訂閱者
+  - 同步訂閱者依訂閱順序在發布的 goroutine 中處理
+  - 訂閱者可以在處理事件時取消訂閱或訂閱
+*/
+func (b *tableEventBus) Publish(event TableEvent) {
+	b.mu.RLock()
+	subscribers := make([]*tableEventSubscriber, 0, len(b.subscribers))
+	for _, s := range b.subscribers {
+		if len(s.types) == 0 || s.types[event.Type] {
+			subscribers = append(subscribers, s)
+		}
+	}
+	b.mu.RUnlock()
+
+	sort.Slice(subscribers, func(i, j int) bool {
+		return subscribers[i].id < subscribers[j].id
+	})
+
+	// 非同步訂閱者共用同一份桌次複本
+	var clonedTable *Table
+	isCloned := false
+	for _, s := range subscribers {
+		if s.events == nil {
+			s.handler(event)
+			continue
+		}
+
+		asyncEvent := event
+		if event.Table != nil {
+			if !isCloned {
+				clonedTable, _ = event.Table.Clone()
+				isCloned = true
+			}
+			asyncEvent.Table = clonedTable
+		}
+		if event.PlayerState != nil {
+			playerState := *event.PlayerState
+			asyncEvent.PlayerState = &playerState
+		}
+		if event.PlayerStates != nil {
+			asyncEvent.PlayerStates = make([]*TablePlayerState, 0, len(event.PlayerStates))
+			for _, ps := range event.PlayerStates {
+				playerState := *ps
+				asyncEvent.PlayerStates = append(asyncEvent.PlayerStates, &playerState)
+			}
+		}
+		s.deliver(asyncEvent)
+	}
+}
+
+func (b *tableEventBus) Close() {
+	b.mu.Lock()
+	subscribers := b.subscribers
+	b.subscribers = make(map[int64]*tableEventSubscriber)
+	b.mu.Unlock()
+
+	for _, s := range subscribers {
+		s.stop()
+	}
+}
+
+func (b *tableEventBus) unsubscribe(id int64) {
+	b.mu.Lock()
+	delete(b.subscribers, id)
+	b.mu.Unlock()
+}
+
+type tableEventSubscriber struct {
+	dropped  int64 // 放在第一個欄位以確保 atomic 操作對齊
+	id       int64
+	bus      *tableEventBus
+	handler  TableEventHandler
+	types    map[string]bool
+	events   chan TableEvent
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (s *tableEventSubscriber) Unsubscribe() {
+	s.bus.unsubscribe(s.id)
+	s.stop()
+}
+
+func (s *tableEventSubscriber) DroppedCount() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+func (s *tableEventSubscriber) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *tableEventSubscriber) deliver(event TableEvent) {
+	select {
+	case <-s.done:
+	case s.events <- event:
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+func (s *tableEventSubscriber) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case event := <-s.events:
+			s.handler(event)
+		}
+	}
+}
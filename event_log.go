@@ -0,0 +1,236 @@
+package pokertable
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/weedbox/pokerface"
+)
+
+var (
+	ErrEventLogUpdateSerialNotFound = errors.New("event log: update serial not found")
+	ErrEventLogTableCommandNotFound = errors.New("event log: create or restore table command not found")
+	ErrEventLogUnknownCommand       = errors.New("event log: unknown command")
+	ErrEventLogReplayTimeout        = errors.New("event log: replay timeout")
+	ErrEventLogReplayMismatch       = errors.New("event log: replay mismatch")
+)
+
+const (
+	TableEventLogType_Command   = "command"    // 桌次指令
+	TableEventLogType_GameState = "game_state" // 遊戲狀態轉換
+	TableEventLogType_Table     = "table"      // 桌次更新 (JSON Patch)
+)
+
+type TableEventLogEntry struct {
+	Seq          int64                `json:"seq"`                   // 事件序號 (數字越大越晚發生)
+	TableID      string               `json:"table_id"`              // 桌次 ID
+	UpdateSerial int64                `json:"update_serial"`         // 事件發生時的桌次更新序列號 (指令為開始執行時)
+	Type         string               `json:"type"`                  // 事件類型 (command, game_state, table)
+	Name         string               `json:"name"`                  // 指令名稱或事件名稱
+	PlayerID     string               `json:"player_id,omitempty"`   // 玩家 ID
+	Args         json.RawMessage      `json:"args,omitempty"`        // 指令參數
+	Error        string               `json:"error,omitempty"`       // 指令執行結果 (錯誤訊息，成功時為空)
+	GameState    *pokerface.GameState `json:"game_state,omitempty"`  // 遊戲狀態
+	TablePatch   json.RawMessage      `json:"table_patch,omitempty"` // 與上一筆桌次的差異 (JSON Patch, RFC 6902)
+	CreatedAt    int64                `json:"created_at"`            // 建立時間 (Milliseconds)
+}
+
+// tableEventLogTableArgs 建立或還原桌次的指令參數 (重播時以相同設定建立桌次引擎)
+type tableEventLogTableArgs struct {
+	TableSetting    *TableSetting        `json:"table_setting,omitempty"` // 建立桌次設定
+	Snapshot        *TableEngineSnapshot `json:"snapshot,omitempty"`      // 還原桌次快照
+	Options         *TableEngineOptions  `json:"options"`                 // 桌次引擎設定
+	RandomSeed      int64                `json:"random_seed"`             // 桌次亂數來源種子
+	HasDeckProvider bool                 `json:"has_deck_provider"`       // 是否由外部指定牌組 (重播時沿用紀錄中的牌組)
+}
+
+func (te *tableEngine) newTableEventLogTableArgs(tableSetting *TableSetting, snapshot *TableEngineSnapshot, randomSeed int64) tableEventLogTableArgs {
+	return tableEventLogTableArgs{
+		TableSetting:    tableSetting,
+		Snapshot:        snapshot,
+		Options:         te.options,
+		RandomSeed:      randomSeed,
+		HasDeckProvider: te.deckProvider != nil,
+	}
+}
+
+type TableEventLogger interface {
+	Append(entry TableEventLogEntry) error
+}
+
+// TableEventLog 記憶體事件紀錄
+type TableEventLog struct {
+	mu      sync.RWMutex
+	entries []TableEventLogEntry
+}
+
+func NewTableEventLog() *TableEventLog {
+	return &TableEventLog{
+		entries: make([]TableEventLogEntry, 0),
+	}
+}
+
+func (l *TableEventLog) Append(entry TableEventLogEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func (l *TableEventLog) Entries() []TableEventLogEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]TableEventLogEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
+
+// JSONTableEventLogger 以 JSON Lines 格式寫出事件紀錄
+type JSONTableEventLogger struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewJSONTableEventLogger(w io.Writer) *JSONTableEventLogger {
+	return &JSONTableEventLogger{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+func (l *JSONTableEventLogger) Append(entry TableEventLogEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.encoder.Encode(entry)
+}
+
+// ReadTableEventLog 讀取 JSON Lines 格式的事件紀錄
+func ReadTableEventLog(r io.Reader) ([]TableEventLogEntry, error) {
+	entries := make([]TableEventLogEntry, 0)
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var entry TableEventLogEntry
+			if err := json.Unmarshal(line, &entry); err != nil {
+				return entries, err
+			}
+			entries = append(entries, entry)
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return entries, err
+		}
+	}
+
+	return entries, nil
+}
+
+type tableEventRecorder struct {
+	mu        sync.Mutex
+	logger    TableEventLogger
+	seq       int64
+	lastTable map[string]interface{}
+}
+
+func newTableEventRecorder(logger TableEventLogger) *tableEventRecorder {
+	return &tableEventRecorder{
+		logger:    logger,
+		lastTable: make(map[string]interface{}),
+	}
+}
+
+func (r *tableEventRecorder) append(entry TableEventLogEntry) error {
+	if entry.Seq == 0 {
+		r.seq++
+		entry.Seq = r.seq
+	}
+	entry.CreatedAt = time.Now().UnixMilli()
+	return r.logger.Append(entry)
+}
+
+// newCommand 建立指令紀錄並先取得事件序號 (執行結束後再以 recordCommand 寫出)
+func (r *tableEventRecorder) newCommand(table *Table, name, playerID string, args interface{}) (TableEventLogEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := TableEventLogEntry{
+		TableID:      table.ID,
+		UpdateSerial: table.UpdateSerial,
+		Type:         TableEventLogType_Command,
+		Name:         name,
+		PlayerID:     playerID,
+	}
+
+	if args != nil {
+		encoded, err := json.Marshal(args)
+		if err != nil {
+			return entry, err
+		}
+		entry.Args = encoded
+	}
+
+	r.seq++
+	entry.Seq = r.seq
+	return entry, nil
+}
+
+func (r *tableEventRecorder) recordCommand(entry TableEventLogEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.append(entry)
+}
+
+func (r *tableEventRecorder) recordGameState(table *Table, gs *pokerface.GameState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.append(TableEventLogEntry{
+		TableID:      table.ID,
+		UpdateSerial: table.UpdateSerial,
+		Type:         TableEventLogType_GameState,
+		Name:         gs.Status.CurrentEvent,
+		GameState:    cloneGameState(gs),
+	})
+}
+
+func (r *tableEventRecorder) recordTable(table *Table, name, playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := toJSONObject(table)
+	if err != nil {
+		return err
+	}
+
+	ops, err := createJSONPatch(r.lastTable, current)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(ops)
+	if err != nil {
+		return err
+	}
+	r.lastTable = current
+
+	return r.append(TableEventLogEntry{
+		TableID:      table.ID,
+		UpdateSerial: table.UpdateSerial,
+		Type:         TableEventLogType_Table,
+		Name:         name,
+		PlayerID:     playerID,
+		TablePatch:   patch,
+	})
+}
@@ -0,0 +1,595 @@
+package pokertable
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weedbox/pokerface"
+)
+
+// replayWaitTimeout 重播時等待桌次更新或計時器的最長時間 (計時器由紀錄觸發，只需等待非同步的遊戲狀態更新)
+const replayWaitTimeout = time.Second * 5
+
+/*
+Replay 重播事件紀錄，還原指定桌次在 UpdateSerial 當下的狀態
+  - 適用時機: 爭議稽核、重現線上問題
+  - 以紀錄中建立 (或還原) 桌次的設定與亂數種子建立新的桌次引擎，依序號重新執行每個指令，並比對指令執行結果
+  - 桌次引擎使用重播時鐘: 時間依紀錄推進，計時器不會自行觸發，只在紀錄中的 Timeout 指令觸發
+  - 每手遊戲沿用紀錄中的 GameID (外部指定牌組時沿用紀錄中的牌組)
+  - 重播產生的每次桌次更新皆與紀錄中的桌次差異比對 (忽略時間欄位)，不一致時回傳 ErrEventLogReplayMismatch
+*/
+func Replay(log []TableEventLogEntry, tableID string, updateSerial int64) (*Table, error) {
+	entries := make([]TableEventLogEntry, 0)
+	for _, entry := range log {
+		if entry.TableID == tableID {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Seq < entries[j].Seq
+	})
+
+	expectedTables, err := foldTableEventLog(entries, updateSerial)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableArgs *tableEventLogTableArgs
+	commands := make([]TableEventLogEntry, 0)
+	for _, entry := range entries {
+		if entry.Type != TableEventLogType_Command {
+			continue
+		}
+
+		if tableArgs == nil {
+			if entry.Name == "CreateTable" || entry.Name == "RestoreTable" {
+				tableArgs = &tableEventLogTableArgs{}
+				if err := json.Unmarshal(entry.Args, tableArgs); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if entry.UpdateSerial <= updateSerial {
+			commands = append(commands, entry)
+		}
+	}
+
+	if tableArgs == nil {
+		return nil, ErrEventLogTableCommandNotFound
+	}
+
+	r := newTableReplayer(entries, tableArgs)
+	defer r.release()
+
+	if err := r.start(tableArgs); err != nil {
+		return nil, err
+	}
+
+	for _, command := range commands {
+		if err := r.waitFor(command.UpdateSerial); err != nil {
+			return nil, err
+		}
+		r.clock.advance(time.UnixMilli(command.CreatedAt))
+
+		errMsg := ""
+		if err := r.te.replayCommand(command); err != nil {
+			errMsg = err.Error()
+		}
+
+		if errMsg != command.Error {
+			return nil, fmt.Errorf("%w: command #%d %s (%s) result %q, expected %q", ErrEventLogReplayMismatch, command.Seq, command.Name, command.PlayerID, errMsg, command.Error)
+		}
+	}
+
+	if err := r.waitFor(updateSerial); err != nil {
+		return nil, err
+	}
+
+	return r.compare(expectedTables, updateSerial)
+}
+
+// foldTableEventLog 依序套用桌次差異，取得每個 UpdateSerial 當下紀錄的桌次
+func foldTableEventLog(entries []TableEventLogEntry, updateSerial int64) (map[int64]interface{}, error) {
+	tables := make(map[int64]interface{})
+
+	var document interface{} = make(map[string]interface{})
+	for _, entry := range entries {
+		if entry.Type != TableEventLogType_Table {
+			continue
+		}
+
+		if entry.UpdateSerial > updateSerial {
+			break
+		}
+
+		ops := make([]JSONPatchOperation, 0)
+		if err := json.Unmarshal(entry.TablePatch, &ops); err != nil {
+			return nil, err
+		}
+
+		var err error
+		document, err = applyJSONPatch(document, ops)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded, err := json.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+
+		var table interface{}
+		if err := decodeJSONWithNumber(encoded, &table); err != nil {
+			return nil, err
+		}
+		tables[entry.UpdateSerial] = table
+	}
+
+	if _, exist := tables[updateSerial]; !exist {
+		return nil, ErrEventLogUpdateSerialNotFound
+	}
+
+	return tables, nil
+}
+
+// comparableTable 取得可比對的桌次內容 (移除重播時必然不同的時間欄位，名稱以 _at 結尾)
+func comparableTable(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, field := range value {
+			if strings.HasSuffix(key, "_at") {
+				continue
+			}
+			object[key] = comparableTable(field)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for idx, field := range value {
+			array[idx] = comparableTable(field)
+		}
+		return array
+	}
+
+	return v
+}
+
+// tableReplayer 重播用的桌次引擎，記錄每次桌次更新的內容
+type tableReplayer struct {
+	te      *tableEngine
+	clock   *replayClock
+	mu      sync.Mutex
+	updated *sync.Cond
+	tables  map[int64]interface{}
+	serial  int64
+}
+
+func newTableReplayer(entries []TableEventLogEntry, tableArgs *tableEventLogTableArgs) *tableReplayer {
+	// 每手遊戲的 GameID 與牌組 (還原時進行中的那一手不會重新建立)
+	restoredGameID := ""
+	if tableArgs.Snapshot != nil && tableArgs.Snapshot.Table != nil && tableArgs.Snapshot.Table.State != nil && tableArgs.Snapshot.Table.State.GameState != nil {
+		restoredGameID = tableArgs.Snapshot.Table.State.GameState.GameID
+	}
+
+	gameIDs := make([]string, 0)
+	decks := make([][]string, 0)
+	for _, entry := range entries {
+		if entry.Type != TableEventLogType_GameState || entry.GameState == nil || entry.GameState.GameID == restoredGameID {
+			continue
+		}
+
+		if len(gameIDs) == 0 || gameIDs[len(gameIDs)-1] != entry.GameState.GameID {
+			gameIDs = append(gameIDs, entry.GameState.GameID)
+			decks = append(decks, entry.GameState.Meta.Deck)
+		}
+	}
+
+	// 從建立 (或還原) 桌次的時間開始
+	clock := newReplayClock()
+	for _, entry := range entries {
+		if entry.Type == TableEventLogType_Command {
+			clock.advance(time.UnixMilli(entry.CreatedAt))
+			break
+		}
+	}
+
+	opts := []TableEngineOpt{
+		WithGameBackend(newReplayGameBackend(gameIDs)),
+		WithRandomSource(rand.NewSource(tableArgs.RandomSeed)),
+		withTableClock(clock),
+	}
+	if tableArgs.HasDeckProvider {
+		var deckMu sync.Mutex
+		opts = append(opts, WithDeckProvider(func(table *Table) []string {
+			deckMu.Lock()
+			defer deckMu.Unlock()
+
+			if len(decks) == 0 {
+				return nil
+			}
+			deck := decks[0]
+			decks = decks[1:]
+			return deck
+		}))
+	}
+
+	options := tableArgs.Options
+	if options == nil {
+		options = NewTableEngineOptions()
+	}
+
+	r := &tableReplayer{
+		te:     NewTableEngine(options, opts...).(*tableEngine),
+		clock:  clock,
+		tables: make(map[int64]interface{}),
+	}
+	r.updated = sync.NewCond(&r.mu)
+	r.te.OnTableUpdated(func(table *Table) {
+		document, err := toJSONObject(table)
+		if err != nil {
+			return
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.tables[table.UpdateSerial] = document
+		if table.UpdateSerial > r.serial {
+			r.serial = table.UpdateSerial
+			r.updated.Broadcast()
+		}
+	})
+
+	return r
+}
+
+func (r *tableReplayer) start(tableArgs *tableEventLogTableArgs) error {
+	if tableArgs.Snapshot != nil {
+		_, err := r.te.RestoreTable(*tableArgs.Snapshot)
+		return err
+	}
+
+	if tableArgs.TableSetting == nil {
+		return ErrEventLogTableCommandNotFound
+	}
+
+	_, err := r.te.CreateTable(*tableArgs.TableSetting)
+	return err
+}
+
+func (r *tableReplayer) release() {
+	if r.te.table != nil && !r.te.isReleased {
+		r.te.ReleaseTable()
+	}
+}
+
+/*
+waitFor 等待重播的桌次更新到指定序列號
+  - 計時器不會自行觸發，只需等待指令觸發的非同步遊戲狀態更新
+  - 超過 replayWaitTimeout 仍未更新表示重播與紀錄不一致 (例如缺少的計時器不會觸發)
+*/
+func (r *tableReplayer) waitFor(updateSerial int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	isTimeout := false
+	timeout := time.AfterFunc(replayWaitTimeout, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		isTimeout = true
+		r.updated.Broadcast()
+	})
+	defer timeout.Stop()
+
+	for r.serial < updateSerial {
+		if isTimeout {
+			return fmt.Errorf("%w: waiting for update serial #%d, current #%d", ErrEventLogReplayTimeout, updateSerial, r.serial)
+		}
+		r.updated.Wait()
+	}
+
+	return nil
+}
+
+// compare 比對重播與紀錄中每個 UpdateSerial 的桌次，回傳指定 UpdateSerial 重播後的桌次
+func (r *tableReplayer) compare(expectedTables map[int64]interface{}, updateSerial int64) (*Table, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	serials := make([]int64, 0, len(expectedTables))
+	for serial := range expectedTables {
+		serials = append(serials, serial)
+	}
+	sort.Slice(serials, func(i, j int) bool {
+		return serials[i] < serials[j]
+	})
+
+	for _, serial := range serials {
+		actual, exist := r.tables[serial]
+		if !exist || !reflect.DeepEqual(comparableTable(expectedTables[serial]), comparableTable(actual)) {
+			return nil, fmt.Errorf("%w: update serial #%d", ErrEventLogReplayMismatch, serial)
+		}
+	}
+
+	encoded, err := json.Marshal(r.tables[updateSerial])
+	if err != nil {
+		return nil, err
+	}
+
+	var table Table
+	if err := json.Unmarshal(encoded, &table); err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
+
+/*
+replayClock 重播用的時鐘
+  - 時間停在目前重播到的指令紀錄時間
+  - 計時器不會自行觸發 (時間為 0 時立即觸發)，由紀錄中的 Timeout 指令觸發同名的計時器
+*/
+type replayClock struct {
+	mu      sync.Mutex
+	pending *sync.Cond
+	now     time.Time
+	timers  map[string]*replayTimer
+}
+
+func newReplayClock() *replayClock {
+	c := &replayClock{
+		timers: make(map[string]*replayTimer),
+	}
+	c.pending = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *replayClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *replayClock) NewTimer(name string) tableTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &replayTimer{clock: c}
+	c.timers[name] = t
+	return t
+}
+
+// advance 推進到指定時間 (不會倒退)
+func (c *replayClock) advance(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.After(c.now) {
+		c.now = now
+	}
+}
+
+// fire 觸發指定名稱的計時器 (計時器可能還在非同步的遊戲狀態更新中開始計時，等待到開始計時為止)
+func (c *replayClock) fire(name string) error {
+	c.mu.Lock()
+
+	isTimeout := false
+	timeout := time.AfterFunc(replayWaitTimeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		isTimeout = true
+		c.pending.Broadcast()
+	})
+	defer timeout.Stop()
+
+	for {
+		if t, exist := c.timers[name]; exist && t.task != nil {
+			task := t.task
+			t.task = nil
+			c.mu.Unlock()
+
+			task(false)
+			return nil
+		}
+
+		if isTimeout {
+			c.mu.Unlock()
+			return fmt.Errorf("%w: waiting for timer %s", ErrEventLogReplayTimeout, name)
+		}
+		c.pending.Wait()
+	}
+}
+
+type replayTimer struct {
+	clock *replayClock
+	task  func(isCancelled bool)
+}
+
+func (t *replayTimer) NewTask(duration time.Duration, fn func(isCancelled bool)) error {
+	t.Cancel()
+
+	// Trigger immediately
+	if duration <= 0 {
+		fn(false)
+		return nil
+	}
+
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	t.task = fn
+	t.clock.pending.Broadcast()
+	return nil
+}
+
+func (t *replayTimer) Cancel() {
+	t.clock.mu.Lock()
+	task := t.task
+	t.task = nil
+	t.clock.mu.Unlock()
+
+	if task != nil {
+		go task(true)
+	}
+}
+
+// Extend 計時器不會自行逾時，還在計時即可延長
+func (t *replayTimer) Extend(duration time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.task != nil
+}
+
+// replayGameBackend 重播用的遊戲引擎，每手遊戲沿用紀錄中的 GameID
+type replayGameBackend struct {
+	*NativeGameBackend
+	mu      sync.Mutex
+	gameIDs []string
+}
+
+func newReplayGameBackend(gameIDs []string) *replayGameBackend {
+	return &replayGameBackend{
+		NativeGameBackend: NewNativeGameBackend(),
+		gameIDs:           gameIDs,
+	}
+}
+
+func (b *replayGameBackend) CreateGame(opts *pokerface.GameOptions) (*pokerface.GameState, error) {
+	gs, err := b.NativeGameBackend.CreateGame(opts)
+	if err != nil {
+		return gs, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.gameIDs) > 0 {
+		gs.GameID = b.gameIDs[0]
+		b.gameIDs = b.gameIDs[1:]
+	}
+
+	return gs, nil
+}
+
+// replayCommand 依紀錄重新執行桌次指令
+func (te *tableEngine) replayCommand(entry TableEventLogEntry) error {
+	var args struct {
+		Mode           string         `json:"mode"`
+		GameCount      int            `json:"game_count"`
+		Participants   map[string]int `json:"participants"`
+		JoinPlayers    []JoinPlayer   `json:"join_players"`
+		LeavePlayerIDs []string       `json:"leave_player_ids"`
+		PlayerIDs      []string       `json:"player_ids"`
+		Duration       int            `json:"duration"`
+		Chips          int64          `json:"chips"`
+		ChipLevel      int64          `json:"chip_level"`
+		Times          int            `json:"times"`
+		Timer          string         `json:"timer"`
+		GameID         string         `json:"game_id"`
+		GamePlayerIdx  int            `json:"game_player_idx"`
+		TimerSerial    int64          `json:"timer_serial"`
+	}
+	var joinPlayer JoinPlayer
+	var blind TableBlindState
+
+	if len(entry.Args) > 0 {
+		var target interface{} = &args
+		switch entry.Name {
+		case "PlayerReserve", "PlayerRedeemChips":
+			target = &joinPlayer
+		case "UpdateBlind":
+			target = &blind
+		}
+
+		if err := json.Unmarshal(entry.Args, target); err != nil {
+			return err
+		}
+	}
+
+	playerID := entry.PlayerID
+	switch entry.Name {
+	case "ReleaseTable":
+		return te.ReleaseTable()
+	case "PauseTable":
+		return te.PauseTable()
+	case "CloseTable":
+		return te.CloseTable()
+	case "StartTableGame":
+		return te.StartTableGame()
+	case "UpdateBlind":
+		te.UpdateBlind(blind.Level, blind.Ante, blind.Dealer, blind.SB, blind.BB)
+		return nil
+	case "UpdateAnteMode":
+		return te.UpdateAnteMode(args.Mode)
+	case "SetUpTableGame":
+		te.SetUpTableGame(args.GameCount, args.Participants)
+		return nil
+	case "UpdateTablePlayers":
+		_, err := te.UpdateTablePlayers(args.JoinPlayers, args.LeavePlayerIDs)
+		return err
+	case "PlayerReserve":
+		return te.PlayerReserve(joinPlayer)
+	case "PlayerJoin":
+		return te.PlayerJoin(playerID)
+	case "PlayerSettlementFinish":
+		return te.PlayerSettlementFinish(playerID)
+	case "PlayerVoteBombPot":
+		return te.PlayerVoteBombPot(playerID)
+	case "PlayerRedeemChips":
+		return te.PlayerRedeemChips(joinPlayer)
+	case "PlayersLeave":
+		return te.PlayersLeave(args.PlayerIDs)
+	case "PlayerSitOut":
+		return te.PlayerSitOut(playerID)
+	case "PlayerSitIn":
+		return te.PlayerSitIn(playerID)
+	case "PlayerExtendActionDeadline":
+		_, err := te.PlayerExtendActionDeadline(playerID, args.Duration)
+		return err
+	case "PlayerReady":
+		return te.PlayerReady(playerID)
+	case "PlayerPay":
+		return te.PlayerPay(playerID, args.Chips)
+	case "PlayerBet":
+		return te.PlayerBet(playerID, args.Chips)
+	case "PlayerRaise":
+		return te.PlayerRaise(playerID, args.ChipLevel)
+	case "PlayerActionTimeout":
+		return te.handleActionTimeout(args.GameID, args.GamePlayerIdx, args.TimerSerial, false)
+	case "Timeout":
+		clock, ok := te.clock.(*replayClock)
+		if !ok {
+			return ErrEventLogUnknownCommand
+		}
+		return clock.fire(args.Timer)
+	case "PlayerCall":
+		return te.PlayerCall(playerID)
+	case "PlayerAllin":
+		return te.PlayerAllin(playerID)
+	case "PlayerCheck":
+		return te.PlayerCheck(playerID)
+	case "PlayerFold":
+		return te.PlayerFold(playerID)
+	case "PlayerPass":
+		return te.PlayerPass(playerID)
+	case "PlayerRunItMultiple":
+		return te.PlayerRunItMultiple(playerID, args.Times)
+	case "PlayerStraddle":
+		return te.PlayerStraddle(playerID)
+	}
+
+	return ErrEventLogUnknownCommand
+}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/thoas/go-funk"
 	"github.com/weedbox/pokerface"
 	"github.com/weedbox/syncsaga"
+	"github.com/weedbox/timebank"
 )
 
 var (
@@ -17,6 +19,9 @@ var (
 	ErrGameUnknownEventHandler = errors.New("game: unknown event handler")
 )
 
+// gameReadyGroupTimeout 等待玩家回覆 (準備、繳交盲注等) 的秒數，逾時後自動 Ready
+const gameReadyGroupTimeout = 17
+
 type Game interface {
 	// Events
 	OnAntesReceived(func(*pokerface.GameState))
@@ -24,10 +29,13 @@ type Game interface {
 	OnGameStateUpdated(func(*pokerface.GameState))
 	OnGameRoundClosed(func(*pokerface.GameState))
 	OnGameErrorUpdated(func(*pokerface.GameState, error))
+	OnRunItMultipleRequested(func(*pokerface.GameState))
 
 	// Others
 	GetGameState() *pokerface.GameState
+	GetParticipantStates() map[int64]bool
 	Start() (*pokerface.GameState, error)
+	Resume(participantStates map[int64]bool) (*pokerface.GameState, error)
 	Next() (*pokerface.GameState, error)
 
 	// Group Actions
@@ -45,45 +53,110 @@ type Game interface {
 	Allin(playerIdx int) (*pokerface.GameState, error)
 	Bet(playerIdx int, chips int64) (*pokerface.GameState, error)
 	Raise(playerIdx int, chipLevel int64) (*pokerface.GameState, error)
+	RunItMultiple(playerIdx int, agree bool) (*pokerface.GameState, error)
+	Straddle(playerIdx int, chips int64) (*pokerface.GameState, error)
+}
+
+type GameOpt func(*game)
+
+// GameBlindPost 玩家補盲注 (現金桌錯過盲注的玩家)
+type GameBlindPost struct {
+	Live     int64 `json:"live"`               // 活盲: 計入玩家本輪下注
+	Dead     int64 `json:"dead"`               // 死盲: 直接進入底池，不計入玩家本輪下注
+	Ante     int64 `json:"ante,omitempty"`     // 代付全桌前注: 與死盲相同，直接進入主池
+	Straddle int64 `json:"straddle,omitempty"` // Straddle: 於盲注後計入玩家本輪下注，翻牌前最後行動
 }
 
 type game struct {
-	backend            GameBackend
-	gs                 *pokerface.GameState
-	opts               *pokerface.GameOptions
-	rg                 *syncsaga.ReadyGroup
-	mu                 sync.RWMutex
-	isClosed           bool
-	incomingStates     chan *pokerface.GameState
-	onAntesReceived    func(*pokerface.GameState)
-	onBlindsReceived   func(*pokerface.GameState)
-	onGameStateUpdated func(*pokerface.GameState)
-	onGameRoundClosed  (func(*pokerface.GameState))
-	onGameErrorUpdated func(*pokerface.GameState, error)
-}
-
-func NewGame(backend GameBackend, opts *pokerface.GameOptions) *game {
-	rg := syncsaga.NewReadyGroup(
-		syncsaga.WithTimeout(17, func(rg *syncsaga.ReadyGroup) {
-			// Auto Ready By Default
-			states := rg.GetParticipantStates()
-			for gamePlayerIdx, isReady := range states {
-				if !isReady {
-					rg.Ready(gamePlayerIdx)
-				}
-			}
-		}),
-	)
+	backend                  GameBackend
+	deck                     []string
+	blindPosts               map[int]GameBlindPost
+	runItTimes               int
+	isRunItRequested         bool
+	isBombPot                bool
+	gs                       *pokerface.GameState
+	opts                     *pokerface.GameOptions
+	rg                       *syncsaga.ReadyGroup
+	timer                    tableTimer
+	mu                       sync.RWMutex
+	isClosed                 bool
+	incomingStates           chan *pokerface.GameState
+	onAntesReceived          func(*pokerface.GameState)
+	onBlindsReceived         func(*pokerface.GameState)
+	onGameStateUpdated       func(*pokerface.GameState)
+	onGameRoundClosed        (func(*pokerface.GameState))
+	onGameErrorUpdated       func(*pokerface.GameState, error)
+	onRunItMultipleRequested func(*pokerface.GameState)
+}
+
+func NewGame(backend GameBackend, opts *pokerface.GameOptions, gameOpts ...GameOpt) *game {
+	g := newGame(backend, opts, nil)
+	for _, opt := range gameOpts {
+		opt(g)
+	}
+	return g
+}
+
+// WithGameDeck 指定本手使用的牌組 (依發牌順序排列，不再洗牌)
+func WithGameDeck(deck []string) GameOpt {
+	return func(g *game) {
+		g.deck = deck
+	}
+}
+
+// WithGameBlindPosts 指定本手需要補盲注的玩家 (key: game player index)
+func WithGameBlindPosts(posts map[int]GameBlindPost) GameOpt {
+	return func(g *game) {
+		if posts != nil {
+			g.blindPosts = posts
+		}
+	}
+}
+
+// WithGameRunItTimes 指定全下後最多可發牌次數 (小於 2 表示不開放多次發牌)
+func WithGameRunItTimes(times int) GameOpt {
+	return func(g *game) {
+		g.runItTimes = times
+	}
+}
+
+// withGameTimer 指定等待玩家回覆逾時的計時器 (由桌次引擎提供，重播時依紀錄觸發)
+func withGameTimer(timer tableTimer) GameOpt {
+	return func(g *game) {
+		g.timer = timer
+	}
+}
+
+// WithGameBombPot 本手為 Bomb Pot (以前注收取每位玩家的籌碼，不進行翻牌前下注)
+func WithGameBombPot() GameOpt {
+	return func(g *game) {
+		g.isBombPot = true
+	}
+}
+
+func NewGameFromState(backend GameBackend, gs *pokerface.GameState, gameOpts ...GameOpt) *game {
+	g := newGame(backend, nil, cloneGameState(gs))
+	for _, opt := range gameOpts {
+		opt(g)
+	}
+	return g
+}
+
+func newGame(backend GameBackend, opts *pokerface.GameOptions, gs *pokerface.GameState) *game {
 	return &game{
-		backend:            backend,
-		opts:               opts,
-		rg:                 rg,
-		incomingStates:     make(chan *pokerface.GameState, 1024),
-		onAntesReceived:    func(gs *pokerface.GameState) {},
-		onBlindsReceived:   func(gs *pokerface.GameState) {},
-		onGameStateUpdated: func(gs *pokerface.GameState) {},
-		onGameRoundClosed:  func(*pokerface.GameState) {},
-		onGameErrorUpdated: func(gs *pokerface.GameState, err error) {},
+		backend:                  backend,
+		gs:                       gs,
+		opts:                     opts,
+		rg:                       syncsaga.NewReadyGroup(),
+		timer:                    timebank.NewTimeBank(),
+		blindPosts:               make(map[int]GameBlindPost),
+		incomingStates:           make(chan *pokerface.GameState, 1024),
+		onAntesReceived:          func(gs *pokerface.GameState) {},
+		onBlindsReceived:         func(gs *pokerface.GameState) {},
+		onGameStateUpdated:       func(gs *pokerface.GameState) {},
+		onGameRoundClosed:        func(*pokerface.GameState) {},
+		onGameErrorUpdated:       func(gs *pokerface.GameState, err error) {},
+		onRunItMultipleRequested: func(gs *pokerface.GameState) {},
 	}
 }
 
@@ -107,10 +180,21 @@ func (g *game) OnGameErrorUpdated(fn func(*pokerface.GameState, error)) {
 	g.onGameErrorUpdated = fn
 }
 
+func (g *game) OnRunItMultipleRequested(fn func(*pokerface.GameState)) {
+	g.onRunItMultipleRequested = fn
+}
+
 func (g *game) GetGameState() *pokerface.GameState {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
 	return g.gs
 }
 
+func (g *game) GetParticipantStates() map[int64]bool {
+	return g.rg.GetParticipantStates()
+}
+
 func (g *game) Start() (*pokerface.GameState, error) {
 	g.runGameStateUpdater()
 
@@ -119,10 +203,52 @@ func (g *game) Start() (*pokerface.GameState, error) {
 		return g.GetGameState(), err
 	}
 
+	// pokerface 建立遊戲時會自行洗牌，有指定牌組時覆蓋
+	if len(g.deck) > 0 {
+		gs.Meta.Deck = append([]string{}, g.deck...)
+	}
+
 	g.updateGameState(gs)
 	return g.GetGameState(), nil
 }
 
+/*
+Resume 從既有的 GameState 繼續遊戲
+  - 適用時機: 桌次從快照還原後
+  - participantStates: 還原前 ReadyGroup 中各玩家的準備狀態
+*/
+func (g *game) Resume(participantStates map[int64]bool) (*pokerface.GameState, error) {
+	if g.gs == nil {
+		return nil, ErrGameInvalidAction
+	}
+
+	event, ok := pokerface.GameEventBySymbol[g.gs.Status.CurrentEvent]
+	if !ok {
+		return g.GetGameState(), ErrGameUnknownEvent
+	}
+
+	g.runGameStateUpdater()
+
+	switch event {
+	case pokerface.GameEvent_ReadyRequested:
+		g.onReadyRequested(g.gs)
+		g.restoreParticipantStates(participantStates)
+	case pokerface.GameEvent_AnteRequested:
+		g.onAnteRequested(g.gs)
+		g.restoreParticipantStates(participantStates)
+	case pokerface.GameEvent_BlindsRequested:
+		g.onBlindsRequested(g.gs)
+		g.restoreParticipantStates(participantStates)
+	case pokerface.GameEvent_RoundClosed:
+		g.onRoundClosed(g.gs)
+		g.restoreParticipantStates(participantStates)
+	case pokerface.GameEvent_GameClosed:
+		g.onGameClosed(g.gs)
+	}
+
+	return g.GetGameState(), nil
+}
+
 func (g *game) Next() (*pokerface.GameState, error) {
 	gs, err := g.backend.Next(g.gs)
 	if err != nil {
@@ -139,6 +265,13 @@ func (g *game) ReadyForAll() (*pokerface.GameState, error) {
 		return g.GetGameState(), err
 	}
 
+	g.applyStraddleActionOrder(gs)
+
+	gs, err = g.skipBombPotPreflop(gs)
+	if err != nil {
+		return g.GetGameState(), err
+	}
+
 	g.updateGameState(gs)
 	return g.GetGameState(), nil
 }
@@ -154,11 +287,23 @@ func (g *game) PayAnte() (*pokerface.GameState, error) {
 }
 
 func (g *game) PayBlinds() (*pokerface.GameState, error) {
-	gs, err := g.backend.PayBlinds(g.gs)
+	// 補盲注與代付全桌前注需在盲注前支付，活盲才會計入本輪下注
+	gs := g.gs
+	if posts := g.payableBlindPosts(); len(posts) > 0 {
+		state, err := g.backend.PayBlindPosts(gs, posts)
+		if err != nil {
+			return g.GetGameState(), err
+		}
+		gs = state
+	}
+
+	gs, err := g.backend.PayBlinds(gs)
 	if err != nil {
 		return g.GetGameState(), err
 	}
 
+	// Straddle 需在盲注後支付，才會成為本輪最高下注
+	g.payStraddle(gs)
 	g.updateGameState(gs)
 	return g.GetGameState(), nil
 }
@@ -298,6 +443,153 @@ func (g *game) Raise(playerIdx int, chipLevel int64) (*pokerface.GameState, erro
 	return g.GetGameState(), nil
 }
 
+/*
+RunItMultiple 全下玩家回覆是否同意多次發牌
+  - 適用時機: 所有玩家全下後、發完公牌前 (Action_RunItMultiple)
+  - 任一玩家不同意即結束等待，只發一次牌
+*/
+func (g *game) RunItMultiple(playerIdx int, agree bool) (*pokerface.GameState, error) {
+	if err := g.validateActionMove(playerIdx, Action_RunItMultiple); err != nil {
+		return g.GetGameState(), err
+	}
+
+	if !agree {
+		for gamePlayerIdx, isReady := range g.rg.GetParticipantStates() {
+			if !isReady {
+				g.rg.Ready(gamePlayerIdx)
+			}
+		}
+		return g.GetGameState(), nil
+	}
+
+	g.rg.Ready(int64(playerIdx))
+	return g.GetGameState(), nil
+}
+
+/*
+Straddle 玩家 Straddle
+  - 適用時機: 發牌前 (第一次 ReadyRequested)，且玩家尚未 Ready
+  - 每手只接受一位玩家 Straddle，籌碼於盲注支付後計入本輪下注
+*/
+func (g *game) Straddle(playerIdx int, chips int64) (*pokerface.GameState, error) {
+	if err := g.validateActionMove(playerIdx, Action_Ready); err != nil {
+		return g.GetGameState(), err
+	}
+
+	if g.gs.Status.CurrentEvent != pokerface.GameEventSymbols[pokerface.GameEvent_ReadyRequested] || g.gs.Status.Round != "" {
+		return g.GetGameState(), ErrGameInvalidAction
+	}
+
+	// Ready 之後即無法 Straddle，避免與發牌同時發生
+	if isReady := g.rg.GetParticipantStates()[int64(playerIdx)]; isReady {
+		return g.GetGameState(), ErrGameInvalidAction
+	}
+
+	if g.straddlerIdx() != UnsetValue {
+		return g.GetGameState(), ErrGameInvalidAction
+	}
+
+	post := g.blindPosts[playerIdx]
+	post.Straddle = chips
+	g.blindPosts[playerIdx] = post
+
+	p := g.gs.GetPlayer(playerIdx)
+	p.Positions = append(p.Positions, Position_Straddle)
+
+	return g.GetGameState(), nil
+}
+
+func (g *game) straddlerIdx() int {
+	for gamePlayerIdx, post := range g.blindPosts {
+		if post.Straddle > 0 {
+			return gamePlayerIdx
+		}
+	}
+	return UnsetValue
+}
+
+/*
+payStraddle Straddle 玩家支付 Straddle
+  - Straddle 視同盲注: 成為本輪最高下注，最小加注量為 Straddle 籌碼量
+*/
+func (g *game) payStraddle(gs *pokerface.GameState) {
+	straddlerIdx := g.straddlerIdx()
+	if straddlerIdx == UnsetValue {
+		return
+	}
+
+	p := gs.GetPlayer(straddlerIdx)
+	if p == nil {
+		return
+	}
+
+	chips := g.blindPosts[straddlerIdx].Straddle
+	if chips > p.StackSize {
+		chips = p.StackSize
+	}
+
+	p.Wager += chips
+	p.StackSize = p.InitialStackSize - p.Wager
+	gs.Status.CurrentRoundPot += chips
+
+	if gs.Status.CurrentWager < p.Wager {
+		gs.Status.PreviousRaiseSize = p.Wager
+		gs.Status.CurrentWager = p.Wager
+		gs.Status.CurrentRaiser = straddlerIdx
+	}
+}
+
+/*
+applyStraddleActionOrder 翻牌前由 Straddle 玩家的下一位開始行動
+  - 適用時機: 翻牌前開始行動 (RoundStarted)
+  - 所有玩家在新回合開始時皆未行動，因此 Straddle 玩家會是最後一位行動的玩家
+*/
+func (g *game) applyStraddleActionOrder(gs *pokerface.GameState) {
+	straddlerIdx := g.straddlerIdx()
+	if straddlerIdx == UnsetValue || gs.Status.Round != GameRound_Preflop || gs.Status.CurrentEvent != pokerface.GameEventSymbols[pokerface.GameEvent_RoundStarted] {
+		return
+	}
+
+	pg := pokerface.NewGameFromState(gs)
+	if err := pg.SetCurrentPlayer(pg.Player((straddlerIdx + 1) % len(gs.Players))); err != nil {
+		g.onGameErrorUpdated(gs, err)
+	}
+}
+
+/*
+skipBombPotPreflop Bomb Pot 不進行翻牌前下注
+  - 適用時機: 翻牌前開始行動 (RoundStarted)
+  - 翻牌前所有玩家依序自動過牌 (已全下的玩家略過)，回合結束後直接進入翻牌
+*/
+func (g *game) skipBombPotPreflop(gs *pokerface.GameState) (*pokerface.GameState, error) {
+	if !g.isBombPot {
+		return gs, nil
+	}
+
+	var err error
+	for gs.Status.Round == GameRound_Preflop && gs.Status.CurrentEvent == pokerface.GameEventSymbols[pokerface.GameEvent_RoundStarted] {
+		p := gs.GetPlayer(gs.Status.CurrentPlayer)
+		if p == nil {
+			return gs, ErrGamePlayerNotFound
+		}
+
+		switch {
+		case funk.ContainsString(p.AllowedActions, WagerAction_Check):
+			gs, err = g.backend.Check(gs)
+		case funk.ContainsString(p.AllowedActions, Action_Pass):
+			gs, err = g.backend.Pass(gs)
+		default:
+			return gs, ErrGameInvalidAction
+		}
+
+		if err != nil {
+			return gs, err
+		}
+	}
+
+	return gs, nil
+}
+
 func (g *game) validatePlayMove(playerIdx int) error {
 	if p := g.gs.GetPlayer(playerIdx); p == nil {
 		return ErrGamePlayerNotFound
@@ -326,6 +618,41 @@ func (g *game) validateActionMove(playerIdx int, action string) error {
 	return nil
 }
 
+// resetReadyGroup 重新準備 ready group，完成時停止逾時計時器
+func (g *game) resetReadyGroup(onCompleted func(rg *syncsaga.ReadyGroup)) {
+	g.rg.Stop()
+	g.rg.OnCompleted(func(rg *syncsaga.ReadyGroup) {
+		g.timer.Cancel()
+		onCompleted(rg)
+	})
+}
+
+// startReadyGroup 開始等待玩家回覆，逾時後所有玩家自動 Ready
+func (g *game) startReadyGroup() {
+	g.rg.Start()
+	g.timer.NewTask(time.Second*gameReadyGroupTimeout, func(isCancelled bool) {
+		if isCancelled {
+			return
+		}
+
+		// Auto Ready By Default
+		for gamePlayerIdx, isReady := range g.rg.GetParticipantStates() {
+			if !isReady {
+				g.rg.Ready(gamePlayerIdx)
+			}
+		}
+	})
+}
+
+func (g *game) restoreParticipantStates(participantStates map[int64]bool) {
+	states := g.rg.GetParticipantStates()
+	for gamePlayerIdx, isReady := range participantStates {
+		if _, exist := states[gamePlayerIdx]; exist && isReady {
+			g.rg.Ready(gamePlayerIdx)
+		}
+	}
+}
+
 func (g *game) runGameStateUpdater() {
 	go func() {
 		for state := range g.incomingStates {
@@ -383,8 +710,7 @@ func (g *game) handleGameState(gs *pokerface.GameState) {
 
 func (g *game) onReadyRequested(gs *pokerface.GameState) {
 	// Preparing ready group to wait for all player ready
-	g.rg.Stop()
-	g.rg.OnCompleted(func(rg *syncsaga.ReadyGroup) {
+	g.resetReadyGroup(func(rg *syncsaga.ReadyGroup) {
 		if _, err := g.ReadyForAll(); err != nil {
 			g.onGameErrorUpdated(gs, err)
 			return
@@ -408,7 +734,7 @@ func (g *game) onReadyRequested(gs *pokerface.GameState) {
 		p.AllowAction(Action_Ready)
 	}
 
-	g.rg.Start()
+	g.startReadyGroup()
 }
 
 func (g *game) onAnteRequested(gs *pokerface.GameState) {
@@ -417,8 +743,7 @@ func (g *game) onAnteRequested(gs *pokerface.GameState) {
 	}
 
 	// Preparing ready group to wait for ante paid from all player
-	g.rg.Stop()
-	g.rg.OnCompleted(func(rg *syncsaga.ReadyGroup) {
+	g.resetReadyGroup(func(rg *syncsaga.ReadyGroup) {
 		gameState, err := g.PayAnte()
 		if err != nil {
 			g.onGameErrorUpdated(gs, err)
@@ -446,13 +771,12 @@ func (g *game) onAnteRequested(gs *pokerface.GameState) {
 		p.AllowAction(Action_Pay)
 	}
 
-	g.rg.Start()
+	g.startReadyGroup()
 }
 
 func (g *game) onBlindsRequested(gs *pokerface.GameState) {
 	// Preparing ready group to wait for blinds
-	g.rg.Stop()
-	g.rg.OnCompleted(func(rg *syncsaga.ReadyGroup) {
+	g.resetReadyGroup(func(rg *syncsaga.ReadyGroup) {
 		gameState, err := g.PayBlinds()
 		if err != nil {
 			g.onGameErrorUpdated(gs, err)
@@ -487,12 +811,29 @@ func (g *game) onBlindsRequested(gs *pokerface.GameState) {
 		}
 	}
 
-	g.rg.Start()
+	g.startReadyGroup()
+}
+
+// payableBlindPosts 本手需在盲注前支付的補盲注與代付全桌前注 (Straddle 於盲注後支付)
+func (g *game) payableBlindPosts() map[int]GameBlindPost {
+	posts := make(map[int]GameBlindPost)
+	for gamePlayerIdx, post := range g.blindPosts {
+		if post.Live > 0 || post.Dead > 0 || post.Ante > 0 {
+			posts[gamePlayerIdx] = post
+		}
+	}
+	return posts
 }
 
 func (g *game) onRoundClosed(gs *pokerface.GameState) {
 	g.onGameRoundClosed(gs)
 
+	// 全下後先詢問玩家是否多次發牌
+	if g.isRunItMultipleAvailable(gs) {
+		g.onRunItMultipleRequestedByAllin(gs)
+		return
+	}
+
 	// Next round automatically
 	gs, err := g.backend.Next(gs)
 	if err != nil {
@@ -503,6 +844,70 @@ func (g *game) onRoundClosed(gs *pokerface.GameState) {
 	g.updateGameState(gs)
 }
 
+/*
+isRunItMultipleAvailable 是否可多次發牌
+  - 至少兩位玩家未棄牌，且最多一位玩家還有籌碼可行動
+  - 公牌尚未發完，且本手尚未詢問過
+*/
+func (g *game) isRunItMultipleAvailable(gs *pokerface.GameState) bool {
+	if g.runItTimes < 2 || g.isRunItRequested || len(gs.Status.Board) >= 5 {
+		return false
+	}
+
+	aliveCount := 0
+	movableCount := 0
+	for _, p := range gs.Players {
+		if p.Fold {
+			continue
+		}
+
+		aliveCount++
+		if p.StackSize > 0 {
+			movableCount++
+		}
+	}
+
+	return aliveCount >= 2 && movableCount <= 1
+}
+
+func (g *game) onRunItMultipleRequestedByAllin(gs *pokerface.GameState) {
+	g.isRunItRequested = true
+
+	// Preparing ready group to wait for all alive players to reply
+	g.resetReadyGroup(func(rg *syncsaga.ReadyGroup) {
+		// reset AllowedActions
+		for _, p := range gs.Players {
+			if funk.Contains(p.AllowedActions, Action_RunItMultiple) {
+				p.AllowedActions = funk.Filter(p.AllowedActions, func(action string) bool {
+					return action != Action_RunItMultiple
+				}).([]string)
+			}
+		}
+
+		// Next round
+		if _, err := g.Next(); err != nil {
+			g.onGameErrorUpdated(gs, err)
+		}
+	})
+
+	g.rg.ResetParticipants()
+	for _, p := range gs.Players {
+		if p.Fold {
+			continue
+		}
+
+		g.rg.Add(int64(p.Idx), false)
+
+		// Allow "run_it_multiple" action
+		p.AllowAction(Action_RunItMultiple)
+	}
+
+	// emit event
+	g.onRunItMultipleRequested(gs)
+
+	g.startReadyGroup()
+}
+
 func (g *game) onGameClosed(gs *pokerface.GameState) {
 	if g.isClosed {
 		return
@@ -7,6 +7,7 @@ type GameBackend interface {
 	ReadyForAll(gs *pokerface.GameState) (*pokerface.GameState, error)
 	PayAnte(gs *pokerface.GameState) (*pokerface.GameState, error)
 	PayBlinds(gs *pokerface.GameState) (*pokerface.GameState, error)
+	PayBlindPosts(gs *pokerface.GameState, posts map[int]GameBlindPost) (*pokerface.GameState, error)
 	Next(gs *pokerface.GameState) (*pokerface.GameState, error)
 	Pay(gs *pokerface.GameState, chips int64) (*pokerface.GameState, error)
 	Fold(gs *pokerface.GameState) (*pokerface.GameState, error)
@@ -0,0 +1,75 @@
+package pokertable
+
+import (
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable/equity"
+)
+
+const (
+	// 全下勝率蒙地卡羅模擬次數 (計算期間會卡住牌局，不宜過多)
+	GameEquityIterations = 2000
+)
+
+/*
+updateGameEquity 計算全下玩家的勝率
+  - 適用時機: 每輪下注結束後
+  - 只有未棄牌玩家至少兩位且最多一位還有籌碼時才計算
+*/
+func (te *tableEngine) updateGameEquity(gs *pokerface.GameState) {
+	holeCards := make([][]string, 0)
+	playerIDs := make([]string, 0)
+	movableCount := 0
+	for _, p := range gs.Players {
+		if p.Fold {
+			continue
+		}
+
+		playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(p.Idx)
+		if playerIdx == UnsetValue {
+			continue
+		}
+
+		if p.StackSize > 0 {
+			movableCount++
+		}
+		holeCards = append(holeCards, p.HoleCards)
+		playerIDs = append(playerIDs, te.table.State.PlayerStates[playerIdx].PlayerID)
+	}
+
+	if len(holeCards) < 2 || movableCount > 1 {
+		return
+	}
+
+	opts := []equity.CalculatorOpt{
+		equity.WithDeck(gs.Meta.Deck),
+		equity.WithCombinationPowers(gs.Meta.CombinationPowers),
+		equity.WithRequiredHoleCardsCount(gs.Meta.RequiredHoleCardsCount),
+		equity.WithIterations(GameEquityIterations),
+	}
+	if te.equityRand != nil {
+		opts = append(opts, equity.WithRandom(te.equityRand))
+	}
+
+	result, err := equity.NewCalculator(opts...).Calculate(holeCards, gs.Status.Board)
+	if err != nil {
+		te.emitErrorEvent("updateGameEquity#Calculate", "", err)
+		return
+	}
+
+	gameEquity := &TableGameEquity{
+		Round:        gs.Status.Round,
+		Board:        append([]string{}, gs.Status.Board...),
+		IsExhaustive: result.IsExhaustive,
+		Samples:      result.Samples,
+		Players:      make([]TablePlayerEquity, 0, len(result.Players)),
+	}
+	for i, playerEquity := range result.Players {
+		gameEquity.Players = append(gameEquity.Players, TablePlayerEquity{
+			PlayerID: playerIDs[i],
+			Win:      playerEquity.Win,
+			Tie:      playerEquity.Tie,
+			Equity:   playerEquity.Equity,
+		})
+	}
+	te.table.State.GameEquity = gameEquity
+}
@@ -0,0 +1,284 @@
+package pokertable
+
+import (
+	"sort"
+
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokerface/settlement"
+)
+
+type TableGameResult struct {
+	CompetitionID string                  `json:"competition_id"` // 賽事 ID
+	TableID       string                  `json:"table_id"`       // 桌次 ID
+	GameID        string                  `json:"game_id"`        // 遊戲 ID
+	GameCount     int                     `json:"game_count"`     // 執行牌局遊戲次數 (遊戲跑幾輪)
+	Board         []string                `json:"board"`          // 公牌
+	Boards        [][]string              `json:"boards"`         // 各次發牌的公牌 (未多次發牌時只有一組)
+	Rake          int64                   `json:"rake"`           // 本手抽水總量
+	BombPot       int64                   `json:"bomb_pot"`       // 本手 Bomb Pot 每位玩家支付的籌碼量 (0 表示非 Bomb Pot)
+	Pots          []TableGameResultPot    `json:"pots"`           // 各池結算結果 (index 0 為主池，其餘為邊池)
+	Players       []TableGameResultPlayer `json:"players"`        // 各玩家結算結果
+}
+
+type TableGameResultPot struct {
+	Total        int64                        `json:"total"`        // 池總量 (含死盲，已扣除抽水)
+	Rake         int64                        `json:"rake"`         // 此池抽水量
+	Contributors []TableGameResultContributor `json:"contributors"` // 投入此池的玩家 (含已棄牌玩家)
+	Winners      []TableGameResultWinner      `json:"winners"`      // 此池贏家
+}
+
+type TableGameResultContributor struct {
+	PlayerID string `json:"player_id"` // 玩家 ID
+	Chips    int64  `json:"chips"`     // 投入此池的籌碼量
+}
+
+type TableGameResultWinner struct {
+	PlayerID    string   `json:"player_id"`   // 玩家 ID
+	Chips       int64    `json:"chips"`       // 從此池獲得的籌碼量
+	Combination string   `json:"combination"` // 牌型 (未攤牌時為空，多次發牌時為第一次公牌的牌型)
+	Cards       []string `json:"cards"`       // 組成牌型的牌
+	Power       int      `json:"power"`       // 牌力 (數值越大越強)
+	Rank        int      `json:"rank"`        // 本手未棄牌玩家的牌力名次 (1 表示最大，0 表示無牌型)
+}
+
+type TableGameResultPlayer struct {
+	PlayerID  string   `json:"player_id"` // 玩家 ID
+	Seat      int      `json:"seat"`      // 座位編號 0 ~ 8
+	Positions []string `json:"positions"` // 場上位置
+	IsFold    bool     `json:"is_fold"`   // 是否棄牌
+	Final     int64    `json:"final"`     // 結算後籌碼
+	Changed   int64    `json:"changed"`   // 本手輸贏籌碼
+}
+
+/*
+newGameResult 整理本手結算結果
+  - 適用時機: 本手結算後 (死盲與抽水皆已計入)
+*/
+func (te *tableEngine) newGameResult() TableGameResult {
+	gs := te.table.State.GameState
+	result := TableGameResult{
+		CompetitionID: te.table.Meta.CompetitionID,
+		TableID:       te.table.ID,
+		GameID:        gs.GameID,
+		GameCount:     te.table.State.GameCount,
+		Board:         gs.Status.Board,
+		Boards:        [][]string{gs.Status.Board},
+		Rake:          0,
+		BombPot:       te.table.State.GameBombPot,
+		Pots:          make([]TableGameResultPot, 0),
+		Players:       make([]TableGameResultPlayer, 0),
+	}
+	if te.table.State.GameRake != nil {
+		result.Rake = te.table.State.GameRake.Total
+	}
+	if te.table.State.GameRunout != nil && len(te.table.State.GameRunout.Boards) > 0 {
+		result.Boards = te.table.State.GameRunout.Boards
+	}
+
+	playerIDs := make(map[int]string)
+	for gamePlayerIdx, playerIdx := range te.table.State.GamePlayerIndexes {
+		playerIDs[gamePlayerIdx] = te.table.State.PlayerStates[playerIdx].PlayerID
+	}
+
+	// 牌力名次
+	ranks := make(map[int]int)
+	for _, player := range gs.Players {
+		if player.Fold || player.Combination == nil {
+			continue
+		}
+
+		rank := 1
+		for _, opponent := range gs.Players {
+			if !opponent.Fold && opponent.Combination != nil && opponent.Combination.Power > player.Combination.Power {
+				rank++
+			}
+		}
+		ranks[player.Idx] = rank
+	}
+
+	// 各池投入籌碼 (依池的籌碼級距拆分玩家投入量)
+	prevLevel := int64(0)
+	for potIdx, pot := range gs.Result.Pots {
+		potResult := TableGameResultPot{
+			Total:        pot.Total,
+			Rake:         0,
+			Contributors: make([]TableGameResultContributor, 0),
+			Winners:      make([]TableGameResultWinner, 0),
+		}
+		if te.table.State.GameRake != nil && potIdx < len(te.table.State.GameRake.Pots) {
+			potResult.Rake = te.table.State.GameRake.Pots[potIdx]
+		}
+
+		if potIdx < len(gs.Status.Pots) {
+			level := gs.Status.Pots[potIdx].Level
+			for _, player := range gs.Players {
+				chips := player.Pot + player.Wager
+				if chips > level {
+					chips = level
+				}
+				chips -= prevLevel
+
+				// 死盲與代付全桌前注併入主池
+				if post, exist := te.table.State.GameBlindPosts[player.Idx]; exist && potIdx == 0 {
+					chips += post.Dead + post.Ante
+				}
+
+				if chips > 0 {
+					potResult.Contributors = append(potResult.Contributors, TableGameResultContributor{
+						PlayerID: playerIDs[player.Idx],
+						Chips:    chips,
+					})
+				}
+			}
+			prevLevel = level
+		}
+
+		for _, winner := range pot.Winners {
+			winnerResult := TableGameResultWinner{
+				PlayerID: playerIDs[winner.Idx],
+				Chips:    winner.Withdraw,
+				Cards:    make([]string, 0),
+				Rank:     ranks[winner.Idx],
+			}
+			if p := gs.GetPlayer(winner.Idx); p != nil && p.Combination != nil {
+				winnerResult.Combination = p.Combination.Type
+				winnerResult.Cards = p.Combination.Cards
+				winnerResult.Power = p.Combination.Power
+			}
+			potResult.Winners = append(potResult.Winners, winnerResult)
+		}
+
+		result.Pots = append(result.Pots, potResult)
+	}
+
+	// 玩家輸贏
+	for _, player := range gs.Result.Players {
+		playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(player.Idx)
+		if playerIdx == UnsetValue {
+			continue
+		}
+
+		playerState := te.table.State.PlayerStates[playerIdx]
+		playerResult := TableGameResultPlayer{
+			PlayerID:  playerState.PlayerID,
+			Seat:      playerState.Seat,
+			Positions: playerState.Positions,
+			IsFold:    false,
+			Final:     player.Final,
+			Changed:   player.Changed,
+		}
+		if p := gs.GetPlayer(player.Idx); p != nil {
+			playerResult.IsFold = p.Fold
+		}
+		result.Players = append(result.Players, playerResult)
+	}
+
+	return result
+}
+
+/*
+settlePotWinners 補齊各池平手的贏家
+  - 適用時機: 本手結算時，併入死盲、多次發牌與抽水之前
+  - pokerface 只在分得籌碼多於投入量時記錄贏家，平手剛好拿回投入量的級距不計入 Withdraw
+  - 依玩家投入量重建各池的級距重新計算 Withdraw (含投入量)，只有一位玩家投入的級距為未被跟注的下注，不列為贏家
+  - 玩家輸贏 (Final, Changed) 不受影響
+*/
+func (te *tableEngine) settlePotWinners() {
+	gs := te.table.State.GameState
+	if gs.Result == nil {
+		return
+	}
+
+	prevLevel := int64(0)
+	for potIdx, pot := range gs.Result.Pots {
+		if potIdx >= len(gs.Status.Pots) {
+			break
+		}
+
+		level := gs.Status.Pots[potIdx].Level
+		withdraws := potLevelWithdraws(gs, prevLevel, level)
+		prevLevel = level
+
+		pot.Winners = make([]*settlement.Winner, 0)
+		for _, p := range gs.Players {
+			if withdraw := withdraws[p.Idx]; withdraw > 0 {
+				pot.Winners = append(pot.Winners, &settlement.Winner{
+					Idx:      p.Idx,
+					Withdraw: withdraw,
+				})
+			}
+		}
+	}
+}
+
+// potLevelWithdraws 計算投入量介於 (prevLevel, level] 的各級距由未棄牌的最大牌力玩家平分 (key: game player idx)
+func potLevelWithdraws(gs *pokerface.GameState, prevLevel int64, level int64) map[int]int64 {
+	chips := make(map[int]int64)
+	levels := make([]int64, 0)
+	for _, p := range gs.Players {
+		c := p.Pot + p.Wager
+		if c > level {
+			c = level
+		}
+		if c <= prevLevel {
+			continue
+		}
+
+		chips[p.Idx] = c
+		if !funk.ContainsInt64(levels, c) {
+			levels = append(levels, c)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i] < levels[j]
+	})
+
+	withdraws := make(map[int]int64)
+	prev := prevLevel
+	for _, l := range levels {
+		contributorCount := 0
+		winners := make([]int, 0)
+		best := UnsetValue
+		for _, p := range gs.Players {
+			if chips[p.Idx] < l {
+				continue
+			}
+
+			contributorCount++
+			if p.Fold {
+				continue
+			}
+
+			power := 0
+			if p.Combination != nil {
+				power = p.Combination.Power
+			}
+			if power > best {
+				best = power
+				winners = []int{p.Idx}
+			} else if power == best {
+				winners = append(winners, p.Idx)
+			}
+		}
+
+		// 未被跟注的下注
+		total := (l - prev) * int64(contributorCount)
+		prev = l
+		if contributorCount < 2 || len(winners) == 0 {
+			continue
+		}
+
+		based := total / int64(len(winners))
+		remainder := total % int64(len(winners))
+		for i, gamePlayerIdx := range winners {
+			reward := based
+			if int64(i) < remainder {
+				reward += 1
+			}
+			withdraws[gamePlayerIdx] += reward
+		}
+	}
+
+	return withdraws
+}
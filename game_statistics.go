@@ -140,10 +140,8 @@ func (te *tableEngine) refreshThreeBet(playerState *TablePlayerState, playerIdx
 	}
 }
 
+// updateCurrentPlayerGameStatistics 更新當前玩家的統計機會 (需持有 te.lock，由 applyGameState 呼叫)
 func (te *tableEngine) updateCurrentPlayerGameStatistics(gs *pokerface.GameState) {
-	te.lock.Lock()
-	defer te.lock.Unlock()
-
 	// check current player
 	currentGamePlayerIdx := gs.Status.CurrentPlayer
 	currentPlayerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(currentGamePlayerIdx)
@@ -217,7 +215,7 @@ func (te *tableEngine) isVPIPChance(gamePlayerIdx int, gs *pokerface.GameState)
 	return false
 }
 
-// isPFRChance: preflop 時，並且前位玩家皆跟注或棄牌
+// isPFRChance: preflop 時，並且前位玩家皆跟注或棄牌 (Straddle 與盲注相同視為強制下注，不計入加注)
 func (te *tableEngine) isPFRChance(gamePlayerIdx int, gs *pokerface.GameState) bool {
 	if !te.validateGameStatisticGameState(gamePlayerIdx, gs) {
 		return false
@@ -0,0 +1,457 @@
+package pokertable
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+)
+
+var (
+	ErrHandHistoryGameNotSettled = errors.New("hand history: game is not settled")
+)
+
+type HandHistoryOptions struct {
+	SiteName     string // 平台名稱
+	HandNumber   int64  // 牌局編號 (UnsetValue 表示使用 GameCount)
+	HeroPlayerID string // 視角玩家 ID (輸出其底牌 Dealt to)
+}
+
+func NewHandHistoryOptions() *HandHistoryOptions {
+	return &HandHistoryOptions{
+		SiteName:     "PokerStars",
+		HandNumber:   UnsetValue,
+		HeroPlayerID: "",
+	}
+}
+
+var handHistoryCombinationNames = map[string]string{
+	"HighCard":      "high card",
+	"Pair":          "a pair",
+	"TwoPair":       "two pair",
+	"ThreeOfAKind":  "three of a kind",
+	"Straight":      "a straight",
+	"Flush":         "a flush",
+	"FullHouse":     "a full house",
+	"FourOfAKind":   "four of a kind",
+	"StraightFlush": "a straight flush",
+}
+
+var handHistoryRoundNames = map[string]string{
+	GameRound_Flop:  "Flop",
+	GameRound_Turn:  "Turn",
+	GameRound_River: "River",
+}
+
+type handHistoryPlayer struct {
+	gamePlayerIdx int
+	playerID      string
+	seat          int
+	positions     []string
+	state         *pokerface.PlayerState
+	tableAnte     int64 // 代付全桌前注 (開局前已從籌碼扣除)
+	straddle      int64 // Straddle 籌碼量
+	foldRound     string
+	collected     int64
+	didBet        bool
+}
+
+/*
+ExportHandHistory 匯出 PokerStars 格式的牌局紀錄
+  - 適用時機: 本手結算後 (TableStateStatus_TableGameSettled)，提供玩家匯入 HUD/追蹤軟體
+  - gameActions: 本手收集到的玩家動作 (OnGamePlayerActionUpdated)，其他手的動作會被忽略
+*/
+func ExportHandHistory(table *Table, gameActions []TablePlayerGameAction, opts *HandHistoryOptions) (string, error) {
+	if table == nil || table.State == nil || table.State.GameState == nil || table.State.GameState.Result == nil {
+		return "", ErrHandHistoryGameNotSettled
+	}
+
+	if opts == nil {
+		opts = NewHandHistoryOptions()
+	}
+
+	gs := table.State.GameState
+
+	// 本手玩家 (依座位排序)
+	players := make([]*handHistoryPlayer, 0)
+	playerData := make(map[string]*handHistoryPlayer)
+	for gamePlayerIdx, playerIdx := range table.State.GamePlayerIndexes {
+		if playerIdx >= len(table.State.PlayerStates) || gamePlayerIdx >= len(gs.Players) {
+			continue
+		}
+
+		player := table.State.PlayerStates[playerIdx]
+		p := &handHistoryPlayer{
+			gamePlayerIdx: gamePlayerIdx,
+			playerID:      player.PlayerID,
+			seat:          player.Seat,
+			positions:     gs.Players[gamePlayerIdx].Positions,
+			state:         gs.Players[gamePlayerIdx],
+			tableAnte:     table.State.GameBlindPosts[gamePlayerIdx].Ante,
+			straddle:      table.State.GameBlindPosts[gamePlayerIdx].Straddle,
+		}
+		players = append(players, p)
+		playerData[p.playerID] = p
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].seat < players[j].seat
+	})
+
+	var sb strings.Builder
+	writeLine := func(format string, args ...interface{}) {
+		sb.WriteString(fmt.Sprintf(format, args...))
+		sb.WriteString("\n")
+	}
+
+	// Header
+	handNumber := opts.HandNumber
+	if handNumber == UnsetValue {
+		handNumber = int64(table.State.GameCount)
+	}
+
+	// Bomb Pot 不支付盲注 (Bomb Pot 籌碼以前注記錄)，標題仍顯示本手盲注等級
+	smallBlind := gs.Meta.Blind.SB
+	if table.State.GameBombPot > 0 && table.State.GameBlindState != nil {
+		smallBlind = table.State.GameBlindState.SB
+	}
+	writeLine("%s Hand #%d: %s (%d/%d) - %s UTC",
+		opts.SiteName,
+		handNumber,
+		handHistoryGameType(table.Meta.Rule, table.Meta.BettingStructure),
+		smallBlind,
+		gs.Meta.Blind.BB,
+		time.Unix(gs.CreatedAt, 0).UTC().Format("2006/01/02 15:04:05"),
+	)
+	writeLine("Table '%s' %d-max Seat #%d is the button", table.ID, table.Meta.TableMaxSeatCount, table.State.CurrentDealerSeat+1)
+	for _, p := range players {
+		writeLine("Seat %d: %s (%d in chips)", p.seat+1, p.playerID, p.state.Bankroll+p.tableAnte)
+	}
+
+	// Antes & Blinds
+	wagers := make(map[string]int64)
+	stacks := make(map[string]int64)
+	for _, p := range players {
+		stacks[p.playerID] = p.state.Bankroll + p.tableAnte
+	}
+	post := func(p *handHistoryPlayer, chips int64, isWager bool) int64 {
+		if chips > stacks[p.playerID] {
+			chips = stacks[p.playerID]
+		}
+		stacks[p.playerID] -= chips
+		if isWager {
+			wagers[p.playerID] += chips
+			p.didBet = p.didBet || chips > 0
+		}
+		return chips
+	}
+
+	if gs.Meta.Ante > 0 {
+		for _, p := range players {
+			chips := post(p, gs.Meta.Ante, false)
+			writeLine("%s: posts the ante %d%s", p.playerID, chips, handHistoryAllInSuffix(stacks[p.playerID]))
+		}
+	}
+
+	for _, p := range players {
+		if p.tableAnte > 0 {
+			chips := post(p, p.tableAnte, false)
+			writeLine("%s: posts the ante %d%s", p.playerID, chips, handHistoryAllInSuffix(stacks[p.playerID]))
+		}
+	}
+
+	blinds := []struct {
+		position string
+		name     string
+		chips    int64
+	}{
+		{Position_Dealer, "button blind", gs.Meta.Blind.Dealer},
+		{Position_SB, "small blind", gs.Meta.Blind.SB},
+		{Position_BB, "big blind", gs.Meta.Blind.BB},
+	}
+	for _, blind := range blinds {
+		if blind.chips <= 0 {
+			continue
+		}
+
+		for _, p := range players {
+			if funk.ContainsString(p.positions, blind.position) {
+				chips := post(p, blind.chips, true)
+				writeLine("%s: posts %s %d%s", p.playerID, blind.name, chips, handHistoryAllInSuffix(stacks[p.playerID]))
+			}
+		}
+	}
+
+	for _, p := range players {
+		if p.straddle > 0 {
+			chips := post(p, p.straddle, true)
+			writeLine("%s: posts straddle %d%s", p.playerID, chips, handHistoryAllInSuffix(stacks[p.playerID]))
+		}
+	}
+
+	// Hole Cards
+	writeLine("*** HOLE CARDS ***")
+	if hero, exist := playerData[opts.HeroPlayerID]; exist && len(hero.state.HoleCards) > 0 {
+		writeLine("Dealt to %s [%s]", hero.playerID, handHistoryCards(hero.state.HoleCards))
+	}
+
+	// Streets
+	roundActions := make(map[string][]TablePlayerGameAction)
+	for _, action := range gameActions {
+		if action.GameID != gs.GameID {
+			continue
+		}
+		roundActions[action.Round] = append(roundActions[action.Round], action)
+	}
+
+	board := gs.Status.Board
+	streets := []struct {
+		round    string
+		title    string
+		cards    []string
+		previous []string
+	}{
+		{GameRound_Preflop, "", nil, nil},
+		{GameRound_Flop, "FLOP", handHistoryBoard(board, 0, 3), nil},
+		{GameRound_Turn, "TURN", handHistoryBoard(board, 3, 4), handHistoryBoard(board, 0, 3)},
+		{GameRound_River, "RIVER", handHistoryBoard(board, 4, 5), handHistoryBoard(board, 0, 4)},
+	}
+
+	uncalled := make(map[string]int64)
+	for _, street := range streets {
+		if street.round != GameRound_Preflop {
+			if len(street.cards) == 0 {
+				break
+			}
+
+			if len(street.previous) > 0 {
+				writeLine("*** %s *** [%s] [%s]", street.title, handHistoryCards(street.previous), handHistoryCards(street.cards))
+			} else {
+				writeLine("*** %s *** [%s]", street.title, handHistoryCards(street.cards))
+			}
+
+			wagers = make(map[string]int64)
+		}
+
+		currentWager := int64(0)
+		for _, wager := range wagers {
+			if wager > currentWager {
+				currentWager = wager
+			}
+		}
+
+		for _, action := range roundActions[street.round] {
+			p, exist := playerData[action.PlayerID]
+			if !exist {
+				continue
+			}
+
+			switch action.Action {
+			case WagerAction_Fold:
+				p.foldRound = street.round
+				writeLine("%s: folds", p.playerID)
+			case WagerAction_Check:
+				writeLine("%s: checks", p.playerID)
+			case WagerAction_Call:
+				chips := post(p, action.Chips, true)
+				writeLine("%s: calls %d%s", p.playerID, chips, handHistoryAllInSuffix(stacks[p.playerID]))
+			case WagerAction_Bet:
+				chips := post(p, action.Chips-wagers[p.playerID], true)
+				currentWager = wagers[p.playerID]
+				writeLine("%s: bets %d%s", p.playerID, chips, handHistoryAllInSuffix(stacks[p.playerID]))
+			case WagerAction_Raise:
+				post(p, action.Chips-wagers[p.playerID], true)
+				writeLine("%s: raises %d to %d%s", p.playerID, wagers[p.playerID]-currentWager, wagers[p.playerID], handHistoryAllInSuffix(stacks[p.playerID]))
+				currentWager = wagers[p.playerID]
+			case WagerAction_AllIn:
+				chips := post(p, action.Chips, true)
+				if wagers[p.playerID] <= currentWager {
+					writeLine("%s: calls %d and is all-in", p.playerID, chips)
+				} else if currentWager == 0 {
+					writeLine("%s: bets %d and is all-in", p.playerID, chips)
+					currentWager = wagers[p.playerID]
+				} else {
+					writeLine("%s: raises %d to %d and is all-in", p.playerID, wagers[p.playerID]-currentWager, wagers[p.playerID])
+					currentWager = wagers[p.playerID]
+				}
+			}
+		}
+
+		// 沒有人跟注的下注退回
+		topPlayerID, topWager, secondWager := "", int64(0), int64(0)
+		for _, p := range players {
+			wager := wagers[p.playerID]
+			if wager > topWager {
+				topPlayerID, secondWager, topWager = p.playerID, topWager, wager
+			} else if wager > secondWager {
+				secondWager = wager
+			}
+		}
+		if topWager > secondWager {
+			uncalled[topPlayerID] += topWager - secondWager
+			stacks[topPlayerID] += topWager - secondWager
+			writeLine("Uncalled bet (%d) returned to %s", topWager-secondWager, topPlayerID)
+		}
+	}
+
+	// Showdown
+	for _, pot := range gs.Result.Pots {
+		for _, winner := range pot.Winners {
+			for _, p := range players {
+				if p.gamePlayerIdx == winner.Idx {
+					p.collected += winner.Withdraw
+				}
+			}
+		}
+	}
+
+	alivePlayers := funk.Filter(players, func(p *handHistoryPlayer) bool {
+		return !p.state.Fold
+	}).([]*handHistoryPlayer)
+	isShowdown := len(alivePlayers) > 1
+	if isShowdown {
+		writeLine("*** SHOW DOWN ***")
+		for _, p := range alivePlayers {
+			writeLine("%s: shows [%s] (%s)", p.playerID, handHistoryCards(p.state.HoleCards), handHistoryCombination(p.state))
+		}
+	}
+
+	rake := int64(0)
+	if table.State.GameRake != nil {
+		rake = table.State.GameRake.Total
+	}
+
+	// 結算後的池已併入死盲並扣除抽水
+	totalPot := rake
+	for _, pot := range gs.Result.Pots {
+		totalPot += pot.Total
+	}
+	for _, chips := range uncalled {
+		totalPot -= chips
+	}
+
+	for _, p := range players {
+		if collected := p.collected - uncalled[p.playerID]; collected > 0 {
+			writeLine("%s collected %d from pot", p.playerID, collected)
+		}
+	}
+	if !isShowdown {
+		for _, p := range alivePlayers {
+			writeLine("%s: doesn't show hand", p.playerID)
+		}
+	}
+
+	// Summary
+	writeLine("*** SUMMARY ***")
+	writeLine("Total pot %d | Rake %d", totalPot, rake)
+	if len(board) > 0 {
+		writeLine("Board [%s]", handHistoryCards(board))
+	}
+	for _, p := range players {
+		writeLine("Seat %d: %s%s %s", p.seat+1, p.playerID, handHistoryPositions(p.seat == table.State.CurrentDealerSeat, p.positions), handHistorySummary(p, isShowdown, uncalled[p.playerID]))
+	}
+
+	return sb.String(), nil
+}
+
+func handHistoryGameType(rule, bettingStructure string) string {
+	game := "Hold'em"
+	switch rule {
+	case CompetitionRule_ShortDeck:
+		game = "6+ Hold'em"
+	case CompetitionRule_Omaha:
+		game = "Omaha"
+	}
+
+	switch bettingStructure {
+	case BettingStructure_PotLimit:
+		return fmt.Sprintf("%s Pot Limit", game)
+	case BettingStructure_FixedLimit:
+		return fmt.Sprintf("%s Limit", game)
+	default:
+		return fmt.Sprintf("%s No Limit", game)
+	}
+}
+
+// handHistoryCards 將 pokerface 牌面 (e.g. SA, HT) 轉為 PokerStars 牌面 (e.g. As, Th)
+func handHistoryCards(cards []string) string {
+	converted := make([]string, 0, len(cards))
+	for _, card := range cards {
+		if len(card) != 2 {
+			converted = append(converted, card)
+			continue
+		}
+		converted = append(converted, fmt.Sprintf("%s%s", card[1:], strings.ToLower(card[:1])))
+	}
+	return strings.Join(converted, " ")
+}
+
+func handHistoryBoard(board []string, from, to int) []string {
+	if len(board) < to {
+		return nil
+	}
+	return board[from:to]
+}
+
+func handHistoryAllInSuffix(stack int64) string {
+	if stack <= 0 {
+		return " and is all-in"
+	}
+	return ""
+}
+
+func handHistoryCombination(player *pokerface.PlayerState) string {
+	if player.Combination == nil {
+		return ""
+	}
+
+	name, exist := handHistoryCombinationNames[player.Combination.Type]
+	if !exist {
+		name = player.Combination.Type
+	}
+
+	if len(player.Combination.Cards) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s, %s", name, handHistoryCards(player.Combination.Cards))
+}
+
+func handHistoryPositions(isButton bool, positions []string) string {
+	names := ""
+	if isButton {
+		names += " (button)"
+	}
+	if funk.ContainsString(positions, Position_SB) {
+		names += " (small blind)"
+	}
+	if funk.ContainsString(positions, Position_BB) {
+		names += " (big blind)"
+	}
+	return names
+}
+
+func handHistorySummary(p *handHistoryPlayer, isShowdown bool, uncalled int64) string {
+	collected := p.collected - uncalled
+
+	if p.state.Fold {
+		if p.foldRound == GameRound_Preflop || p.foldRound == "" {
+			if !p.didBet {
+				return "folded before Flop (didn't bet)"
+			}
+			return "folded before Flop"
+		}
+		return fmt.Sprintf("folded on the %s", handHistoryRoundNames[p.foldRound])
+	}
+
+	if !isShowdown {
+		return fmt.Sprintf("collected (%d)", collected)
+	}
+
+	if collected > 0 {
+		return fmt.Sprintf("showed [%s] and won (%d) with %s", handHistoryCards(p.state.HoleCards), collected, handHistoryCombination(p.state))
+	}
+	return fmt.Sprintf("showed [%s] and lost with %s", handHistoryCards(p.state.HoleCards), handHistoryCombination(p.state))
+}
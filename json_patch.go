@@ -0,0 +1,292 @@
+package pokertable
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrJSONPatchInvalidPath          = errors.New("json patch: invalid path")
+	ErrJSONPatchPathNotFound         = errors.New("json patch: path not found")
+	ErrJSONPatchUnsupportedOperation = errors.New("json patch: unsupported operation")
+)
+
+const (
+	JSONPatchOp_Add     = "add"
+	JSONPatchOp_Remove  = "remove"
+	JSONPatchOp_Replace = "replace"
+)
+
+// JSONPatchOperation JSON Patch (RFC 6902) 操作
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`              // 操作 (add, remove, replace)
+	Path  string          `json:"path"`            // JSON Pointer (RFC 6901)
+	Value json.RawMessage `json:"value,omitempty"` // 新的值 (remove 時為空)
+}
+
+/*
+createJSONPatch 產生 JSON Patch (RFC 6902)，套用到 original 後會得到 modified
+  - 物件依 key 排序比對，產生的操作順序固定
+  - 陣列長度相同時逐一比對，長度不同時比對共同部分後於尾端新增或移除
+*/
+func createJSONPatch(original, modified interface{}) ([]JSONPatchOperation, error) {
+	ops := make([]JSONPatchOperation, 0)
+	if err := diffJSONValue(&ops, "", original, modified); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func diffJSONValue(ops *[]JSONPatchOperation, path string, original, modified interface{}) error {
+	originalObject, isOriginalObject := original.(map[string]interface{})
+	modifiedObject, isModifiedObject := modified.(map[string]interface{})
+	if isOriginalObject && isModifiedObject {
+		keys := make([]string, 0, len(originalObject)+len(modifiedObject))
+		for key := range originalObject {
+			keys = append(keys, key)
+		}
+		for key := range modifiedObject {
+			if _, exist := originalObject[key]; !exist {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			childPath := path + "/" + escapeJSONPointer(key)
+			originalValue, isOriginalExist := originalObject[key]
+			modifiedValue, isModifiedExist := modifiedObject[key]
+			switch {
+			case !isModifiedExist:
+				*ops = append(*ops, JSONPatchOperation{Op: JSONPatchOp_Remove, Path: childPath})
+			case !isOriginalExist:
+				if err := appendJSONPatchValue(ops, JSONPatchOp_Add, childPath, modifiedValue); err != nil {
+					return err
+				}
+			default:
+				if err := diffJSONValue(ops, childPath, originalValue, modifiedValue); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	originalArray, isOriginalArray := original.([]interface{})
+	modifiedArray, isModifiedArray := modified.([]interface{})
+	if isOriginalArray && isModifiedArray {
+		commonCount := len(originalArray)
+		if len(modifiedArray) < commonCount {
+			commonCount = len(modifiedArray)
+		}
+
+		for i := 0; i < commonCount; i++ {
+			if err := diffJSONValue(ops, fmt.Sprintf("%s/%d", path, i), originalArray[i], modifiedArray[i]); err != nil {
+				return err
+			}
+		}
+
+		// 從尾端移除，避免 index 位移
+		for i := len(originalArray) - 1; i >= commonCount; i-- {
+			*ops = append(*ops, JSONPatchOperation{Op: JSONPatchOp_Remove, Path: fmt.Sprintf("%s/%d", path, i)})
+		}
+
+		for i := commonCount; i < len(modifiedArray); i++ {
+			if err := appendJSONPatchValue(ops, JSONPatchOp_Add, fmt.Sprintf("%s/%d", path, i), modifiedArray[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !reflect.DeepEqual(original, modified) {
+		return appendJSONPatchValue(ops, JSONPatchOp_Replace, path, modified)
+	}
+
+	return nil
+}
+
+func appendJSONPatchValue(ops *[]JSONPatchOperation, op string, path string, value interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	*ops = append(*ops, JSONPatchOperation{Op: op, Path: path, Value: encoded})
+	return nil
+}
+
+/*
+applyJSONPatch 套用 JSON Patch (RFC 6902)
+  - 支援 add, remove, replace
+  - 路徑為空字串時代表整份文件
+*/
+func applyJSONPatch(document interface{}, ops []JSONPatchOperation) (interface{}, error) {
+	for _, op := range ops {
+		var value interface{}
+		if op.Op != JSONPatchOp_Remove {
+			if err := decodeJSONWithNumber(op.Value, &value); err != nil {
+				return nil, err
+			}
+		}
+
+		tokens, err := parseJSONPointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		document, err = applyJSONPatchOperation(document, tokens, op.Op, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s %s", err, op.Op, op.Path)
+		}
+	}
+
+	return document, nil
+}
+
+func applyJSONPatchOperation(target interface{}, tokens []string, op string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		switch op {
+		case JSONPatchOp_Add, JSONPatchOp_Replace:
+			return value, nil
+		case JSONPatchOp_Remove:
+			return nil, nil
+		}
+		return nil, ErrJSONPatchUnsupportedOperation
+	}
+
+	token := tokens[0]
+	isLast := len(tokens) == 1
+
+	switch container := target.(type) {
+	case map[string]interface{}:
+		child, exist := container[token]
+		if !isLast {
+			if !exist {
+				return nil, ErrJSONPatchPathNotFound
+			}
+
+			updated, err := applyJSONPatchOperation(child, tokens[1:], op, value)
+			if err != nil {
+				return nil, err
+			}
+			container[token] = updated
+			return container, nil
+		}
+
+		switch op {
+		case JSONPatchOp_Add:
+			container[token] = value
+		case JSONPatchOp_Replace:
+			if !exist {
+				return nil, ErrJSONPatchPathNotFound
+			}
+			container[token] = value
+		case JSONPatchOp_Remove:
+			if !exist {
+				return nil, ErrJSONPatchPathNotFound
+			}
+			delete(container, token)
+		default:
+			return nil, ErrJSONPatchUnsupportedOperation
+		}
+		return container, nil
+
+	case []interface{}:
+		// "-" 代表陣列尾端 (只用於 add)
+		idx := len(container)
+		if token != "-" {
+			parsed, err := strconv.Atoi(token)
+			if err != nil || parsed < 0 {
+				return nil, ErrJSONPatchInvalidPath
+			}
+			idx = parsed
+		}
+
+		if !isLast {
+			if idx >= len(container) {
+				return nil, ErrJSONPatchPathNotFound
+			}
+
+			updated, err := applyJSONPatchOperation(container[idx], tokens[1:], op, value)
+			if err != nil {
+				return nil, err
+			}
+			container[idx] = updated
+			return container, nil
+		}
+
+		switch op {
+		case JSONPatchOp_Add:
+			if idx > len(container) {
+				return nil, ErrJSONPatchPathNotFound
+			}
+			container = append(container, nil)
+			copy(container[idx+1:], container[idx:])
+			container[idx] = value
+		case JSONPatchOp_Replace:
+			if idx >= len(container) {
+				return nil, ErrJSONPatchPathNotFound
+			}
+			container[idx] = value
+		case JSONPatchOp_Remove:
+			if idx >= len(container) {
+				return nil, ErrJSONPatchPathNotFound
+			}
+			container = append(container[:idx], container[idx+1:]...)
+		default:
+			return nil, ErrJSONPatchUnsupportedOperation
+		}
+		return container, nil
+	}
+
+	return nil, ErrJSONPatchPathNotFound
+}
+
+// parseJSONPointer 解析 JSON Pointer (RFC 6901)
+func parseJSONPointer(path string) ([]string, error) {
+	if path == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(path, "/") {
+		return nil, ErrJSONPatchInvalidPath
+	}
+
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func toJSONObject(v interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]interface{}
+	if err := decodeJSONWithNumber(encoded, &object); err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// decodeJSONWithNumber 保留數字原始精度 (避免 int64 轉 float64 失真)
+func decodeJSONWithNumber(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
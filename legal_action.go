@@ -0,0 +1,96 @@
+package pokertable
+
+import (
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+)
+
+/*
+LegalActions 計算玩家當前可執行的動作與籌碼範圍
+  - 以 pokerface 的 AllowedActions 為基礎，再套用下注結構限制
+  - 已達加注次數上限時移除加注，下注結構不允許全下時移除全下
+  - 玩家不在遊戲中時回傳 nil
+*/
+func (t Table) LegalActions(playerID string) *TableLegalActions {
+	if t.State == nil || t.State.GameState == nil {
+		return nil
+	}
+
+	gs := t.State.GameState
+	gamePlayerIdx := t.GamePlayerIndex(playerID)
+	player := gs.GetPlayer(gamePlayerIdx)
+	if player == nil {
+		return nil
+	}
+
+	legalActions := &TableLegalActions{
+		PlayerID: playerID,
+		Actions:  make([]string, 0),
+	}
+
+	limit := NewTableWagerLimit(t.Meta, gs, gamePlayerIdx, t.State.GameRoundBetCount)
+	for _, action := range player.AllowedActions {
+		switch action {
+		case WagerAction_Call:
+			legalActions.CallAmount = minInt64(gs.Status.CurrentWager-player.Wager, player.StackSize)
+		case WagerAction_AllIn:
+			if !isAllinAllowed(gs, player, limit) {
+				continue
+			}
+			legalActions.AllinAmount = player.StackSize
+		case WagerAction_Bet:
+			legalActions.MinBet = limit.MinBet
+			legalActions.MaxBet = limit.MaxBet
+		case WagerAction_Raise:
+			if limit.IsRaiseCapped {
+				continue
+			}
+			legalActions.MinRaise = limit.MinRaise
+			legalActions.MaxRaise = limit.MaxRaise
+		}
+
+		legalActions.Actions = append(legalActions.Actions, action)
+	}
+
+	return legalActions
+}
+
+// PlayerLegalActions 取得玩家可執行動作與籌碼範圍
+func (te *tableEngine) PlayerLegalActions(playerID string) (*TableLegalActions, error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+
+	if te.table == nil {
+		return nil, ErrGamePlayerNotFound
+	}
+
+	legalActions := te.table.LegalActions(playerID)
+	if legalActions == nil {
+		return nil, ErrGamePlayerNotFound
+	}
+
+	return legalActions, nil
+}
+
+// HasAction 是否可以執行該動作
+func (la TableLegalActions) HasAction(action string) bool {
+	return funk.ContainsString(la.Actions, action)
+}
+
+// isAllinAllowed 下注結構是否允許玩家全下
+func isAllinAllowed(gs *pokerface.GameState, player *pokerface.PlayerState, limit *TableWagerLimit) bool {
+	// 全下金額沒有超過當前下注量 (跟注全下)
+	if player.InitialStackSize <= gs.Status.CurrentWager {
+		return true
+	}
+
+	if gs.Status.CurrentWager == 0 {
+		return player.InitialStackSize <= limit.MaxBet
+	}
+
+	if limit.IsRaiseCapped {
+		return false
+	}
+
+	return player.InitialStackSize <= limit.MaxRaise
+}
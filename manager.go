@@ -2,34 +2,74 @@ package pokertable
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 )
 
 var (
-	ErrManagerTableNotFound = errors.New("manager: table not found")
+	ErrManagerTableNotFound    = errors.New("manager: table not found")
+	ErrManagerTableStoreNotSet = errors.New("manager: table store not set")
 )
 
+type ManagerOpt func(*manager)
+
+// ManagerRecoverError 還原失敗的桌次與原因 (key: table id)
+type ManagerRecoverError map[string]error
+
+func (e ManagerRecoverError) Error() string {
+	tableIDs := make([]string, 0, len(e))
+	for tableID := range e {
+		tableIDs = append(tableIDs, tableID)
+	}
+	sort.Strings(tableIDs)
+
+	msgs := make([]string, 0, len(tableIDs))
+	for _, tableID := range tableIDs {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", tableID, e[tableID]))
+	}
+	return fmt.Sprintf("manager: failed to recover %d table(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+func (e ManagerRecoverError) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
 type Manager interface {
 	// Other Actions
 	Reset()
 	ReleaseTable(tableID string) error
+	Recover(options *TableEngineOptions, callbacks *TableEngineCallbacks) ([]*Table, error)
+	Subscribe(handler TableEventHandler, opts ...TableEventSubscribeOpt) TableEventSubscription
 
 	// Table Actions
 	GetTableEngine(tableID string) (TableEngine, error)
+	GetTableEngines() []TableEngine
 	CreateTable(options *TableEngineOptions, callbacks *TableEngineCallbacks, setting TableSetting) (*Table, error)
+	RestoreTable(options *TableEngineOptions, callbacks *TableEngineCallbacks, snapshot TableEngineSnapshot) (*Table, error)
+	GetTableSnapshot(tableID string) (*TableEngineSnapshot, error)
 	PauseTable(tableID string) error
 	CloseTable(tableID string) error
 	StartTableGame(tableID string) error
 	SetUpTableGame(tableID string, gameCount int, participants map[string]int) error
 	UpdateBlind(tableID string, level int, ante, dealer, sb, bb int64) error
+	UpdateAnteMode(tableID string, mode string) error
 	UpdateTablePlayers(tableID string, joinPlayers []JoinPlayer, leavePlayerIDs []string) (map[string]int, error)
 
 	// Player Table Actions
 	PlayerReserve(tableID string, joinPlayer JoinPlayer) error
 	PlayerJoin(tableID, playerID string) error
 	PlayerSettlementFinish(tableID, playerID string) error
+	PlayerVoteBombPot(tableID, playerID string) error
 	PlayerRedeemChips(tableID string, joinPlayer JoinPlayer) error
 	PlayersLeave(tableID string, playerIDs []string) error
+	PlayerSitOut(tableID, playerID string) error
+	PlayerSitIn(tableID, playerID string) error
 
 	// Player Game Actions
 	PlayerExtendActionDeadline(tableID, playerID string, duration int) (int64, error)
@@ -42,18 +82,58 @@ type Manager interface {
 	PlayerCheck(tableID, playerID string) error
 	PlayerFold(tableID, playerID string) error
 	PlayerPass(tableID, playerID string) error
+	PlayerRunItMultiple(tableID, playerID string, times int) error
+	PlayerStraddle(tableID, playerID string) error
 }
 
 type manager struct {
 	tableEngines sync.Map
+	store        TableStore
+	eventLogger  TableEventLogger
+	engineOpts   []TableEngineOpt
+	bus          TableEventBus
 }
 
-func NewManager() Manager {
-	return &manager{
+func NewManager(opts ...ManagerOpt) Manager {
+	m := &manager{
 		tableEngines: sync.Map{},
+		bus:          NewTableEventBus(),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+func WithManagerTableStore(store TableStore) ManagerOpt {
+	return func(m *manager) {
+		m.store = store
 	}
 }
 
+// WithManagerTableEngineOpts 套用到此 Manager 建立的每個桌次引擎
+func WithManagerTableEngineOpts(opts ...TableEngineOpt) ManagerOpt {
+	return func(m *manager) {
+		m.engineOpts = append(m.engineOpts, opts...)
+	}
+}
+
+func WithManagerEventLogger(logger TableEventLogger) ManagerOpt {
+	return func(m *manager) {
+		m.eventLogger = logger
+	}
+}
+
+/*
+Subscribe 訂閱此 Manager 所有桌次的事件
+  - 適用時機: 跨桌次監聽 (例如 Websocket Gateway)
+*/
+func (m *manager) Subscribe(handler TableEventHandler, opts ...TableEventSubscribeOpt) TableEventSubscription {
+	return m.bus.Subscribe(handler, opts...)
+}
+
 func (m *manager) Reset() {
 	m.tableEngines = sync.Map{}
 }
@@ -69,7 +149,57 @@ func (m *manager) ReleaseTable(tableID string) error {
 	}
 
 	m.tableEngines.Delete(tableID)
-	return nil
+	return m.deleteStoredTable(tableID)
+}
+
+/*
+Recover 從 TableStore 還原所有未關閉的桌次
+  - 適用時機: 服務重啟後
+  - 單一桌次讀取或還原失敗時略過該桌次並繼續還原其他桌次，失敗原因以 ManagerRecoverError 回傳
+*/
+func (m *manager) Recover(options *TableEngineOptions, callbacks *TableEngineCallbacks) ([]*Table, error) {
+	if m.store == nil {
+		return nil, ErrManagerTableStoreNotSet
+	}
+
+	tableIDs, err := m.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make([]*Table, 0)
+	recoverErr := make(ManagerRecoverError)
+	for _, tableID := range tableIDs {
+		if _, err := m.GetTableEngine(tableID); err == nil {
+			continue
+		}
+
+		snapshot, err := m.store.Load(tableID)
+		if err != nil {
+			recoverErr[tableID] = err
+			continue
+		}
+
+		if snapshot.Table.State.Status == TableStateStatus_TableClosed {
+			if err := m.store.Delete(tableID); err != nil {
+				recoverErr[tableID] = err
+			}
+			continue
+		}
+
+		table, err := m.RestoreTable(options, callbacks, *snapshot)
+		if err != nil {
+			recoverErr[tableID] = err
+			continue
+		}
+		tables = append(tables, table)
+	}
+
+	if len(recoverErr) > 0 {
+		return tables, recoverErr
+	}
+
+	return tables, nil
 }
 
 func (m *manager) GetTableEngine(tableID string) (TableEngine, error) {
@@ -80,6 +210,15 @@ func (m *manager) GetTableEngine(tableID string) (TableEngine, error) {
 	return tableEngine.(TableEngine), nil
 }
 
+func (m *manager) GetTableEngines() []TableEngine {
+	tableEngines := make([]TableEngine, 0)
+	m.tableEngines.Range(func(key, value interface{}) bool {
+		tableEngines = append(tableEngines, value.(TableEngine))
+		return true
+	})
+	return tableEngines
+}
+
 func (m *manager) CreateTable(options *TableEngineOptions, callbacks *TableEngineCallbacks, setting TableSetting) (*Table, error) {
 	var engineOptions *TableEngineOptions
 	if options != nil {
@@ -96,7 +235,7 @@ func (m *manager) CreateTable(options *TableEngineOptions, callbacks *TableEngin
 	}
 
 	gameBackend := NewNativeGameBackend()
-	tableEngine := NewTableEngine(engineOptions, WithGameBackend(gameBackend))
+	tableEngine := NewTableEngine(engineOptions, m.tableEngineOpts(gameBackend)...)
 	tableEngine.OnTableUpdated(engineCallbacks.OnTableUpdated)
 	tableEngine.OnTableErrorUpdated(engineCallbacks.OnTableErrorUpdated)
 	tableEngine.OnTableStateUpdated(engineCallbacks.OnTableStateUpdated)
@@ -105,6 +244,10 @@ func (m *manager) CreateTable(options *TableEngineOptions, callbacks *TableEngin
 	tableEngine.OnGamePlayerActionUpdated(engineCallbacks.OnGamePlayerActionUpdated)
 	tableEngine.OnAutoGameOpenEnd(engineCallbacks.OnAutoGameOpenEnd)
 	tableEngine.OnReadyOpenFirstTableGame(engineCallbacks.OnReadyOpenFirstTableGame)
+	tableEngine.OnTablePlayerTimeBankUsed(engineCallbacks.OnTablePlayerTimeBankUsed)
+	tableEngine.OnTablePlayerActionTimeout(engineCallbacks.OnTablePlayerActionTimeout)
+	tableEngine.OnGameSettled(engineCallbacks.OnGameSettled)
+	tableEngine.OnTablePatched(engineCallbacks.OnTablePatched)
 	table, err := tableEngine.CreateTable(setting)
 	if err != nil {
 		return nil, err
@@ -114,6 +257,41 @@ func (m *manager) CreateTable(options *TableEngineOptions, callbacks *TableEngin
 	return table, nil
 }
 
+func (m *manager) RestoreTable(options *TableEngineOptions, callbacks *TableEngineCallbacks, snapshot TableEngineSnapshot) (*Table, error) {
+	var engineOptions *TableEngineOptions
+	if options != nil {
+		engineOptions = options
+	} else {
+		engineOptions = NewTableEngineOptions()
+	}
+
+	var engineCallbacks *TableEngineCallbacks
+	if callbacks != nil {
+		engineCallbacks = callbacks
+	} else {
+		engineCallbacks = NewTableEngineCallbacks()
+	}
+
+	gameBackend := NewNativeGameBackend()
+	tableEngine, err := NewTableEngineFromSnapshot(engineOptions, engineCallbacks, snapshot, m.tableEngineOpts(gameBackend)...)
+	if err != nil {
+		return nil, err
+	}
+
+	table := tableEngine.GetTable()
+	m.tableEngines.Store(table.ID, tableEngine)
+	return table, nil
+}
+
+func (m *manager) GetTableSnapshot(tableID string) (*TableEngineSnapshot, error) {
+	tableEngine, err := m.GetTableEngine(tableID)
+	if err != nil {
+		return nil, ErrManagerTableNotFound
+	}
+
+	return tableEngine.GetSnapshot()
+}
+
 func (m *manager) PauseTable(tableID string) error {
 	tableEngine, err := m.GetTableEngine(tableID)
 	if err != nil {
@@ -134,7 +312,7 @@ func (m *manager) CloseTable(tableID string) error {
 	}
 
 	m.tableEngines.Delete(tableID)
-	return nil
+	return m.deleteStoredTable(tableID)
 }
 
 func (m *manager) StartTableGame(tableID string) error {
@@ -165,6 +343,15 @@ func (m *manager) UpdateBlind(tableID string, level int, ante, dealer, sb, bb in
 	return nil
 }
 
+func (m *manager) UpdateAnteMode(tableID string, mode string) error {
+	tableEngine, err := m.GetTableEngine(tableID)
+	if err != nil {
+		return ErrManagerTableNotFound
+	}
+
+	return tableEngine.UpdateAnteMode(mode)
+}
+
 func (m *manager) UpdateTablePlayers(tableID string, joinPlayers []JoinPlayer, leavePlayerIDs []string) (map[string]int, error) {
 	tableEngine, err := m.GetTableEngine(tableID)
 	if err != nil {
@@ -201,6 +388,15 @@ func (m *manager) PlayerSettlementFinish(tableID, playerID string) error {
 	return tableEngine.PlayerSettlementFinish(playerID)
 }
 
+func (m *manager) PlayerVoteBombPot(tableID, playerID string) error {
+	tableEngine, err := m.GetTableEngine(tableID)
+	if err != nil {
+		return ErrManagerTableNotFound
+	}
+
+	return tableEngine.PlayerVoteBombPot(playerID)
+}
+
 func (m *manager) PlayerRedeemChips(tableID string, joinPlayer JoinPlayer) error {
 	tableEngine, err := m.GetTableEngine(tableID)
 	if err != nil {
@@ -219,6 +415,24 @@ func (m *manager) PlayersLeave(tableID string, playerIDs []string) error {
 	return tableEngine.PlayersLeave(playerIDs)
 }
 
+func (m *manager) PlayerSitOut(tableID, playerID string) error {
+	tableEngine, err := m.GetTableEngine(tableID)
+	if err != nil {
+		return ErrManagerTableNotFound
+	}
+
+	return tableEngine.PlayerSitOut(playerID)
+}
+
+func (m *manager) PlayerSitIn(tableID, playerID string) error {
+	tableEngine, err := m.GetTableEngine(tableID)
+	if err != nil {
+		return ErrManagerTableNotFound
+	}
+
+	return tableEngine.PlayerSitIn(playerID)
+}
+
 func (m *manager) PlayerExtendActionDeadline(tableID, playerID string, duration int) (int64, error) {
 	tableEngine, err := m.GetTableEngine(tableID)
 	if err != nil {
@@ -308,3 +522,56 @@ func (m *manager) PlayerPass(tableID, playerID string) error {
 
 	return tableEngine.PlayerPass(playerID)
 }
+
+func (m *manager) PlayerRunItMultiple(tableID, playerID string, times int) error {
+	tableEngine, err := m.GetTableEngine(tableID)
+	if err != nil {
+		return ErrManagerTableNotFound
+	}
+
+	return tableEngine.PlayerRunItMultiple(playerID, times)
+}
+
+func (m *manager) PlayerStraddle(tableID, playerID string) error {
+	tableEngine, err := m.GetTableEngine(tableID)
+	if err != nil {
+		return ErrManagerTableNotFound
+	}
+
+	return tableEngine.PlayerStraddle(playerID)
+}
+
+func (m *manager) tableEngineOpts(gameBackend GameBackend) []TableEngineOpt {
+	opts := []TableEngineOpt{WithGameBackend(gameBackend), WithTableEventBus(m.bus)}
+	if m.store != nil {
+		opts = append(opts, WithTableStore(m.store))
+	}
+	if m.eventLogger != nil {
+		opts = append(opts, WithEventLogger(m.eventLogger))
+	}
+	return append(opts, m.engineOpts...)
+}
+
+func (m *manager) deleteStoredTable(tableID string) error {
+	if m.store == nil {
+		return nil
+	}
+	return m.store.Delete(tableID)
+}
+
+/*
+resumeTableGame 暫停中的桌次可以繼續時開下一手
+  - 適用時機: 中場休息結束、人數不足暫停的桌次補人後
+  - 尚未開始或仍需暫停的桌次不做任何事
+*/
+func resumeTableGame(m Manager, table *Table) error {
+	if table.State.Status != TableStateStatus_TablePausing || table.State.StartAt == UnsetValue || table.ShouldPause() {
+		return nil
+	}
+
+	participants := make(map[string]int)
+	for idx, player := range table.AlivePlayers() {
+		participants[player.PlayerID] = idx
+	}
+	return m.SetUpTableGame(table.ID, table.State.GameCount+1, participants)
+}
@@ -0,0 +1,402 @@
+package pokertable
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokertable/seat_manager"
+)
+
+type MTTCoordinatorOpt func(*mttCoordinator)
+
+/*
+MTTCoordinator MTT 多桌平衡與拆桌協調器
+  - 監聽 Manager 中同一賽事的所有 MTT 桌次，於每手結算並重置後或桌次暫停時調整各桌人數
+  - 拆桌: 剩餘玩家數可以坐進少一桌時，拆掉人數最少的桌次並關閉
+  - 平衡: 人數最多與最少的桌次相差超過 1 人時，從人數最多的桌次移動玩家
+  - 移動順序: 依下一個 BB 的順序 (即將輪到 BB 的玩家先移動)
+  - 移動位置: 目的桌次當前 BB 之後第一個空位 (與原桌相同的相對位置)，沒有可用資訊時隨機入座
+  - 只在桌次沒有進行中的牌局時 (結算後等待下一手或暫停中) 移出玩家
+  - 不在結算事件中調整玩家，移動玩家都經由 Manager 取得各桌 te.lock
+*/
+type MTTCoordinator interface {
+	Stop() // 停止監聽
+}
+
+type MTTPlayerMove struct {
+	PlayerID    string `json:"player_id"`     // 玩家 ID
+	FromTableID string `json:"from_table_id"` // 原桌次 ID
+	ToTableID   string `json:"to_table_id"`   // 目的桌次 ID
+	Seat        int    `json:"seat"`          // 目的桌次座位編號
+	Chips       int64  `json:"chips"`         // 帶入籌碼
+}
+
+// WithMTTPlayersMoved 平衡移動玩家後通知 (拆桌的移動由 WithMTTTableBroken 通知)
+func WithMTTPlayersMoved(fn func(moves []MTTPlayerMove)) MTTCoordinatorOpt {
+	return func(c *mttCoordinator) {
+		c.onPlayersMoved = fn
+	}
+}
+
+// WithMTTTableBroken 拆桌並移動所有玩家後通知
+func WithMTTTableBroken(fn func(tableID string, moves []MTTPlayerMove)) MTTCoordinatorOpt {
+	return func(c *mttCoordinator) {
+		c.onTableBroken = fn
+	}
+}
+
+// WithMTTCoordinatorErrorUpdated 移動玩家或拆桌失敗時通知
+func WithMTTCoordinatorErrorUpdated(fn func(tableID string, err error)) MTTCoordinatorOpt {
+	return func(c *mttCoordinator) {
+		c.onErrorUpdated = fn
+	}
+}
+
+type mttCoordinator struct {
+	mu             sync.Mutex
+	manager        Manager
+	competitionID  string
+	subscription   TableEventSubscription
+	onPlayersMoved func(moves []MTTPlayerMove)
+	onTableBroken  func(tableID string, moves []MTTPlayerMove)
+	onErrorUpdated func(tableID string, err error)
+}
+
+func NewMTTCoordinator(manager Manager, competitionID string, opts ...MTTCoordinatorOpt) MTTCoordinator {
+	c := &mttCoordinator{
+		manager:        manager,
+		competitionID:  competitionID,
+		onPlayersMoved: func(moves []MTTPlayerMove) {},
+		onTableBroken:  func(tableID string, moves []MTTPlayerMove) {},
+		onErrorUpdated: func(tableID string, err error) {},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.subscription = manager.Subscribe(c.handleTableStateEvent, WithTableEventTypes(TableEventType_TableStateUpdated))
+	return c
+}
+
+func (c *mttCoordinator) Stop() {
+	c.subscription.Unsubscribe()
+}
+
+/*
+handleTableStateEvent 桌次狀態更新
+  - 本手結算並重置 (TableGameStandby): 該桌次已完成結算流程，可以移出玩家
+  - 桌次暫停 (TablePausing): 例如人數不足暫停的桌次，可以被拆桌或補人
+  - 其他狀態事件 (包含協調器自己造成的關桌) 不處理
+*/
+func (c *mttCoordinator) handleTableStateEvent(event TableEvent) {
+	if event.CompetitionID != c.competitionID || event.Table == nil || event.Table.Meta.Mode != CompetitionMode_MTT {
+		return
+	}
+
+	if event.StateEvent != TableStateEvent_StatusUpdated {
+		return
+	}
+
+	switch event.Table.State.Status {
+	case TableStateStatus_TableGameStandby, TableStateStatus_TablePausing:
+	default:
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.balance(event.TableID)
+}
+
+func (c *mttCoordinator) balance(triggerTableID string) {
+	tables := c.tables()
+	if len(tables) < 2 {
+		return
+	}
+
+	canMoveOut := func(table *Table) bool {
+		if table.State.Status == TableStateStatus_TablePausing {
+			return true
+		}
+		return table.ID == triggerTableID && table.State.Status == TableStateStatus_TableGameStandby
+	}
+
+	// 拆桌: 剩餘玩家可以坐進少一桌
+	totalPlayerCount := 0
+	for _, table := range tables {
+		totalPlayerCount += len(table.AlivePlayers())
+	}
+	maxSeatCount := tables[0].Meta.TableMaxSeatCount
+	if totalPlayerCount <= (len(tables)-1)*maxSeatCount {
+		// 人數相同時優先拆目前可以移出玩家的桌次
+		sort.SliceStable(tables, func(i, j int) bool {
+			iCount, jCount := len(tables[i].AlivePlayers()), len(tables[j].AlivePlayers())
+			if iCount != jCount {
+				return iCount < jCount
+			}
+			return canMoveOut(tables[i]) && !canMoveOut(tables[j])
+		})
+
+		// 人數最少的桌次正在進行牌局，等該桌結算後再拆
+		if canMoveOut(tables[0]) {
+			c.breakTable(tables[0])
+		}
+		return
+	}
+
+	// 平衡: 從人數最多的桌次移動玩家到人數最少的桌次
+	moves := make([]MTTPlayerMove, 0)
+	for _, table := range tables {
+		if !canMoveOut(table) {
+			continue
+		}
+
+		for _, playerID := range mttBBOrderPlayerIDs(table) {
+			// 開局時桌次會被替換，每次移動前重新取得
+			source, others := c.splitTables(table.ID)
+			if source == nil {
+				break
+			}
+
+			sourceCount := len(source.AlivePlayers())
+			if sourceCount < mttMaxAlivePlayerCount(others) {
+				break
+			}
+
+			destination := mttFewestPlayersTable(others)
+			if destination == nil || sourceCount-len(destination.AlivePlayers()) <= 1 {
+				break
+			}
+
+			move, err := c.movePlayer(source, destination, playerID)
+			if err != nil {
+				c.onErrorUpdated(source.ID, err)
+				break
+			}
+			moves = append(moves, *move)
+		}
+	}
+
+	if len(moves) > 0 {
+		c.onPlayersMoved(moves)
+	}
+}
+
+/*
+breakTable 拆桌
+  - 依下一個 BB 的順序，逐一把玩家移到當下人數最少的桌次
+  - 所有玩家移出後關閉桌次
+*/
+func (c *mttCoordinator) breakTable(source *Table) {
+	moves := make([]MTTPlayerMove, 0)
+	for _, playerID := range mttBBOrderPlayerIDs(source) {
+		_, destinations := c.splitTables(source.ID)
+		destination := mttFewestPlayersTable(destinations)
+		if destination == nil {
+			c.onErrorUpdated(source.ID, ErrTableNoEmptySeats)
+			return
+		}
+
+		move, err := c.movePlayer(source, destination, playerID)
+		if err != nil {
+			c.onErrorUpdated(source.ID, err)
+			return
+		}
+		moves = append(moves, *move)
+	}
+
+	if err := c.manager.CloseTable(source.ID); err != nil {
+		c.onErrorUpdated(source.ID, err)
+		return
+	}
+
+	c.onTableBroken(source.ID, moves)
+}
+
+/*
+movePlayer 移動玩家到目的桌次
+  - 先在目的桌次入座，再從原桌次移除，任一步驟失敗時撤銷目的桌次的入座 (玩家與籌碼留在原桌次)
+  - 目的桌次因人數不足暫停時，補人後開下一手
+*/
+func (c *mttCoordinator) movePlayer(source, destination *Table, playerID string) (*MTTPlayerMove, error) {
+	playerIdx := source.FindPlayerIdx(playerID)
+	if playerIdx == UnsetValue {
+		return nil, ErrTablePlayerNotFound
+	}
+
+	move := &MTTPlayerMove{
+		PlayerID:    playerID,
+		FromTableID: source.ID,
+		ToTableID:   destination.ID,
+		Seat:        mttDestinationSeat(destination),
+		Chips:       source.State.PlayerStates[playerIdx].Bankroll,
+	}
+
+	isShortHanded := len(destination.AlivePlayers()) < destination.Meta.TableMinPlayerCount
+
+	joinPlayer := JoinPlayer{
+		PlayerID:    playerID,
+		RedeemChips: move.Chips,
+		Seat:        move.Seat,
+	}
+	playerSeatMap, err := c.manager.UpdateTablePlayers(destination.ID, []JoinPlayer{joinPlayer}, nil)
+	if err != nil {
+		return nil, err
+	}
+	move.Seat = playerSeatMap[playerID]
+
+	if err := c.manager.PlayerJoin(destination.ID, playerID); err != nil {
+		c.rollbackMove(move)
+		return nil, err
+	}
+
+	if _, err := c.manager.UpdateTablePlayers(source.ID, nil, []string{playerID}); err != nil {
+		c.rollbackMove(move)
+		return nil, err
+	}
+
+	// 依入座後的目的桌次判斷是否可以開下一手
+	if isShortHanded {
+		snapshot, err := c.manager.GetTableSnapshot(destination.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := resumeTableGame(c.manager, snapshot.Table); err != nil {
+			return nil, err
+		}
+	}
+
+	return move, nil
+}
+
+// rollbackMove 撤銷玩家在目的桌次的入座
+func (c *mttCoordinator) rollbackMove(move *MTTPlayerMove) {
+	if _, err := c.manager.UpdateTablePlayers(move.ToTableID, nil, []string{move.PlayerID}); err != nil {
+		c.onErrorUpdated(move.ToTableID, err)
+	}
+}
+
+/*
+tables 此賽事所有未關閉的 MTT 桌次 (依桌次 ID 排序)
+  - 回傳桌次快照 (GetSnapshot 會取得各桌 te.lock)，其他桌次可能同時在進行牌局，不直接讀取桌次引擎中的桌次
+*/
+func (c *mttCoordinator) tables() []*Table {
+	tables := make([]*Table, 0)
+	for _, tableEngine := range c.manager.GetTableEngines() {
+		snapshot, err := tableEngine.GetSnapshot()
+		if err != nil {
+			continue
+		}
+
+		table := snapshot.Table
+		if table.Meta.CompetitionID != c.competitionID || table.Meta.Mode != CompetitionMode_MTT {
+			continue
+		}
+
+		if table.State.Status == TableStateStatus_TableClosed {
+			continue
+		}
+
+		tables = append(tables, table)
+	}
+
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].ID < tables[j].ID
+	})
+	return tables
+}
+
+// splitTables 取得指定桌次與此賽事的其他桌次
+func (c *mttCoordinator) splitTables(tableID string) (*Table, []*Table) {
+	var target *Table
+	others := make([]*Table, 0)
+	for _, table := range c.tables() {
+		if table.ID == tableID {
+			target = table
+		} else {
+			others = append(others, table)
+		}
+	}
+	return target, others
+}
+
+// mttBBOrderPlayerIDs 有籌碼的玩家依下一個 BB 的順序排列
+func mttBBOrderPlayerIDs(table *Table) []string {
+	playerIDs := make([]string, 0)
+	for _, playerID := range table.State.NextBBOrderPlayerIDs {
+		playerIdx := table.FindPlayerIdx(playerID)
+		if playerIdx != UnsetValue && table.State.PlayerStates[playerIdx].Bankroll > 0 {
+			playerIDs = append(playerIDs, playerID)
+		}
+	}
+
+	// 尚未開局或已重置 NextBBOrderPlayerIDs 時，依座位從當前 BB 之後排列
+	maxSeatCount := table.Meta.TableMaxSeatCount
+	startSeat := table.State.CurrentBBSeat
+	if startSeat == UnsetValue {
+		startSeat = maxSeatCount - 1
+	}
+	for i := startSeat + 1; i <= startSeat+maxSeatCount; i++ {
+		playerIdx := table.State.SeatMap[i%maxSeatCount]
+		if playerIdx == seat_manager.UnsetSeatID {
+			continue
+		}
+
+		player := table.State.PlayerStates[playerIdx]
+		if player.Bankroll > 0 && !funk.ContainsString(playerIDs, player.PlayerID) {
+			playerIDs = append(playerIDs, player.PlayerID)
+		}
+	}
+
+	return playerIDs
+}
+
+/*
+mttDestinationSeat 目的桌次的座位
+  - 當前 BB 之後的第一個空位，下一手即輪到該座位的玩家當 BB
+  - 尚未開局時回傳 UnsetValue (隨機入座)
+*/
+func mttDestinationSeat(table *Table) int {
+	if table.State.CurrentBBSeat == UnsetValue {
+		return seat_manager.UnsetSeatID
+	}
+
+	maxSeatCount := table.Meta.TableMaxSeatCount
+	for i := table.State.CurrentBBSeat + 1; i <= table.State.CurrentBBSeat+maxSeatCount; i++ {
+		seat := i % maxSeatCount
+		if table.State.SeatMap[seat] == seat_manager.UnsetSeatID {
+			return seat
+		}
+	}
+
+	return seat_manager.UnsetSeatID
+}
+
+// mttFewestPlayersTable 還有空位且有籌碼玩家最少的桌次
+func mttFewestPlayersTable(tables []*Table) *Table {
+	var fewest *Table
+	for _, table := range tables {
+		if len(table.State.PlayerStates) >= table.Meta.TableMaxSeatCount {
+			continue
+		}
+
+		if fewest == nil || len(table.AlivePlayers()) < len(fewest.AlivePlayers()) {
+			fewest = table
+		}
+	}
+	return fewest
+}
+
+func mttMaxAlivePlayerCount(tables []*Table) int {
+	maxCount := 0
+	for _, table := range tables {
+		if count := len(table.AlivePlayers()); count > maxCount {
+			maxCount = count
+		}
+	}
+	return maxCount
+}
@@ -72,6 +72,53 @@ func (ngb *NativeGameBackend) PayBlinds(gs *pokerface.GameState) (*pokerface.Gam
 	return ngb.getState(g), nil
 }
 
+/*
+PayBlindPosts 補盲注與代付全桌前注 (key: game player index)
+  - 適用時機: 支付盲注前 (BlindsRequested)
+  - 死盲與代付前注從玩家籌碼扣除並計入本輪底池，不計入玩家本輪下注與底池層級 (結算時由桌次引擎併入主池)
+  - 活盲計入本輪下注，並先拉高本輪下注，避免補盲玩家在支付盲注時成為加注者 (大盲仍保有行動權)
+  - 籌碼不足時優先支付活盲，只支付剩餘籌碼
+*/
+func (ngb *NativeGameBackend) PayBlindPosts(gs *pokerface.GameState, posts map[int]GameBlindPost) (*pokerface.GameState, error) {
+	g := ngb.engine.NewGameFromState(cloneGameState(gs))
+	if g.GetEvent() != pokerface.GameEventSymbols[pokerface.GameEvent_BlindsRequested] {
+		return nil, pokerface.ErrInvalidAction
+	}
+
+	state := g.GetState()
+	for gamePlayerIdx, post := range posts {
+		p := g.Player(gamePlayerIdx)
+		if p == nil {
+			continue
+		}
+
+		ps := p.State()
+		live := post.Live
+		if live > ps.StackSize {
+			live = ps.StackSize
+		}
+		dead := post.Dead + post.Ante
+		if dead > ps.StackSize-live {
+			dead = ps.StackSize - live
+		}
+
+		ps.Bankroll -= dead
+		ps.InitialStackSize -= dead
+		ps.Wager += live
+		ps.StackSize = ps.InitialStackSize - ps.Wager
+		if ps.StackSize == 0 {
+			ps.DidAction = "allin"
+		}
+
+		state.Status.CurrentRoundPot += live + dead
+		if state.Status.CurrentWager < ps.Wager {
+			state.Status.CurrentWager = ps.Wager
+		}
+	}
+
+	return ngb.getState(g), nil
+}
+
 func (ngb *NativeGameBackend) Next(gs *pokerface.GameState) (*pokerface.GameState, error) {
 	g := ngb.engine.NewGameFromState(cloneGameState(gs))
 	err := g.Next()
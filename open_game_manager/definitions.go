@@ -2,6 +2,7 @@ package open_game_manager
 
 import (
 	"errors"
+	"time"
 
 	"github.com/weedbox/syncsaga"
 )
@@ -13,6 +14,7 @@ var (
 type OpenGameManager interface {
 	Ready(participantID string) error
 	Setup(gameCount int, participants map[string]int)
+	VoteBombPot(participantID string) error
 	GetState() OpenGameState
 	PrintState()
 }
@@ -20,11 +22,19 @@ type OpenGameManager interface {
 type openGameManager struct {
 	onOpenGameReady func(state OpenGameState)
 	rg              *syncsaga.ReadyGroup
+	timer           Timer
 	state           *OpenGameState
 }
 
+// Timer 等待開局逾時的計時器 (timebank.TimeBank 實作此介面)
+type Timer interface {
+	NewTask(duration time.Duration, fn func(isCancelled bool)) error
+	Cancel()
+}
+
 type OpenGameOption struct {
 	Timeout         int
+	Timer           Timer // 未指定時使用 timebank.TimeBank
 	OnOpenGameReady func(state OpenGameState)
 }
 
@@ -35,7 +45,8 @@ type OpenGameState struct {
 }
 
 type OpenGameParticipant struct {
-	ID      string `json:"id"`
-	Index   int    `json:"index"`
-	IsReady bool   `json:"is_ready"`
+	ID             string `json:"id"`
+	Index          int    `json:"index"`
+	IsReady        bool   `json:"is_ready"`
+	IsBombPotVoted bool   `json:"is_bomb_pot_voted"` // 是否投票同意下一手進行 Bomb Pot
 }
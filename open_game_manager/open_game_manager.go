@@ -5,19 +5,14 @@ import (
 	"fmt"
 
 	"github.com/weedbox/syncsaga"
+	"github.com/weedbox/timebank"
 )
 
 func NewOpenGameManager(options OpenGameOption) OpenGameManager {
 	m := &openGameManager{
 		onOpenGameReady: options.OnOpenGameReady,
-		rg: syncsaga.NewReadyGroup(syncsaga.WithTimeout(options.Timeout, func(rg *syncsaga.ReadyGroup) {
-			// Auto Ready By Default
-			for idx, isReady := range rg.GetParticipantStates() {
-				if !isReady {
-					rg.Ready(idx)
-				}
-			}
-		})),
+		rg:              syncsaga.NewReadyGroup(),
+		timer:           newTimer(options),
 	}
 	m.state = &OpenGameState{
 		Timeout:      options.Timeout,
@@ -31,14 +26,8 @@ func NewOpenGameManager(options OpenGameOption) OpenGameManager {
 func NewOpenGameManagerFromState(state OpenGameState, options OpenGameOption) OpenGameManager {
 	m := &openGameManager{
 		onOpenGameReady: options.OnOpenGameReady,
-		rg: syncsaga.NewReadyGroup(syncsaga.WithTimeout(options.Timeout, func(rg *syncsaga.ReadyGroup) {
-			// Auto Ready By Default
-			for idx, isReady := range rg.GetParticipantStates() {
-				if !isReady {
-					rg.Ready(idx)
-				}
-			}
-		})),
+		rg:              syncsaga.NewReadyGroup(),
+		timer:           newTimer(options),
 		state: &OpenGameState{
 			Timeout:      options.Timeout,
 			GameCount:    state.GameCount,
@@ -55,13 +44,14 @@ func NewOpenGameManagerFromState(state OpenGameState, options OpenGameOption) Op
 		m.readyGroupAddParticipant(*participant, false)
 		if participant.IsReady {
 			readyParticipants[participant.Index] = OpenGameParticipant{
-				ID:      participant.ID,
-				Index:   participant.Index,
-				IsReady: true,
+				ID:             participant.ID,
+				Index:          participant.Index,
+				IsReady:        true,
+				IsBombPotVoted: participant.IsBombPotVoted,
 			}
 		}
 	}
-	m.rg.Start()
+	m.readyGroupStart()
 
 	for _, readyParticipant := range readyParticipants {
 		m.readyGroupAddParticipant(readyParticipant, true)
@@ -70,10 +60,22 @@ func NewOpenGameManagerFromState(state OpenGameState, options OpenGameOption) Op
 	return m
 }
 
+func newTimer(options OpenGameOption) Timer {
+	if options.Timer != nil {
+		return options.Timer
+	}
+	return timebank.NewTimeBank()
+}
+
 func (m *openGameManager) Ready(participantID string) error {
 	return m.readyGroupReady(participantID)
 }
 
+// VoteBombPot 參與者投票同意下一手進行 Bomb Pot (重新 Setup 後清除)
+func (m *openGameManager) VoteBombPot(participantID string) error {
+	return m.bombPotVote(participantID)
+}
+
 func (m *openGameManager) Setup(gameCount int, participants map[string]int) {
 	m.state.GameCount = gameCount
 
@@ -91,7 +93,7 @@ func (m *openGameManager) Setup(gameCount int, participants map[string]int) {
 		m.readyGroupAddParticipant(participant, false)
 	}
 
-	m.rg.Start()
+	m.readyGroupStart()
 }
 
 func (m *openGameManager) GetState() OpenGameState {
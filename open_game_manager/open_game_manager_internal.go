@@ -1,5 +1,7 @@
 package open_game_manager
 
+import "time"
+
 func (m *openGameManager) readyGroupResetParticipants() {
 	m.rg.ResetParticipants()
 	m.state.Participants = map[string]*OpenGameParticipant{}
@@ -7,14 +9,40 @@ func (m *openGameManager) readyGroupResetParticipants() {
 
 func (m *openGameManager) readyGroupAddParticipant(participant OpenGameParticipant, isReady bool) {
 	m.state.Participants[participant.ID] = &OpenGameParticipant{
-		ID:      participant.ID,
-		Index:   participant.Index,
-		IsReady: isReady,
+		ID:             participant.ID,
+		Index:          participant.Index,
+		IsReady:        isReady,
+		IsBombPotVoted: participant.IsBombPotVoted,
 	}
 	m.rg.Add(int64(participant.Index), isReady)
 }
 
+// readyGroupStart 開始等待參與者準備，逾時後所有參與者自動準備
+func (m *openGameManager) readyGroupStart() {
+	m.rg.Start()
+
+	// No time limit
+	if m.state.Timeout <= 0 {
+		return
+	}
+
+	m.timer.NewTask(time.Duration(m.state.Timeout)*time.Second, func(isCancelled bool) {
+		if isCancelled {
+			return
+		}
+
+		// Auto Ready By Default
+		for idx, isReady := range m.rg.GetParticipantStates() {
+			if !isReady {
+				m.rg.Ready(idx)
+			}
+		}
+	})
+}
+
 func (m *openGameManager) readyGroupOnCompleted() {
+	m.timer.Cancel()
+
 	for participantID := range m.state.Participants {
 		m.state.Participants[participantID].IsReady = true
 	}
@@ -31,3 +59,13 @@ func (m *openGameManager) readyGroupReady(participantID string) error {
 	participant.IsReady = true
 	return nil
 }
+
+func (m *openGameManager) bombPotVote(participantID string) error {
+	participant, exist := m.state.Participants[participantID]
+	if !exist {
+		return ErrParticipantNotFound
+	}
+
+	participant.IsBombPotVoted = true
+	return nil
+}
@@ -303,3 +303,59 @@ func TestOpenGameManager_SetupPartialReady(t *testing.T) {
 
 	fmt.Println("[TestOpenGameManager_SetupPartialReady] End: ", time.Now().Format(time.RFC3339))
 }
+
+func TestOpenGameManager_VoteBombPot(t *testing.T) {
+	fmt.Println("[TestOpenGameManager_VoteBombPot] Start: ", time.Now().Format(time.RFC3339))
+
+	var votedState OpenGameState
+	done := make(chan struct{})
+	options := OpenGameOption{
+		Timeout: 3,
+		OnOpenGameReady: func(state OpenGameState) {
+			fmt.Println("[TestOpenGameManager_VoteBombPot] OpenGameReady for game count: ", state.GameCount)
+			votedState = state
+			close(done)
+		},
+	}
+	participants := map[string]int{
+		"player1": 1,
+		"player2": 2,
+		"player3": 3,
+	}
+
+	m := NewOpenGameManager(options)
+	m.Setup(1, participants)
+
+	// 未參與的玩家無法投票
+	assert.ErrorIs(t, m.VoteBombPot("player4"), ErrParticipantNotFound)
+
+	// 投票不影響就緒狀態
+	assert.NoError(t, m.VoteBombPot("player1"))
+	assert.NoError(t, m.VoteBombPot("player2"))
+	assert.True(t, m.GetState().Participants["player1"].IsBombPotVoted)
+	assert.False(t, m.GetState().Participants["player1"].IsReady)
+	assert.False(t, m.GetState().Participants["player3"].IsBombPotVoted)
+
+	for playerID := range participants {
+		assert.NoError(t, m.Ready(playerID))
+	}
+	<-done
+
+	// 開局時保留投票結果
+	assert.True(t, votedState.Participants["player1"].IsBombPotVoted)
+	assert.True(t, votedState.Participants["player2"].IsBombPotVoted)
+	assert.False(t, votedState.Participants["player3"].IsBombPotVoted)
+
+	// 從狀態還原時保留投票結果
+	restored := NewOpenGameManagerFromState(m.GetState(), OpenGameOption{Timeout: 3, OnOpenGameReady: func(state OpenGameState) {}})
+	assert.True(t, restored.GetState().Participants["player1"].IsBombPotVoted)
+	assert.False(t, restored.GetState().Participants["player3"].IsBombPotVoted)
+
+	// 重新 Setup 後清除投票
+	m.Setup(2, participants)
+	for _, participant := range m.GetState().Participants {
+		assert.False(t, participant.IsBombPotVoted)
+	}
+
+	fmt.Println("[TestOpenGameManager_VoteBombPot] End: ", time.Now().Format(time.RFC3339))
+}
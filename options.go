@@ -1,32 +1,40 @@
 package pokertable
 
 type TableEngineCallbacks struct {
-	OnTableUpdated            func(table *Table)
-	OnTableErrorUpdated       func(table *Table, err error)
-	OnTableStateUpdated       func(event string, table *Table)
-	OnTablePlayerStateUpdated func(competitionID, tableID string, playerState *TablePlayerState)
-	OnTablePlayerReserved     func(competitionID, tableID string, playerState *TablePlayerState)
-	OnGamePlayerActionUpdated func(gameAction TablePlayerGameAction)
-	OnAutoGameOpenEnd         func(competitionID, tableID string)
-	OnReadyOpenFirstTableGame func(competitionID, tableID string, gameCount int, playerStates []*TablePlayerState)
+	OnTableUpdated             func(table *Table)
+	OnTableErrorUpdated        func(table *Table, err error)
+	OnTableStateUpdated        func(event string, table *Table)
+	OnTablePlayerStateUpdated  func(competitionID, tableID string, playerState *TablePlayerState)
+	OnTablePlayerReserved      func(competitionID, tableID string, playerState *TablePlayerState)
+	OnGamePlayerActionUpdated  func(gameAction TablePlayerGameAction)
+	OnAutoGameOpenEnd          func(competitionID, tableID string)
+	OnReadyOpenFirstTableGame  func(competitionID, tableID string, gameCount int, playerStates []*TablePlayerState)
+	OnTablePlayerTimeBankUsed  func(competitionID, tableID string, playerState *TablePlayerState, duration int)
+	OnTablePlayerActionTimeout func(competitionID, tableID string, playerState *TablePlayerState)
+	OnGameSettled              func(result TableGameResult)
+	OnTablePatched             func(patch TablePatch)
 }
 
 func NewTableEngineCallbacks() *TableEngineCallbacks {
 	return &TableEngineCallbacks{
-		OnTableUpdated:            func(table *Table) {},
-		OnTableErrorUpdated:       func(table *Table, err error) {},
-		OnTableStateUpdated:       func(event string, table *Table) {},
-		OnTablePlayerStateUpdated: func(competitionID, tableID string, playerState *TablePlayerState) {},
-		OnTablePlayerReserved:     func(competitionID, tableID string, playerState *TablePlayerState) {},
-		OnGamePlayerActionUpdated: func(gameAction TablePlayerGameAction) {},
-		OnAutoGameOpenEnd:         func(competitionID, tableID string) {},
-		OnReadyOpenFirstTableGame: func(competitionID, tableID string, gameCount int, playerStates []*TablePlayerState) {},
+		OnTableUpdated:             func(table *Table) {},
+		OnTableErrorUpdated:        func(table *Table, err error) {},
+		OnTableStateUpdated:        func(event string, table *Table) {},
+		OnTablePlayerStateUpdated:  func(competitionID, tableID string, playerState *TablePlayerState) {},
+		OnTablePlayerReserved:      func(competitionID, tableID string, playerState *TablePlayerState) {},
+		OnGamePlayerActionUpdated:  func(gameAction TablePlayerGameAction) {},
+		OnAutoGameOpenEnd:          func(competitionID, tableID string) {},
+		OnReadyOpenFirstTableGame:  func(competitionID, tableID string, gameCount int, playerStates []*TablePlayerState) {},
+		OnTablePlayerTimeBankUsed:  func(competitionID, tableID string, playerState *TablePlayerState, duration int) {},
+		OnTablePlayerActionTimeout: func(competitionID, tableID string, playerState *TablePlayerState) {},
+		OnGameSettled:              func(result TableGameResult) {},
+		OnTablePatched:             func(patch TablePatch) {},
 	}
 }
 
 type TableEngineOptions struct {
-	GameContinueInterval int
-	OpenGameTimeout      int
+	GameContinueInterval int `json:"game_continue_interval"`
+	OpenGameTimeout      int `json:"open_game_timeout"`
 }
 
 func NewTableEngineOptions() *TableEngineOptions {
@@ -0,0 +1,129 @@
+package pokertable
+
+/*
+rakeCap 依本手玩家人數取得抽水上限
+  - 取玩家人數門檻最高且符合的設定
+  - 0 表示無上限
+*/
+func (te *tableEngine) rakeCap(playerCount int) int64 {
+	maxRake := int64(0)
+	minPlayerCount := 0
+	for _, rakeCap := range te.table.Meta.Rake.Caps {
+		if playerCount >= rakeCap.MinPlayerCount && rakeCap.MinPlayerCount >= minPlayerCount {
+			minPlayerCount = rakeCap.MinPlayerCount
+			maxRake = rakeCap.Cap
+		}
+	}
+	return maxRake
+}
+
+/*
+settleRake 計算本手抽水並從各池贏家扣除
+  - 只有現金桌需要抽水
+  - 依池的順序 (主池優先) 抽水，總量不超過本手抽水上限
+  - 未被跟注的下注不抽水
+  - 未發翻牌且設定 NoFlopNoDrop 時不抽水
+  - 各池抽水由該池贏家平分，餘數由第一位贏家負擔
+*/
+func (te *tableEngine) settleRake() {
+	if te.table.Meta.Mode != CompetitionMode_Cash || te.table.Meta.Rake.Percent <= 0 {
+		return
+	}
+
+	gs := te.table.State.GameState
+	result := gs.Result
+	if result == nil {
+		return
+	}
+
+	playerCount := len(te.table.State.GamePlayerIndexes)
+	gameRake := &TableGameRake{
+		GameID:      gs.GameID,
+		GameCount:   te.table.State.GameCount,
+		PlayerCount: playerCount,
+		Cap:         te.rakeCap(playerCount),
+		Total:       0,
+		Pots:        make([]int64, len(result.Pots)),
+		Players:     make(map[string]int64),
+	}
+	te.table.State.GameRake = gameRake
+
+	if te.table.Meta.Rake.NoFlopNoDrop && len(gs.Status.Board) == 0 {
+		return
+	}
+
+	// 未被跟注的下注會退回給下注玩家，不計入抽水
+	rakeablePots := make([]int64, len(result.Pots))
+	for potIdx, pot := range result.Pots {
+		rakeablePots[potIdx] = pot.Total
+	}
+	uncalled := te.uncalledBet()
+	for potIdx := len(rakeablePots) - 1; potIdx >= 0 && uncalled > 0; potIdx-- {
+		returned := uncalled
+		if returned > rakeablePots[potIdx] {
+			returned = rakeablePots[potIdx]
+		}
+		rakeablePots[potIdx] -= returned
+		uncalled -= returned
+	}
+
+	for potIdx, pot := range result.Pots {
+		if len(pot.Winners) == 0 {
+			continue
+		}
+
+		potRake := int64(float64(rakeablePots[potIdx]) * te.table.Meta.Rake.Percent / 100)
+		if gameRake.Cap > 0 && gameRake.Total+potRake > gameRake.Cap {
+			potRake = gameRake.Cap - gameRake.Total
+		}
+		if potRake <= 0 {
+			continue
+		}
+
+		pot.Total -= potRake
+		gameRake.Pots[potIdx] = potRake
+		gameRake.Total += potRake
+
+		based := potRake / int64(len(pot.Winners))
+		remainder := potRake % int64(len(pot.Winners))
+		for i, winner := range pot.Winners {
+			rake := based
+			if int64(i) < remainder {
+				rake += 1
+			}
+
+			winner.Withdraw -= rake
+			for _, player := range result.Players {
+				if player.Idx == winner.Idx {
+					player.Final -= rake
+					player.Changed -= rake
+				}
+			}
+
+			playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(winner.Idx)
+			if playerIdx == UnsetValue {
+				continue
+			}
+			gameRake.Players[te.table.State.PlayerStates[playerIdx].PlayerID] += rake
+		}
+	}
+}
+
+/*
+uncalledBet 計算本手未被跟注而退回的籌碼量
+  - 投入最多的玩家與第二多的玩家之差額
+*/
+func (te *tableEngine) uncalledBet() int64 {
+	topWager := int64(0)
+	secondWager := int64(0)
+	for _, player := range te.table.State.GameState.Players {
+		wager := player.Pot + player.Wager
+		if wager > topWager {
+			secondWager = topWager
+			topWager = wager
+		} else if wager > secondWager {
+			secondWager = wager
+		}
+	}
+	return topWager - secondWager
+}
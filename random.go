@@ -0,0 +1,96 @@
+package pokertable
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/weedbox/pokertable/seat_manager"
+)
+
+// DeckProvider 提供本手遊戲使用的牌組 (依發牌順序排列)，回傳空牌組則使用預設洗牌
+type DeckProvider func(table *Table) []string
+
+// lockedSource 讓多個 goroutine 可以共用同一個亂數來源
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+func newLockedRand(src rand.Source) *rand.Rand {
+	return rand.New(&lockedSource{src: src})
+}
+
+/*
+newRandomSeed 有記錄事件時，以新的種子重新建立桌次亂數來源並回傳該種子
+  - 有設定亂數來源: 由該亂數來源產生種子
+  - 未記錄事件: 不變更亂數來源，回傳 0
+*/
+func (te *tableEngine) newRandomSeed() int64 {
+	if te.recorder == nil {
+		return 0
+	}
+
+	seed := time.Now().UnixNano()
+	if te.rand != nil {
+		seed = te.rand.Int63()
+	}
+	te.rand = newLockedRand(rand.NewSource(seed))
+	return seed
+}
+
+func (te *tableEngine) seatManagerOpts() []seat_manager.SeatManagerOpt {
+	if te.rand == nil {
+		return nil
+	}
+
+	// 座位管理使用由桌次亂數衍生的獨立亂數來源
+	return []seat_manager.SeatManagerOpt{
+		seat_manager.WithRandom(rand.New(rand.NewSource(te.rand.Int63()))),
+	}
+}
+
+// newEquityRand 本手勝率計算使用由桌次亂數衍生的獨立亂數來源 (開局時產生，牌局進行中不再取用桌次亂數)
+func (te *tableEngine) newEquityRand() *rand.Rand {
+	if te.rand == nil {
+		return nil
+	}
+
+	return rand.New(rand.NewSource(te.rand.Int63()))
+}
+
+/*
+newDeck 決定本手遊戲使用的牌組
+  - 有設定 DeckProvider: 使用指定牌組
+  - 有設定亂數來源: 以該亂數來源洗牌
+  - 皆未設定: 回傳 nil，由 pokerface 自行洗牌
+*/
+func (te *tableEngine) newDeck(cards []string) []string {
+	if te.deckProvider != nil {
+		if deck := te.deckProvider(te.table); len(deck) > 0 {
+			return append([]string{}, deck...)
+		}
+	}
+
+	if te.rand == nil {
+		return nil
+	}
+
+	deck := append([]string{}, cards...)
+	te.rand.Shuffle(len(deck), func(i, j int) {
+		deck[i], deck[j] = deck[j], deck[i]
+	})
+	return deck
+}
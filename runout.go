@@ -0,0 +1,201 @@
+package pokertable
+
+import (
+	"sort"
+
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokerface/combination"
+	"github.com/weedbox/pokerface/settlement"
+)
+
+const (
+	// 全下後最多可發牌次數 (發三次)
+	MaxRunItTimes = 3
+)
+
+/*
+newGameRunout 建立本手多次發牌紀錄
+  - 適用時機: 所有玩家全下後，詢問玩家是否多次發牌時
+*/
+func (te *tableEngine) newGameRunout(gs *pokerface.GameState) *TableGameRunout {
+	runout := &TableGameRunout{
+		BoardSize:   len(gs.Status.Board),
+		PlayerIDs:   make([]string, 0),
+		AgreedTimes: make(map[string]int),
+		Times:       1,
+		Boards:      make([][]string, 0),
+	}
+
+	for _, p := range gs.Players {
+		if p.Fold {
+			continue
+		}
+
+		if playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(p.Idx); playerIdx != UnsetValue {
+			runout.PlayerIDs = append(runout.PlayerIDs, te.table.State.PlayerStates[playerIdx].PlayerID)
+		}
+	}
+
+	return runout
+}
+
+/*
+settleRunouts 多次發牌並依各次公牌重新分配各池
+  - 所有全下玩家都同意才會多次發牌，發牌次數取玩家同意的最小值
+  - 第一次發牌沿用本手公牌，其餘從剩下的牌組繼續發 (每條街先燒一張牌)
+  - 各池平分給每次發牌 (餘數給第一次)，再由該次公牌的贏家平分
+*/
+func (te *tableEngine) settleRunouts() {
+	runout := te.table.State.GameRunout
+	gs := te.table.State.GameState
+	if runout == nil || gs.Result == nil {
+		return
+	}
+
+	runout.Times = 1
+	runout.Boards = [][]string{gs.Status.Board}
+
+	times := te.table.Meta.MaxRunItTimes
+	for _, playerID := range runout.PlayerIDs {
+		agreedTimes, ok := runout.AgreedTimes[playerID]
+		if !ok {
+			return
+		}
+		if agreedTimes < times {
+			times = agreedTimes
+		}
+	}
+
+	// 其餘公牌
+	deckPosition := gs.Status.CurrentDeckPosition
+	for len(runout.Boards) < times {
+		board := append([]string{}, gs.Status.Board[:runout.BoardSize]...)
+		for len(board) < 5 {
+			count := 1
+			if len(board) == 0 {
+				count = 3
+			}
+
+			// 剩下的牌不夠發
+			if deckPosition+1+count > len(gs.Meta.Deck) {
+				break
+			}
+
+			deckPosition++
+			board = append(board, gs.Meta.Deck[deckPosition:deckPosition+count]...)
+			deckPosition += count
+		}
+
+		if len(board) < 5 {
+			break
+		}
+		runout.Boards = append(runout.Boards, board)
+	}
+	runout.Times = len(runout.Boards)
+
+	if runout.Times < 2 {
+		return
+	}
+
+	// 各次公牌的玩家牌力
+	powers := make([]map[int]uint64, 0)
+	for _, board := range runout.Boards {
+		boardPowers := make(map[int]uint64)
+		for _, p := range gs.Players {
+			if !p.Fold {
+				boardPowers[p.Idx] = bestCombinationScore(gs, p.HoleCards, board)
+			}
+		}
+		powers = append(powers, boardPowers)
+	}
+
+	for potIdx, pot := range gs.Result.Pots {
+		if potIdx >= len(gs.Status.Pots) {
+			continue
+		}
+
+		contributors := make([]int, 0)
+		for gamePlayerIdx := range gs.Status.Pots[potIdx].Contributors {
+			contributors = append(contributors, gamePlayerIdx)
+		}
+		// 只有一位玩家的池不需依公牌重新分配
+		if len(contributors) < 2 {
+			continue
+		}
+		sort.Ints(contributors)
+
+		// 依各次公牌重新計算贏家
+		withdraws := make(map[int]int64)
+		based := pot.Total / int64(runout.Times)
+		remainder := pot.Total % int64(runout.Times)
+		for i, boardPowers := range powers {
+			share := based
+			if int64(i) < remainder {
+				share += 1
+			}
+
+			winners := make([]int, 0)
+			best := uint64(0)
+			for _, gamePlayerIdx := range contributors {
+				power := boardPowers[gamePlayerIdx]
+				if len(winners) == 0 || power > best {
+					best = power
+					winners = []int{gamePlayerIdx}
+				} else if power == best {
+					winners = append(winners, gamePlayerIdx)
+				}
+			}
+
+			winnerBased := share / int64(len(winners))
+			winnerRemainder := share % int64(len(winners))
+			for j, gamePlayerIdx := range winners {
+				reward := winnerBased
+				if int64(j) < winnerRemainder {
+					reward += 1
+				}
+				withdraws[gamePlayerIdx] += reward
+			}
+		}
+
+		// 以新的贏家取代原本的贏家
+		for _, winner := range pot.Winners {
+			for _, player := range gs.Result.Players {
+				if player.Idx == winner.Idx {
+					player.Final -= winner.Withdraw
+					player.Changed -= winner.Withdraw
+				}
+			}
+		}
+
+		pot.Winners = make([]*settlement.Winner, 0)
+		for _, gamePlayerIdx := range contributors {
+			withdraw := withdraws[gamePlayerIdx]
+			if withdraw == 0 {
+				continue
+			}
+
+			pot.Winners = append(pot.Winners, &settlement.Winner{
+				Idx:      gamePlayerIdx,
+				Withdraw: withdraw,
+			})
+			for _, player := range gs.Result.Players {
+				if player.Idx == gamePlayerIdx {
+					player.Final += withdraw
+					player.Changed += withdraw
+				}
+			}
+		}
+	}
+}
+
+// bestCombinationScore 計算玩家底牌搭配指定公牌的最大牌力
+func bestCombinationScore(gs *pokerface.GameState, holeCards []string, board []string) uint64 {
+	best := uint64(0)
+	for _, cards := range combination.GetAllPossibleCombinations(board, holeCards, gs.Meta.RequiredHoleCardsCount) {
+		ps := combination.CalculatePower(gs.Meta.CombinationPowers, cards)
+		if ps.Score > best {
+			best = ps.Score
+		}
+	}
+	return best
+}
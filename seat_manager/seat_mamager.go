@@ -2,6 +2,7 @@ package seat_manager
 
 import (
 	"errors"
+	"math/rand"
 )
 
 var (
@@ -16,7 +17,7 @@ var (
 	ErrAlreadyInitPositions    = errors.New("seat manager: already init positions")
 	ErrUnableToRotatePositions = errors.New("seat manager: unable to rotate positions")
 
-	SupportedRules = []string{Rule_Default, Rule_ShortDeck}
+	SupportedRules = []string{Rule_Default, Rule_ShortDeck, Rule_Omaha}
 )
 
 type SeatManager interface {
@@ -25,6 +26,9 @@ type SeatManager interface {
 	AssignSeats(playerSeatIDs map[string]int) error
 	RemoveSeats(playerIDs []string) error
 	UpdatePlayerHasChips(playerID string, hasChips bool) error
+	SitOutPlayer(playerID string) error
+	SitInPlayer(playerID string, waitForBB bool) error
+	ClearMissedBlinds(playerID string) error
 	JoinPlayers(playerIDs []string) error
 	InitPositions(isRandom bool) error
 	RotatePositions() error
@@ -37,26 +41,43 @@ type SeatManager interface {
 	IsInitPositions() bool
 	IsPlayerActive(playerID string) (bool, error)
 	ListPlayerSeatsFromDealer() []*SeatPlayer
+	GetState() SeatManagerState
 }
 
+type SeatManagerState struct {
+	MaxSeat      int                 `json:"max_seat"`
+	SeatData     map[int]*SeatPlayer `json:"seat_data"`      // key: seat_id (from 0 to MaxSeat - 1), value: seat (nil by default)
+	DealerSeatID int                 `json:"dealer_seat_id"` // UnsetSeatID by default
+	SBSeatID     int                 `json:"sb_seat_id"`     // UnsetSeatID by default
+	BBSeatID     int                 `json:"bb_seat_id"`     // UnsetSeatID by default
+	Rule         string              `json:"rule"`           // default, short_deck, omaha
+	IsInit       bool                `json:"is_init"`
+}
+
+type SeatManagerOpt func(*seatManager)
+
 type SeatPlayer struct {
 	ID                string `json:"id"`
 	IsIn              bool   `json:"is_in"`
 	IsBetweenDealerBB bool   `json:"is_between_dealer_bb"`
 	HasChips          bool   `json:"has_chips"`
+	IsSitOut          bool   `json:"is_sit_out"`    // 玩家是否暫離
+	IsWaitingBB       bool   `json:"is_waiting_bb"` // 玩家是否需等待輪到大盲才能回到牌局 (暫離期間錯過盲注)
+	MissedSB          bool   `json:"missed_sb"`     // 玩家暫離期間是否錯過小盲
+	MissedBB          bool   `json:"missed_bb"`     // 玩家暫離期間是否錯過大盲
 }
 
 func (sp *SeatPlayer) Active() bool {
-	return sp.IsIn && !sp.IsBetweenDealerBB && sp.HasChips
+	return sp.IsIn && !sp.IsSitOut && !sp.IsWaitingBB && !sp.IsBetweenDealerBB && sp.HasChips
 }
 
-func NewSeatManager(maxSeats int, rule string) SeatManager {
+func NewSeatManager(maxSeats int, rule string, opts ...SeatManagerOpt) SeatManager {
 	seatData := make(map[int]*SeatPlayer)
 	for i := 0; i < maxSeats; i++ {
 		seatData[i] = nil
 	}
 
-	return &seatManager{
+	sm := &seatManager{
 		MaxSeat:      maxSeats,
 		SeatData:     seatData,
 		DealerSeatID: UnsetSeatID,
@@ -65,8 +86,46 @@ func NewSeatManager(maxSeats int, rule string) SeatManager {
 		Rule:         rule,
 		IsInit:       false,
 	}
+
+	for _, opt := range opts {
+		opt(sm)
+	}
+
+	return sm
+}
+
+// WithRandom 指定隨機座位與隨機 Dealer 使用的亂數產生器
+func WithRandom(r *rand.Rand) SeatManagerOpt {
+	return func(sm *seatManager) {
+		sm.random = r
+	}
 }
 
-func NewSeatManagerFromState(sm *seatManager) SeatManager {
+func NewSeatManagerFromState(state SeatManagerState, opts ...SeatManagerOpt) SeatManager {
+	seatData := make(map[int]*SeatPlayer)
+	for i := 0; i < state.MaxSeat; i++ {
+		seatData[i] = nil
+	}
+	for seatID, seatPlayer := range state.SeatData {
+		if seatPlayer != nil {
+			sp := *seatPlayer
+			seatData[seatID] = &sp
+		}
+	}
+
+	sm := &seatManager{
+		MaxSeat:      state.MaxSeat,
+		SeatData:     seatData,
+		DealerSeatID: state.DealerSeatID,
+		SBSeatID:     state.SBSeatID,
+		BBSeatID:     state.BBSeatID,
+		Rule:         state.Rule,
+		IsInit:       state.IsInit,
+	}
+
+	for _, opt := range opts {
+		opt(sm)
+	}
+
 	return sm
 }
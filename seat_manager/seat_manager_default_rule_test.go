@@ -1,6 +1,7 @@
 package seat_manager
 
 import (
+	"encoding/json"
 	"maps"
 	"sync"
 	"testing"
@@ -1700,6 +1701,57 @@ func TestDefaultRule_AfterInitPositions_IsPlayerBetweenDealerBB(t *testing.T) {
 	assert.True(t, sm.IsPlayerBetweenDealerBB("P7"))
 }
 
+func TestDefaultRule_NewSeatManagerFromState(t *testing.T) {
+	maxSeat := 9
+	rule := Rule_Default
+	playerSeatIDs := map[string]int{
+		"P1": 0,
+		"P2": 3,
+		"P3": 4,
+		"P4": 7,
+	}
+
+	sm := NewSeatManager(maxSeat, rule)
+	err := sm.AssignSeats(playerSeatIDs)
+	assert.NoError(t, err)
+
+	// join players
+	err = sm.JoinPlayers([]string{"P1", "P2", "P3", "P4"})
+	assert.NoError(t, err)
+
+	err = sm.InitPositions(false)
+	assert.NoError(t, err)
+
+	// restore from state through json
+	encoded, err := json.Marshal(sm.GetState())
+	assert.NoError(t, err)
+
+	var state SeatManagerState
+	err = json.Unmarshal(encoded, &state)
+	assert.NoError(t, err)
+
+	restored := NewSeatManagerFromState(state)
+	assert.True(t, restored.IsInitPositions())
+	assert.Equal(t, sm.CurrentDealerSeatID(), restored.CurrentDealerSeatID())
+	assert.Equal(t, sm.CurrentSBSeatID(), restored.CurrentSBSeatID())
+	assert.Equal(t, sm.CurrentBBSeatID(), restored.CurrentBBSeatID())
+	assert.Equal(t, len(sm.Seats()), len(restored.Seats()))
+	for playerID, seatID := range playerSeatIDs {
+		restoredSeatID, err := restored.GetSeatID(playerID)
+		assert.NoError(t, err)
+		assert.Equal(t, seatID, restoredSeatID)
+	}
+
+	// both managers rotate independently to the same positions
+	err = sm.RotatePositions()
+	assert.NoError(t, err)
+	err = restored.RotatePositions()
+	assert.NoError(t, err)
+	assert.Equal(t, sm.CurrentDealerSeatID(), restored.CurrentDealerSeatID())
+	assert.Equal(t, sm.CurrentSBSeatID(), restored.CurrentSBSeatID())
+	assert.Equal(t, sm.CurrentBBSeatID(), restored.CurrentBBSeatID())
+}
+
 func verifySeatsAndPlayerPositions(t *testing.T, expectedSeatPositions map[string]int, expectedPlayerPositions map[string][]string, sm SeatManager) {
 	// check seats
 	assert.Equal(t, expectedSeatPositions[Position_Dealer], sm.CurrentDealerSeatID())
@@ -1719,3 +1771,155 @@ func verifySeatsAndPlayerPositions(t *testing.T, expectedSeatPositions map[strin
 		}
 	}
 }
+
+func TestDefaultRule_SitOutAndSitIn_BeforeRotatePositions(t *testing.T) {
+	maxSeat := 9
+	rule := Rule_Default
+	playerSeatIDs := map[string]int{
+		"P1": 0, // bb
+		"P2": 3, // ug
+		"P3": 4, // dealer
+		"P4": 7, // sb
+	}
+
+	sm := NewSeatManager(maxSeat, rule)
+	err := sm.AssignSeats(playerSeatIDs)
+	assert.NoError(t, err)
+
+	// join all players
+	err = sm.JoinPlayers([]string{"P1", "P2", "P3", "P4"})
+	assert.NoError(t, err)
+
+	err = sm.InitPositions(false)
+	assert.NoError(t, err)
+
+	// P2 sits out & sits in before next round
+	err = sm.SitOutPlayer("P2")
+	assert.NoError(t, err)
+	active, err := sm.IsPlayerActive("P2")
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	err = sm.SitInPlayer("P2", true)
+	assert.NoError(t, err)
+	active, err = sm.IsPlayerActive("P2")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	// unknown player
+	assert.ErrorIs(t, sm.SitOutPlayer("P10"), ErrPlayerNotFound)
+	assert.ErrorIs(t, sm.SitInPlayer("P10", true), ErrPlayerNotFound)
+}
+
+func TestDefaultRule_SitOutAndSitIn_WaitForBB(t *testing.T) {
+	maxSeat := 9
+	rule := Rule_Default
+	playerSeatIDs := map[string]int{
+		"P1": 0, // bb
+		"P2": 3, // ug
+		"P3": 4, // dealer
+		"P4": 7, // sb
+	}
+	expectedRoundSeatPositions := []map[string]int{
+		// P2 sits out, BB skips P2
+		{Position_Dealer: 7, Position_SB: 0, Position_BB: 4},
+		// P2 sits in, waits for BB
+		{Position_Dealer: 0, Position_SB: 4, Position_BB: 7},
+		{Position_Dealer: 4, Position_SB: 7, Position_BB: 0},
+		// P2 is BB, comes back
+		{Position_Dealer: 7, Position_SB: 0, Position_BB: 3},
+	}
+	expectedRoundP2Active := []bool{false, false, false, true}
+
+	sm := NewSeatManager(maxSeat, rule)
+	err := sm.AssignSeats(playerSeatIDs)
+	assert.NoError(t, err)
+
+	// join all players
+	err = sm.JoinPlayers([]string{"P1", "P2", "P3", "P4"})
+	assert.NoError(t, err)
+
+	err = sm.InitPositions(false)
+	assert.NoError(t, err)
+
+	err = sm.SitOutPlayer("P2")
+	assert.NoError(t, err)
+
+	for round, expectedSeatPositions := range expectedRoundSeatPositions {
+		err = sm.RotatePositions()
+		assert.NoError(t, err)
+
+		assert.Equal(t, expectedSeatPositions[Position_Dealer], sm.CurrentDealerSeatID(), "round %d dealer", round)
+		assert.Equal(t, expectedSeatPositions[Position_SB], sm.CurrentSBSeatID(), "round %d sb", round)
+		assert.Equal(t, expectedSeatPositions[Position_BB], sm.CurrentBBSeatID(), "round %d bb", round)
+
+		active, err := sm.IsPlayerActive("P2")
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRoundP2Active[round], active, "round %d P2 active", round)
+
+		if round == 0 {
+			assert.True(t, sm.Seats()[3].MissedSB)
+			assert.True(t, sm.Seats()[3].MissedBB)
+
+			// 錯過大盲的玩家回座後需等待大盲
+			err = sm.SitInPlayer("P2", true)
+			assert.NoError(t, err)
+			active, err = sm.IsPlayerActive("P2")
+			assert.NoError(t, err)
+			assert.False(t, active)
+		}
+	}
+
+	assert.False(t, sm.Seats()[3].IsWaitingBB)
+	assert.False(t, sm.Seats()[3].MissedSB)
+	assert.False(t, sm.Seats()[3].MissedBB)
+}
+
+func TestDefaultRule_SitOut_MissedSB_PostDeadBlinds(t *testing.T) {
+	maxSeat := 9
+	rule := Rule_Default
+	playerSeatIDs := map[string]int{
+		"P1": 0, // bb
+		"P2": 3, // ug
+		"P3": 4, // dealer
+		"P4": 7, // sb
+	}
+
+	sm := NewSeatManager(maxSeat, rule)
+	err := sm.AssignSeats(playerSeatIDs)
+	assert.NoError(t, err)
+
+	// join all players
+	err = sm.JoinPlayers([]string{"P1", "P2", "P3", "P4"})
+	assert.NoError(t, err)
+
+	err = sm.InitPositions(false)
+	assert.NoError(t, err)
+
+	// P1 sits out right after bb, misses sb only
+	err = sm.SitOutPlayer("P1")
+	assert.NoError(t, err)
+
+	err = sm.RotatePositions()
+	assert.NoError(t, err)
+	assert.Equal(t, 7, sm.CurrentDealerSeatID())
+	assert.Equal(t, 0, sm.CurrentSBSeatID())
+	assert.Equal(t, 3, sm.CurrentBBSeatID())
+	assert.True(t, sm.Seats()[0].MissedSB)
+	assert.False(t, sm.Seats()[0].MissedBB)
+
+	// 立即補盲注回座
+	err = sm.SitInPlayer("P1", false)
+	assert.NoError(t, err)
+	active, err := sm.IsPlayerActive("P1")
+	assert.NoError(t, err)
+	assert.True(t, active)
+	assert.True(t, sm.Seats()[0].MissedSB)
+
+	err = sm.ClearMissedBlinds("P1")
+	assert.NoError(t, err)
+	assert.False(t, sm.Seats()[0].MissedSB)
+	assert.False(t, sm.Seats()[0].MissedBB)
+
+	assert.ErrorIs(t, sm.ClearMissedBlinds("P10"), ErrPlayerNotFound)
+}
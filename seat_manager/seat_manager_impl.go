@@ -2,6 +2,7 @@ package seat_manager
 
 import (
 	"fmt"
+	"math/rand"
 	"sync"
 
 	"github.com/thoas/go-funk"
@@ -16,6 +17,7 @@ type seatManager struct {
 	Rule         string              `json:"rule"`           // default, short_deck
 	IsInit       bool                `json:"is_init"`
 	mu           sync.RWMutex        `json:"-"`
+	random       *rand.Rand          `json:"-"`
 }
 
 func (sm *seatManager) GetSeatID(playerID string) (int, error) {
@@ -190,6 +192,60 @@ func (sm *seatManager) UpdatePlayerHasChips(playerID string, hasChips bool) erro
 	return nil
 }
 
+func (sm *seatManager) SitOutPlayer(playerID string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	_, seatID, err := sm.getSeatPlayer(playerID)
+	if err != nil {
+		sm.printState(1, func(tag int) {
+			fmt.Printf("[DEBUG#seatManager#SitOutPlayer#%d][getSeatPlayer] playerID: %s. Error: %+v\n", tag, playerID, err)
+		})
+		return err
+	}
+
+	sm.SeatData[seatID].IsSitOut = true
+	return nil
+}
+
+func (sm *seatManager) SitInPlayer(playerID string, waitForBB bool) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	_, seatID, err := sm.getSeatPlayer(playerID)
+	if err != nil {
+		sm.printState(1, func(tag int) {
+			fmt.Printf("[DEBUG#seatManager#SitInPlayer#%d][getSeatPlayer] playerID: %s, waitForBB: %+v. Error: %+v\n", tag, playerID, waitForBB, err)
+		})
+		return err
+	}
+
+	sp := sm.SeatData[seatID]
+	sp.IsSitOut = false
+	sp.IsBetweenDealerBB = false
+	sp.IsWaitingBB = waitForBB && (sp.MissedSB || sp.MissedBB)
+	return nil
+}
+
+func (sm *seatManager) ClearMissedBlinds(playerID string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	_, seatID, err := sm.getSeatPlayer(playerID)
+	if err != nil {
+		sm.printState(1, func(tag int) {
+			fmt.Printf("[DEBUG#seatManager#ClearMissedBlinds#%d][getSeatPlayer] playerID: %s. Error: %+v\n", tag, playerID, err)
+		})
+		return err
+	}
+
+	sp := sm.SeatData[seatID]
+	sp.MissedSB = false
+	sp.MissedBB = false
+	sp.IsWaitingBB = false
+	return nil
+}
+
 func (sm *seatManager) InitPositions(isRandom bool) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
@@ -296,6 +352,31 @@ func (sm *seatManager) ListPlayerSeatsFromDealer() []*SeatPlayer {
 	return seatPlayers
 }
 
+func (sm *seatManager) GetState() SeatManagerState {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	seatData := make(map[int]*SeatPlayer)
+	for seatID, seatPlayer := range sm.SeatData {
+		if seatPlayer != nil {
+			sp := *seatPlayer
+			seatData[seatID] = &sp
+		} else {
+			seatData[seatID] = nil
+		}
+	}
+
+	return SeatManagerState{
+		MaxSeat:      sm.MaxSeat,
+		SeatData:     seatData,
+		DealerSeatID: sm.DealerSeatID,
+		SBSeatID:     sm.SBSeatID,
+		BBSeatID:     sm.BBSeatID,
+		Rule:         sm.Rule,
+		IsInit:       sm.IsInit,
+	}
+}
+
 func (sm *seatManager) IsHU() bool {
 	/*
 		HU conditions
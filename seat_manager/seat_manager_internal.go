@@ -49,6 +49,7 @@ func (sm *seatManager) getEmptySeatIDs() []int {
 			emptySeatIDs = append(emptySeatIDs, seatID)
 		}
 	}
+	sort.Ints(emptySeatIDs)
 	return emptySeatIDs
 }
 
@@ -114,6 +115,10 @@ func (sm *seatManager) firstOccupiedSeat() (int, error) {
 }
 
 func (sm *seatManager) newRandom() *rand.Rand {
+	if sm.random != nil {
+		return sm.random
+	}
+
 	seed := time.Now().UnixNano()
 	source := rand.NewSource(seed)
 	return rand.New(source)
@@ -135,7 +140,7 @@ func (sm *seatManager) nextOccupiedSeatID(startSeatID int) int {
 func (sm *seatManager) nextInAndHasChipsSeatID(startSeatID int) int {
 	for i := 1; i < sm.MaxSeat; i++ {
 		seatID := (startSeatID + i) % 9
-		if sp, exist := sm.SeatData[seatID]; exist && sp != nil && sp.HasChips && sp.IsIn {
+		if sp, exist := sm.SeatData[seatID]; exist && sp != nil && sp.HasChips && sp.IsIn && !sp.IsSitOut {
 			return seatID
 		}
 	}
@@ -199,6 +204,9 @@ func (sm *seatManager) getActivePlayerCount() int {
   - BB 前一個有坐人的玩家當作 SB
   - SB 前一個有坐人的玩家當作 Dealer
 
+- 奧瑪哈
+  - 同常牌
+
 - 短牌
   - 隨機挑選一個位置當作 Dealer
 */
@@ -232,7 +240,7 @@ func (sm *seatManager) initPositions(isRandom bool) error {
 		firstSeatID = seatID
 	}
 
-	if sm.Rule == Rule_Default {
+	if sm.isBlindRule() {
 		// pick an occupied seat id as BB
 		sm.BBSeatID = firstSeatID
 		if activeCount == 2 {
@@ -288,13 +296,16 @@ func (sm *seatManager) initPositions(isRandom bool) error {
   - 新的 SB 為上一次 BB，如果上一次 BB 玩家沒籌碼或不在位置上，新的 SB 依然是這個位置
   - 新的 Dealer 為上一次 SB，如果上一次 SB 玩家沒籌碼或不在位置上，新的 Dealer 依然是這個位置
 
+- 奧瑪哈
+  - 同常牌
+
 - 短牌
   - Dealer 往下一個座位找，直到找到有籌碼的玩家為止
 */
 func (sm *seatManager) rotatePositions() error {
 	previousRoundIsHU := sm.IsHU()
 
-	if sm.Rule == Rule_Default {
+	if sm.isBlindRule() {
 		previousSBSeatID := sm.SBSeatID
 		previousBBSeatID := sm.BBSeatID
 
@@ -302,6 +313,9 @@ func (sm *seatManager) rotatePositions() error {
 		newBBSeatID := sm.nextInAndHasChipsSeatID(previousBBSeatID)
 		tempNewDealerSeatID := previousSBSeatID
 
+		// update seat_player missed blinds before
+		sm.updateMissedBB(previousBBSeatID, newBBSeatID)
+
 		// update seat_player.IsBetweenDealerBB before
 		for seatID, sp := range sm.Seats() {
 			if sp != nil && !sp.Active() {
@@ -341,6 +355,7 @@ func (sm *seatManager) rotatePositions() error {
 				sm.DealerSeatID = previousSBSeatID
 			}
 		}
+		sm.updateMissedSB(sm.SBSeatID)
 	} else if sm.Rule == Rule_ShortDeck {
 		if sm.getActivePlayerCount() < 2 {
 			sm.printState(2, func(tag int) {
@@ -364,6 +379,46 @@ func (sm *seatManager) rotatePositions() error {
 	return nil
 }
 
+/*
+- 大盲經過暫離玩家時，該玩家錯過大盲與小盲
+- 輪到大盲的玩家不需再補盲注
+*/
+func (sm *seatManager) updateMissedBB(previousBBSeatID, newBBSeatID int) {
+	if newBBSeatID == UnsetSeatID {
+		return
+	}
+
+	for i := previousBBSeatID + 1; i < previousBBSeatID+sm.MaxSeat; i++ {
+		seatID := i % sm.MaxSeat
+		if seatID == newBBSeatID {
+			break
+		}
+
+		if sp, exist := sm.SeatData[seatID]; exist && sp != nil && sp.IsIn && sp.IsSitOut {
+			sp.MissedSB = true
+			sp.MissedBB = true
+		}
+	}
+
+	if sp, exist := sm.SeatData[newBBSeatID]; exist && sp != nil {
+		sp.MissedSB = false
+		sp.MissedBB = false
+		sp.IsWaitingBB = false
+	}
+}
+
+// updateMissedSB 小盲位置為暫離玩家時，該玩家錯過小盲
+func (sm *seatManager) updateMissedSB(sbSeatID int) {
+	if sp, exist := sm.SeatData[sbSeatID]; exist && sp != nil && sp.IsIn && sp.IsSitOut {
+		sp.MissedSB = true
+	}
+}
+
+// isBlindRule 是否為使用 SB/BB 盲注的規則 (常牌、奧瑪哈)
+func (sm *seatManager) isBlindRule() bool {
+	return sm.Rule == Rule_Default || sm.Rule == Rule_Omaha
+}
+
 func (sm *seatManager) newSeatPlayer(playerID string) SeatPlayer {
 	return SeatPlayer{
 		ID:       playerID,
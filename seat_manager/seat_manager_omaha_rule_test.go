@@ -0,0 +1,268 @@
+package seat_manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOmahaRule_NotRandomInitPositions_TwoPlayers(t *testing.T) {
+	maxSeat := 9
+	rule := Rule_Omaha
+	playerSeatIDs := map[string]int{
+		"P1": 0, // bb
+		"P2": 3, // dealer/sb
+	}
+	expectedSeatPositions := map[string]int{
+		Position_Dealer: 3,
+		Position_SB:     3,
+		Position_BB:     0,
+	}
+	expectedPlayerPositions := map[string][]string{
+		"P1": {Position_BB},
+		"P2": {Position_Dealer, Position_SB},
+	}
+
+	sm := NewSeatManager(maxSeat, rule)
+	err := sm.AssignSeats(playerSeatIDs)
+	assert.NoError(t, err)
+
+	// P1 & P2 join
+	joinPlayers := []string{"P1", "P2"}
+	err = sm.JoinPlayers(joinPlayers)
+	assert.NoError(t, err)
+
+	for _, seatPlayer := range sm.Seats() {
+		if seatPlayer != nil {
+			assert.Contains(t, joinPlayers, seatPlayer.ID)
+			assert.True(t, seatPlayer.Active())
+		}
+	}
+
+	err = sm.InitPositions(false)
+	assert.NoError(t, err)
+	assert.True(t, sm.IsInitPositions())
+
+	verifySeatsAndPlayerPositions(t, expectedSeatPositions, expectedPlayerPositions, sm)
+}
+
+func TestOmahaRule_NotRandomInitPositions_MoreThanTwoPlayers(t *testing.T) {
+	maxSeat := 9
+	rule := Rule_Omaha
+	playerSeatIDs := map[string]int{
+		"P1": 0, // bb
+		"P2": 3, // ug
+		"P3": 4, // dealer
+		"P4": 7, // sb
+	}
+	expectedSeatPositions := map[string]int{
+		Position_Dealer: 4,
+		Position_SB:     7,
+		Position_BB:     0,
+	}
+	expectedPlayerPositions := map[string][]string{
+		"P1": {Position_BB},
+		"P2": {},
+		"P3": {Position_Dealer},
+		"P4": {Position_SB},
+	}
+
+	sm := NewSeatManager(maxSeat, rule)
+	err := sm.AssignSeats(playerSeatIDs)
+	assert.NoError(t, err)
+
+	// join all players
+	playerIDs := []string{"P1", "P2", "P3", "P4"}
+	err = sm.JoinPlayers(playerIDs)
+	assert.NoError(t, err)
+
+	for _, seatPlayer := range sm.Seats() {
+		if seatPlayer != nil {
+			assert.Contains(t, playerIDs, seatPlayer.ID)
+			assert.True(t, seatPlayer.Active())
+		}
+	}
+
+	err = sm.InitPositions(false)
+	assert.NoError(t, err)
+	assert.True(t, sm.IsInitPositions())
+
+	verifySeatsAndPlayerPositions(t, expectedSeatPositions, expectedPlayerPositions, sm)
+}
+
+func TestOmahaRule_RotatePositions_MultipleTimes_TwoPlayers(t *testing.T) {
+	maxSeat := 9
+	rule := Rule_Omaha
+	playerSeatIDs := map[string]int{
+		"P1": 0,
+		"P2": 3,
+	}
+	expectedSeatPositions_OddGameCounts := map[string]int{
+		Position_Dealer: 3,
+		Position_SB:     3,
+		Position_BB:     0,
+	}
+	expectedPlayerPositions_OddGameCounts := map[string][]string{
+		"P1": {Position_BB},
+		"P2": {Position_Dealer, Position_SB},
+	}
+	expectedSeatPositions_EvenGameCounts := map[string]int{
+		Position_Dealer: 0,
+		Position_SB:     0,
+		Position_BB:     3,
+	}
+	expectedPlayerPositions_EvenGameCounts := map[string][]string{
+		"P1": {Position_Dealer, Position_SB},
+		"P2": {Position_BB},
+	}
+
+	sm := NewSeatManager(maxSeat, rule)
+	err := sm.AssignSeats(playerSeatIDs)
+	assert.NoError(t, err)
+
+	// join all players
+	playerIDs := []string{"P1", "P2"}
+	err = sm.JoinPlayers(playerIDs)
+	assert.NoError(t, err)
+
+	for _, seatPlayer := range sm.Seats() {
+		if seatPlayer != nil {
+			assert.Contains(t, playerIDs, seatPlayer.ID)
+			assert.True(t, seatPlayer.Active())
+		}
+	}
+
+	for gameCount := 1; gameCount <= 10; gameCount++ {
+		var err error
+		if gameCount == 1 {
+			err = sm.InitPositions(false)
+			assert.NoError(t, err)
+			assert.True(t, sm.IsInitPositions())
+
+			verifySeatsAndPlayerPositions(t, expectedSeatPositions_OddGameCounts, expectedPlayerPositions_OddGameCounts, sm)
+		} else {
+			err = sm.RotatePositions()
+			assert.NoError(t, err)
+
+			if gameCount%2 == 0 {
+				verifySeatsAndPlayerPositions(t, expectedSeatPositions_EvenGameCounts, expectedPlayerPositions_EvenGameCounts, sm)
+			} else {
+				verifySeatsAndPlayerPositions(t, expectedSeatPositions_OddGameCounts, expectedPlayerPositions_OddGameCounts, sm)
+			}
+		}
+	}
+}
+
+func TestOmahaRule_RotatePositions_MultipleTimes_MoreThanTwoPlayers_ValidDealerSBBB(t *testing.T) {
+	maxSeat := 9
+	rule := Rule_Omaha
+	playerSeatIDs := map[string]int{
+		"P1": 0,
+		"P2": 3,
+		"P3": 4,
+		"P4": 7,
+	}
+	expectedSeatPositions := []map[string]int{
+		// game count = 1 (only care about dealer, sb & bb)
+		{
+			Position_Dealer: 4, // P3
+			Position_SB:     7, // P4
+			Position_BB:     0, // P1
+		},
+		// game count = 2 (only care about dealer, sb & bb)
+		{
+			Position_Dealer: 7, // P4
+			Position_SB:     0, // P1
+			Position_BB:     3, // P2
+		},
+		// game count = 3 (only care about dealer, sb & bb)
+		{
+			Position_Dealer: 0, // P1
+			Position_SB:     3, // P2
+			Position_BB:     4, // P3
+		},
+		// game count = 4 (only care about dealer, sb & bb)
+		{
+			Position_Dealer: 3, // P2
+			Position_SB:     4, // P3
+			Position_BB:     7, // P4
+		},
+		// game count = 5 (only care about dealer, sb & bb)
+		{
+			Position_Dealer: 4, // P3
+			Position_SB:     7, // P4
+			Position_BB:     0, // P1
+		},
+	}
+	expectedPlayerPositions := []map[string][]string{
+		// game count = 1 (only care about dealer, sb & bb)
+		{
+			"P1": {Position_BB},
+			"P2": {},
+			"P3": {Position_Dealer},
+			"P4": {Position_SB},
+		},
+		// game count = 2 (only care about dealer, sb & bb)
+		{
+			"P1": {Position_SB},
+			"P2": {Position_BB},
+			"P3": {},
+			"P4": {Position_Dealer},
+		},
+		// game count = 3 (only care about dealer, sb & bb)
+		{
+			"P1": {Position_Dealer},
+			"P2": {Position_SB},
+			"P3": {Position_BB},
+			"P4": {},
+		},
+		// game count = 4 (only care about dealer, sb & bb)
+		{
+			"P1": {},
+			"P2": {Position_Dealer},
+			"P3": {Position_SB},
+			"P4": {Position_BB},
+		},
+		// game count = 5 (only care about dealer, sb & bb)
+		{
+			"P1": {Position_BB},
+			"P2": {},
+			"P3": {Position_Dealer},
+			"P4": {Position_SB},
+		},
+	}
+
+	sm := NewSeatManager(maxSeat, rule)
+	err := sm.AssignSeats(playerSeatIDs)
+	assert.NoError(t, err)
+
+	// join all players
+	playerIDs := []string{"P1", "P2", "P3", "P4"}
+	err = sm.JoinPlayers(playerIDs)
+	assert.NoError(t, err)
+
+	for _, seatPlayer := range sm.Seats() {
+		if seatPlayer != nil {
+			assert.Contains(t, playerIDs, seatPlayer.ID)
+			assert.True(t, seatPlayer.Active())
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		gameCount := i + 1
+		expectedSeatPosition := expectedSeatPositions[i]
+		expectedPlayerPosition := expectedPlayerPositions[i]
+
+		var err error
+		if gameCount == 1 {
+			err = sm.InitPositions(false)
+			assert.NoError(t, err)
+			assert.True(t, sm.IsInitPositions())
+		} else {
+			err = sm.RotatePositions()
+			assert.NoError(t, err)
+		}
+
+		verifySeatsAndPlayerPositions(t, expectedSeatPosition, expectedPlayerPosition, sm)
+	}
+}
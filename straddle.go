@@ -0,0 +1,84 @@
+package pokertable
+
+import (
+	"errors"
+
+	"github.com/thoas/go-funk"
+)
+
+var (
+	ErrTableStraddleNotAllowed    = errors.New("table: straddle is not allowed")
+	ErrTableStraddleInvalidPlayer = errors.New("table: player is not able to straddle")
+)
+
+var SupportedStraddleModes = []string{
+	StraddleMode_UTG,
+	StraddleMode_Mississippi,
+}
+
+/*
+isValidStraddleSetting 檢查 Straddle 設定
+  - 只開放現金桌，且固定限注不開放 Straddle
+*/
+func isValidStraddleSetting(meta TableMeta) bool {
+	if meta.StraddleMode == "" {
+		return true
+	}
+
+	if !funk.ContainsString(SupportedStraddleModes, meta.StraddleMode) {
+		return false
+	}
+
+	return meta.Mode == CompetitionMode_Cash && meta.BettingStructure != BettingStructure_FixedLimit
+}
+
+/*
+validateStraddle 檢查玩家本手是否可以 Straddle
+  - 本手至少三位玩家，且非 Bomb Pot
+  - StraddleMode_UTG: 只有大盲的下一位玩家 (槍口位) 可以 Straddle
+  - StraddleMode_Mississippi: 大小盲以外的玩家皆可 Straddle
+  - 補盲注的玩家不可 Straddle，且扣除代付前注後籌碼需多於 Straddle 籌碼量
+*/
+func (te *tableEngine) validateStraddle(gamePlayerIdx int, chips int64) error {
+	if !isValidStraddleSetting(te.table.Meta) || te.table.Meta.StraddleMode == "" || te.table.State.GameBombPot > 0 {
+		return ErrTableStraddleNotAllowed
+	}
+
+	gs := te.game.GetGameState()
+	if len(gs.Players) < 3 {
+		return ErrTableStraddleNotAllowed
+	}
+
+	player := gs.GetPlayer(gamePlayerIdx)
+	if player == nil {
+		return ErrTablePlayerNotFound
+	}
+
+	if gs.HasPosition(gamePlayerIdx, Position_SB) || gs.HasPosition(gamePlayerIdx, Position_BB) {
+		return ErrTableStraddleInvalidPlayer
+	}
+
+	if te.table.Meta.StraddleMode == StraddleMode_UTG {
+		bbIdx := UnsetValue
+		for _, p := range gs.Players {
+			if funk.ContainsString(p.Positions, Position_BB) {
+				bbIdx = p.Idx
+			}
+		}
+
+		if bbIdx == UnsetValue || (bbIdx+1)%len(gs.Players) != gamePlayerIdx {
+			return ErrTableStraddleInvalidPlayer
+		}
+	}
+
+	post := te.table.State.GameBlindPosts[gamePlayerIdx]
+	if post.Live > 0 || post.Dead > 0 {
+		return ErrTableStraddleInvalidPlayer
+	}
+
+	if player.Bankroll-post.Ante <= chips {
+		return ErrTableStraddleInvalidPlayer
+	}
+
+	return nil
+}
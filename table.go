@@ -34,14 +34,42 @@ type Table struct {
 }
 
 type TableMeta struct {
-	CompetitionID       string `json:"competition_id"`         // 賽事 ID
-	Rule                string `json:"rule"`                   // 德州撲克規則, 常牌(default), 短牌(short_deck), 奧瑪哈(omaha)
-	Mode                string `json:"mode"`                   // 賽事模式 (CT, MTT, Cash)
-	MaxDuration         int    `json:"max_duration"`           // 比賽時間總長 (Seconds)
-	TableMaxSeatCount   int    `json:"table_max_seat_count"`   // 每桌人數上限
-	TableMinPlayerCount int    `json:"table_min_player_count"` // 每桌最小開打數
-	MinChipUnit         int64  `json:"min_chip_unit"`          // 最小單位籌碼量
-	ActionTime          int    `json:"action_time"`            // 玩家動作思考時間 (Seconds)
+	CompetitionID         string              `json:"competition_id"`           // 賽事 ID
+	Rule                  string              `json:"rule"`                     // 德州撲克規則, 常牌(default), 短牌(short_deck), 奧瑪哈(omaha)
+	BettingStructure      string              `json:"betting_structure"`        // 下注結構, 無限注(no_limit), 底池限注(pot_limit), 固定限注(fixed_limit)
+	FixedLimitRaiseCap    int                 `json:"fixed_limit_raise_cap"`    // 固定限注每條街加注次數上限 (0 表示使用預設值)
+	Mode                  string              `json:"mode"`                     // 賽事模式 (CT, MTT, Cash)
+	MaxDuration           int                 `json:"max_duration"`             // 比賽時間總長 (Seconds)
+	TableMaxSeatCount     int                 `json:"table_max_seat_count"`     // 每桌人數上限
+	TableMinPlayerCount   int                 `json:"table_min_player_count"`   // 每桌最小開打數
+	MinChipUnit           int64               `json:"min_chip_unit"`            // 最小單位籌碼量
+	ActionTime            int                 `json:"action_time"`              // 玩家動作思考時間 (Seconds)
+	TimeBankInitial       int                 `json:"time_bank_initial"`        // 玩家初始時間銀行 (Seconds)
+	TimeBankLevelTopUp    int                 `json:"time_bank_level_top_up"`   // 盲注每升一級補充的時間銀行 (Seconds)
+	ActionTimeoutEnforced bool                `json:"action_timeout_enforced"`  // 是否由桌次引擎處理玩家動作超時 (自動過牌或棄牌)
+	MaxActionTimeoutCount int                 `json:"max_action_timeout_count"` // 玩家連續動作超時幾次後設為暫離 (0 表示不會暫離)
+	PostDeadBlinds        bool                `json:"post_dead_blinds"`         // 現金桌錯過盲注的玩家回座時是否立即補盲注 (否則等待輪到大盲)
+	Rake                  TableRakeSetting    `json:"rake"`                     // 現金桌抽水設定
+	MaxRunItTimes         int                 `json:"max_run_it_times"`         // 全下後最多可發牌次數 (2: 發兩次, 3: 發三次, 0 或 1 表示不開放)
+	StraddleMode          string              `json:"straddle_mode,omitempty"`  // 現金桌 Straddle 模式, 不開放(""), 槍口位(utg), 密西西比(mississippi)
+	BombPot               TableBombPotSetting `json:"bomb_pot"`                 // 現金桌 Bomb Pot 設定
+}
+
+type TableBombPotSetting struct {
+	Amount      int64 `json:"amount"`       // 每位玩家支付的籌碼量，0 表示不開放
+	Interval    int   `json:"interval"`     // 每幾手自動進行一次 Bomb Pot (0 表示不定期)
+	VoteEnabled bool  `json:"vote_enabled"` // 是否開放玩家於開局前投票 (本手玩家全數同意才進行)
+}
+
+type TableRakeSetting struct {
+	Percent      float64        `json:"percent"`         // 抽水比例 (%)，0 表示不抽水
+	Caps         []TableRakeCap `json:"caps"`            // 每手抽水上限 (依本手玩家人數)
+	NoFlopNoDrop bool           `json:"no_flop_no_drop"` // 未發翻牌的牌局是否不抽水
+}
+
+type TableRakeCap struct {
+	MinPlayerCount int   `json:"min_player_count"` // 本手玩家人數達此數量時適用
+	Cap            int64 `json:"cap"`              // 抽水上限 (0 表示無上限)
 }
 
 type TableState struct {
@@ -59,9 +87,49 @@ type TableState struct {
 	GamePlayerIndexes    []int                  `json:"game_player_indexes"`      // 本手正在玩的 PlayerIndex 陣列 (陣列 index 為從 Dealer 位置開始的 PlayerIndex)，GameEngine 用
 	GameState            *pokerface.GameState   `json:"game_state"`               // 本手狀態
 	LastPlayerGameAction *TablePlayerGameAction `json:"last_player_game_action"`  // 最新一筆玩家牌局動作
+	WagerLimit           *TableWagerLimit       `json:"wager_limit"`              // 當前動作玩家的下注限制
+	GameBlindPosts       map[int]GameBlindPost  `json:"game_blind_posts"`         // 本手錯過盲注玩家的補盲注與代付全桌前注 (key: game player index)
+	GameRake             *TableGameRake         `json:"game_rake"`                // 本手抽水紀錄 (現金桌結算後才有值)
+	GameRunout           *TableGameRunout       `json:"game_runout"`              // 本手多次發牌紀錄 (全下後詢問玩家時才有值)
+	GameEquity           *TableGameEquity       `json:"game_equity"`              // 本手全下後各玩家勝率 (全下且該輪下注結束後才有值)
+	GameBombPot          int64                  `json:"game_bomb_pot"`            // 本手 Bomb Pot 每位玩家支付的籌碼量 (0 表示非 Bomb Pot)
+	GameRoundBetCount    int                    `json:"game_round_bet_count"`     // 本回合完整下注/加注次數 (不含盲注，回合結束時重置)
 	NextBBOrderPlayerIDs []string               `json:"next_bb_order_player_ids"` // 下一手 BB 座位玩家 ID 陣列
 }
 
+type TableGameRake struct {
+	GameID      string           `json:"game_id"`      // 遊戲 ID
+	GameCount   int              `json:"game_count"`   // 執行牌局遊戲次數 (遊戲跑幾輪)
+	PlayerCount int              `json:"player_count"` // 本手玩家人數
+	Cap         int64            `json:"cap"`          // 本手抽水上限 (0 表示無上限)
+	Total       int64            `json:"total"`        // 本手抽水總量
+	Pots        []int64          `json:"pots"`         // 各池抽水量 (index 同 GameState.Result.Pots)
+	Players     map[string]int64 `json:"players"`      // 各贏家被抽水量 (key: player id)
+}
+
+type TableGameRunout struct {
+	BoardSize   int            `json:"board_size"`   // 詢問時已發出的公牌數
+	PlayerIDs   []string       `json:"player_ids"`   // 需同意的全下玩家 ID 陣列
+	AgreedTimes map[string]int `json:"agreed_times"` // 已同意的玩家與同意的發牌次數 (key: player id)
+	Times       int            `json:"times"`        // 實際發牌次數 (結算時決定)
+	Boards      [][]string     `json:"boards"`       // 各次發牌的公牌 (結算時決定)
+}
+
+type TableGameEquity struct {
+	Round        string              `json:"round"`         // 計算時的回合
+	Board        []string            `json:"board"`         // 計算時已發出的公牌
+	IsExhaustive bool                `json:"is_exhaustive"` // 是否為窮舉 (否則為蒙地卡羅模擬)
+	Samples      int                 `json:"samples"`       // 計算的公牌組合數
+	Players      []TablePlayerEquity `json:"players"`       // 未棄牌玩家勝率
+}
+
+type TablePlayerEquity struct {
+	PlayerID string  `json:"player_id"` // 玩家 ID
+	Win      float64 `json:"win"`       // 獨贏機率
+	Tie      float64 `json:"tie"`       // 平手機率
+	Equity   float64 `json:"equity"`    // 期望勝率 (平手時依平手人數平分)
+}
+
 type TablePlayerGameAction struct {
 	CompetitionID    string   `json:"competition_id"`     // 賽事 ID
 	TableID          string   `json:"table_id"`           // 桌次 ID
@@ -81,22 +149,47 @@ type TablePlayerGameAction struct {
 	Wager            int64    `json:"wager"`              // GameState.Player Wager
 }
 
+type TableWagerLimit struct {
+	MinBet        int64 `json:"min_bet"`         // 最小下注量
+	MaxBet        int64 `json:"max_bet"`         // 最大下注量
+	MinRaise      int64 `json:"min_raise"`       // 最小加注到的籌碼量 (ChipLevel)
+	MaxRaise      int64 `json:"max_raise"`       // 最大加注到的籌碼量 (ChipLevel)
+	IsRaiseCapped bool  `json:"is_raise_capped"` // 本回合是否已達加注次數上限 (固定限注)
+}
+
+type TableLegalActions struct {
+	PlayerID    string   `json:"player_id"`    // 玩家 ID
+	Actions     []string `json:"actions"`      // 玩家可執行的動作
+	CallAmount  int64    `json:"call_amount"`  // 跟注需補的籌碼量
+	AllinAmount int64    `json:"allin_amount"` // 全下籌碼量
+	MinBet      int64    `json:"min_bet"`      // 最小下注量
+	MaxBet      int64    `json:"max_bet"`      // 最大下注量
+	MinRaise    int64    `json:"min_raise"`    // 最小加注到的籌碼量 (ChipLevel)
+	MaxRaise    int64    `json:"max_raise"`    // 最大加注到的籌碼量 (ChipLevel)
+}
+
 type TablePlayerState struct {
-	PlayerID       string                    `json:"player_id"`       // 玩家 ID
-	Seat           int                       `json:"seat"`            // 座位編號 0 ~ 8
-	Positions      []string                  `json:"positions"`       // 場上位置
-	IsParticipated bool                      `json:"is_participated"` // 玩家是否參戰
-	Bankroll       int64                     `json:"bankroll"`        // 玩家身上籌碼
-	IsIn           bool                      `json:"is_in"`           // 玩家是否入座
-	GameStatistics TablePlayerGameStatistics `json:"game_statistics"` // 玩家每手遊戲統計
+	PlayerID           string                    `json:"player_id"`            // 玩家 ID
+	Seat               int                       `json:"seat"`                 // 座位編號 0 ~ 8
+	Positions          []string                  `json:"positions"`            // 場上位置
+	IsParticipated     bool                      `json:"is_participated"`      // 玩家是否參戰
+	Bankroll           int64                     `json:"bankroll"`             // 玩家身上籌碼
+	TimeBank           int                       `json:"time_bank"`            // 玩家剩餘時間銀行 (Seconds)
+	ActionTimeoutCount int                       `json:"action_timeout_count"` // 玩家連續動作超時次數
+	IsSitOut           bool                      `json:"is_sit_out"`           // 玩家是否暫離 (輪到暫離玩家時由桌次引擎自動過牌或棄牌)
+	MissedSB           bool                      `json:"missed_sb"`            // 玩家暫離期間是否錯過小盲 (現金桌)
+	MissedBB           bool                      `json:"missed_bb"`            // 玩家暫離期間是否錯過大盲 (現金桌)
+	IsIn               bool                      `json:"is_in"`                // 玩家是否入座
+	GameStatistics     TablePlayerGameStatistics `json:"game_statistics"`      // 玩家每手遊戲統計
 }
 
 type TableBlindState struct {
-	Level  int   `json:"level"`  // 盲注等級(-1 表示中場休息)
-	Ante   int64 `json:"ante"`   // 前注籌碼量
-	Dealer int64 `json:"dealer"` // 庄位籌碼量
-	SB     int64 `json:"sb"`     // 大盲籌碼量
-	BB     int64 `json:"bb"`     // 小盲籌碼量
+	Level    int    `json:"level"`               // 盲注等級(-1 表示中場休息)
+	Ante     int64  `json:"ante"`                // 前注籌碼量 (代付全桌前注時為代付玩家支付的總量)
+	AnteMode string `json:"ante_mode,omitempty"` // 前注模式, 每位玩家(per_player), 大盲代付(big_blind), 按鈕位代付(button), 空值視為每位玩家
+	Dealer   int64  `json:"dealer"`              // 庄位籌碼量
+	SB       int64  `json:"sb"`                  // 大盲籌碼量
+	BB       int64  `json:"bb"`                  // 小盲籌碼量
 }
 
 // Table Getters
@@ -230,6 +323,11 @@ func (bs TableBlindState) IsBreaking() bool {
 	return bs.Level == -1
 }
 
+// IsTableAnte 是否由單一玩家代付全桌前注
+func (bs TableBlindState) IsTableAnte() bool {
+	return bs.AnteMode == AnteMode_BigBlind || bs.AnteMode == AnteMode_Button
+}
+
 func (bs TableBlindState) IsSet() bool {
 	return bs.Level != 0 && bs.Ante != UnsetValue && bs.Dealer != UnsetValue && bs.SB != UnsetValue && bs.BB != UnsetValue
 }
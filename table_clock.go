@@ -0,0 +1,146 @@
+package pokertable
+
+import (
+	"sync"
+	"time"
+)
+
+// tableTimer 桌次計時器 (與 timebank.TimeBank 相同的介面)
+type tableTimer interface {
+	NewTask(duration time.Duration, fn func(isCancelled bool)) error
+	Cancel()
+	Extend(duration time.Duration) bool
+}
+
+/*
+tableClock 桌次時鐘
+  - 桌次引擎取得目前時間與建立計時器都經由時鐘
+  - 預設為系統時間，重播時改用依事件紀錄推進的時鐘 (計時器依紀錄觸發)
+*/
+type tableClock interface {
+	Now() time.Time
+	NewTimer(name string) tableTimer
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(name string) tableTimer {
+	return &systemTimer{}
+}
+
+/*
+systemTimer 系統時間計時器
+  - 行為與 timebank.TimeBank 相同，但可由多個 goroutine 同時操作 (動作、開局與逾時各自在不同的 goroutine)
+  - 同時只有一個任務，建立新任務或取消時，原本的任務以 isCancelled = true 觸發
+*/
+type systemTimer struct {
+	mu    sync.Mutex
+	timer *time.Timer
+	due   time.Time
+	fn    func(isCancelled bool)
+}
+
+func (t *systemTimer) NewTask(duration time.Duration, fn func(isCancelled bool)) error {
+	t.Cancel()
+
+	// 立即觸發
+	if duration <= 0 {
+		fn(false)
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var timer *time.Timer
+	timer = time.AfterFunc(duration, func() {
+		t.mu.Lock()
+		if t.timer != timer {
+			// 已取消或已被新的任務取代
+			t.mu.Unlock()
+			return
+		}
+		t.timer = nil
+		t.fn = nil
+		t.mu.Unlock()
+
+		fn(false)
+	})
+	t.timer = timer
+	t.due = time.Now().Add(duration)
+	t.fn = fn
+	return nil
+}
+
+func (t *systemTimer) Cancel() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timer == nil {
+		return
+	}
+
+	t.timer.Stop()
+	fn := t.fn
+	t.timer = nil
+	t.fn = nil
+	go fn(true)
+}
+
+func (t *systemTimer) Extend(duration time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// 沒有任務或已逾時 (逾時任務正在觸發)
+	if t.timer == nil || !t.timer.Stop() {
+		return false
+	}
+
+	t.due = t.due.Add(duration)
+	t.timer.Reset(time.Until(t.due))
+	return true
+}
+
+// withTableClock 指定桌次時鐘 (重播用)
+func withTableClock(clock tableClock) TableEngineOpt {
+	return func(te *tableEngine) {
+		te.clock = clock
+	}
+}
+
+// recordedTimer 逾時觸發時記錄 Timeout 指令，重播時依紀錄觸發同名的計時器
+type recordedTimer struct {
+	tableTimer
+	te   *tableEngine
+	name string
+}
+
+// newRecordedTimer 建立會記錄逾時的計時器 (名稱需在同一個桌次引擎中唯一)
+func (te *tableEngine) newRecordedTimer(name string) tableTimer {
+	return &recordedTimer{
+		tableTimer: te.clock.NewTimer(name),
+		te:         te,
+		name:       name,
+	}
+}
+
+func (t *recordedTimer) NewTask(duration time.Duration, fn func(isCancelled bool)) error {
+	// 立即觸發的計時器重播時也會立即觸發，不需記錄
+	if duration <= 0 {
+		return t.tableTimer.NewTask(duration, fn)
+	}
+
+	return t.tableTimer.NewTask(duration, func(isCancelled bool) {
+		if isCancelled {
+			fn(true)
+			return
+		}
+
+		defer t.te.recordUnlockedCommand("Timeout", "", map[string]interface{}{"timer": t.name})(nil)
+		fn(false)
+	})
+}
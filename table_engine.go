@@ -3,14 +3,14 @@ package pokertable
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"strings"
 	"sync"
-	"time"
 
+	"github.com/thoas/go-funk"
 	"github.com/weedbox/pokertable/open_game_manager"
 	"github.com/weedbox/pokertable/seat_manager"
 	"github.com/weedbox/syncsaga"
-	"github.com/weedbox/timebank"
 )
 
 var (
@@ -22,6 +22,8 @@ var (
 	ErrTablePlayerSeatUnavailable              = errors.New("table: player seat unavailable")
 	ErrTableOpenGameFailed                     = errors.New("table: failed to open game")
 	ErrTableOpenGameFailedInBlindBreakingLevel = errors.New("table: unable to open game when blind level is breaking")
+	ErrTablePlayerNotCurrentActor              = errors.New("table: player is not the current actor")
+	ErrTablePlayerTimeBankExhausted            = errors.New("table: player time bank exhausted")
 )
 
 type TableEngineOpt func(*tableEngine)
@@ -36,9 +38,16 @@ type TableEngine interface {
 	OnGamePlayerActionUpdated(fn func(gameAction TablePlayerGameAction))                                               // 遊戲玩家動作更新事件監聽器
 	OnAutoGameOpenEnd(fn func(competitionID, tableID string))                                                          // 自動開桌結束事件監聽器
 	OnReadyOpenFirstTableGame(fn func(competitionID, tableID string, gameCount int, playerStates []*TablePlayerState)) // 開始第一手遊戲監聽器
+	OnTablePlayerTimeBankUsed(fn func(competitionID, tableID string, playerState *TablePlayerState, duration int))     // 玩家使用時間銀行監聽器
+	OnTablePlayerActionTimeout(fn func(competitionID, tableID string, playerState *TablePlayerState))                  // 玩家動作超時監聽器
+	OnGameSettled(fn func(result TableGameResult))                                                                     // 本手結算結果監聽器
+	OnTablePatched(fn func(patch TablePatch))                                                                          // 桌次差異監聽器 (需啟用 WithTablePatch，旁觀者視角)
+	Subscribe(handler TableEventHandler, opts ...TableEventSubscribeOpt) TableEventSubscription                        // 訂閱桌次事件 (可有多個訂閱者)
 
 	// Other Actions
-	ReleaseTable() error // 結束釋放桌次
+	ReleaseTable() error                                       // 結束釋放桌次
+	GetSnapshot() (*TableEngineSnapshot, error)                // 取得桌次快照
+	RestoreTable(snapshot TableEngineSnapshot) (*Table, error) // 從快照還原桌次
 
 	// Table Actions
 	GetTable() *Table                                                                             // 取得桌次
@@ -48,6 +57,7 @@ type TableEngine interface {
 	CloseTable() error                                                                            // 關閉桌
 	StartTableGame() error                                                                        // 開打遊戲
 	UpdateBlind(level int, ante, dealer, sb, bb int64)                                            // 更新當前盲注資訊
+	UpdateAnteMode(mode string) error                                                             // 更新前注模式
 	SetUpTableGame(gameCount int, participants map[string]int)                                    // 設定某手遊戲
 	UpdateTablePlayers(joinPlayers []JoinPlayer, leavePlayerIDs []string) (map[string]int, error) // 更新桌上玩家數量
 
@@ -55,10 +65,14 @@ type TableEngine interface {
 	PlayerReserve(joinPlayer JoinPlayer) error     // 玩家確認座位
 	PlayerJoin(playerID string) error              // 玩家入桌
 	PlayerSettlementFinish(playerID string) error  // 玩家結算完成
+	PlayerVoteBombPot(playerID string) error       // 玩家投票同意下一手進行 Bomb Pot
 	PlayerRedeemChips(joinPlayer JoinPlayer) error // 增購籌碼
 	PlayersLeave(playerIDs []string) error         // 玩家們離桌
+	PlayerSitOut(playerID string) error            // 玩家暫離
+	PlayerSitIn(playerID string) error             // 玩家回座
 
 	// Player Game Actions
+	PlayerLegalActions(playerID string) (*TableLegalActions, error)          // 取得玩家可執行動作與籌碼範圍
 	PlayerExtendActionDeadline(playerID string, duration int) (int64, error) // 延長玩家動作結束時間
 	PlayerReady(playerID string) error                                       // 玩家準備動作完成
 	PlayerPay(playerID string, chips int64) error                            // 玩家付籌碼
@@ -69,50 +83,83 @@ type TableEngine interface {
 	PlayerCheck(playerID string) error                                       // 玩家過牌
 	PlayerFold(playerID string) error                                        // 玩家棄牌
 	PlayerPass(playerID string) error                                        // 玩家 Pass
+	PlayerRunItMultiple(playerID string, times int) error                    // 全下玩家回覆同意的發牌次數 (1 表示不同意)
+	PlayerStraddle(playerID string) error                                    // 玩家 Straddle (現金桌發牌前)
 }
 
 type tableEngine struct {
-	lock                      sync.Mutex
-	options                   *TableEngineOptions
-	table                     *Table
-	game                      Game
-	gameBackend               GameBackend
-	rg                        *syncsaga.ReadyGroup
-	tbForOpenGame             *timebank.TimeBank
-	sm                        seat_manager.SeatManager
-	ogm                       open_game_manager.OpenGameManager
-	onTableUpdated            func(table *Table)
-	onTableErrorUpdated       func(table *Table, err error)
-	onTableStateUpdated       func(event string, table *Table)
-	onTablePlayerStateUpdated func(competitionID, tableID string, playerState *TablePlayerState)
-	onTablePlayerReserved     func(competitionID, tableID string, playerState *TablePlayerState)
-	onGamePlayerActionUpdated func(gameAction TablePlayerGameAction)
-	onAutoGameOpenEnd         func(competitionID, tableID string)
-	onReadyOpenFirstTableGame func(competitionID, tableID string, gameCount int, playerStates []*TablePlayerState)
-	isReleased                bool
+	lock                       sync.Mutex
+	options                    *TableEngineOptions
+	table                      *Table
+	game                       Game
+	gameBackend                GameBackend
+	rg                         *syncsaga.ReadyGroup
+	clock                      tableClock
+	tbForOpenGame              tableTimer
+	tbForAction                tableTimer
+	tbForPlayersAutoIn         tableTimer
+	tbForOpenGameReady         tableTimer
+	tbForGameReady             tableTimer
+	sm                         seat_manager.SeatManager
+	ogm                        open_game_manager.OpenGameManager
+	store                      TableStore
+	recorder                   *tableEventRecorder
+	rand                       *rand.Rand
+	equityRand                 *rand.Rand // 本手勝率計算的亂數來源
+	deckProvider               DeckProvider
+	patchEncoder               *TablePatchEncoder
+	bus                        TableEventBus
+	sharedBuses                []TableEventBus
+	onTableUpdated             func(table *Table)
+	onTableErrorUpdated        func(table *Table, err error)
+	onTableStateUpdated        func(event string, table *Table)
+	onTablePlayerStateUpdated  func(competitionID, tableID string, playerState *TablePlayerState)
+	onTablePlayerReserved      func(competitionID, tableID string, playerState *TablePlayerState)
+	onGamePlayerActionUpdated  func(gameAction TablePlayerGameAction)
+	onAutoGameOpenEnd          func(competitionID, tableID string)
+	onReadyOpenFirstTableGame  func(competitionID, tableID string, gameCount int, playerStates []*TablePlayerState)
+	onTablePlayerTimeBankUsed  func(competitionID, tableID string, playerState *TablePlayerState, duration int)
+	onTablePlayerActionTimeout func(competitionID, tableID string, playerState *TablePlayerState)
+	onGameSettled              func(result TableGameResult)
+	onTablePatched             func(patch TablePatch)
+	actionTimerSerial          int64
+	isPlayersAutoInWaiting     bool // 等待玩家入座中 (ReadyGroup 尚未完成)
+	isReleased                 bool
 }
 
 func NewTableEngine(options *TableEngineOptions, opts ...TableEngineOpt) TableEngine {
 	callbacks := NewTableEngineCallbacks()
 	te := &tableEngine{
-		options:                   options,
-		rg:                        syncsaga.NewReadyGroup(),
-		tbForOpenGame:             timebank.NewTimeBank(),
-		onTableUpdated:            callbacks.OnTableUpdated,
-		onTableErrorUpdated:       callbacks.OnTableErrorUpdated,
-		onTableStateUpdated:       callbacks.OnTableStateUpdated,
-		onTablePlayerStateUpdated: callbacks.OnTablePlayerStateUpdated,
-		onTablePlayerReserved:     callbacks.OnTablePlayerReserved,
-		onGamePlayerActionUpdated: callbacks.OnGamePlayerActionUpdated,
-		onAutoGameOpenEnd:         callbacks.OnAutoGameOpenEnd,
-		onReadyOpenFirstTableGame: callbacks.OnReadyOpenFirstTableGame,
-		isReleased:                false,
+		options:                    options,
+		rg:                         syncsaga.NewReadyGroup(),
+		bus:                        NewTableEventBus(),
+		clock:                      systemClock{},
+		onTableUpdated:             callbacks.OnTableUpdated,
+		onTableErrorUpdated:        callbacks.OnTableErrorUpdated,
+		onTableStateUpdated:        callbacks.OnTableStateUpdated,
+		onTablePlayerStateUpdated:  callbacks.OnTablePlayerStateUpdated,
+		onTablePlayerReserved:      callbacks.OnTablePlayerReserved,
+		onGamePlayerActionUpdated:  callbacks.OnGamePlayerActionUpdated,
+		onAutoGameOpenEnd:          callbacks.OnAutoGameOpenEnd,
+		onReadyOpenFirstTableGame:  callbacks.OnReadyOpenFirstTableGame,
+		onTablePlayerTimeBankUsed:  callbacks.OnTablePlayerTimeBankUsed,
+		onTablePlayerActionTimeout: callbacks.OnTablePlayerActionTimeout,
+		onGameSettled:              callbacks.OnGameSettled,
+		onTablePatched:             callbacks.OnTablePatched,
+		isReleased:                 false,
 	}
 
 	for _, opt := range opts {
 		opt(te)
 	}
 
+	// 計時器逾時時記錄 Timeout 指令 (玩家動作超時另外記錄為 PlayerActionTimeout)
+	te.tbForOpenGame = te.newRecordedTimer("OpenGame")
+	te.tbForAction = te.clock.NewTimer("Action")
+	te.tbForPlayersAutoIn = te.newRecordedTimer("PlayersAutoIn")
+	te.tbForOpenGameReady = te.newRecordedTimer("OpenGameReady")
+	te.tbForGameReady = te.newRecordedTimer("GameReady")
+
 	return te
 }
 
@@ -122,6 +169,53 @@ func WithGameBackend(gb GameBackend) TableEngineOpt {
 	}
 }
 
+// WithRandomSource 指定洗牌、隨機座位與隨機 Dealer 使用的亂數來源
+func WithRandomSource(src rand.Source) TableEngineOpt {
+	// 同一個 Option 套用到多個桌次時，共用同一把鎖
+	r := newLockedRand(src)
+	return func(te *tableEngine) {
+		te.rand = r
+	}
+}
+
+// WithDeckProvider 指定每手遊戲使用的牌組
+func WithDeckProvider(provider DeckProvider) TableEngineOpt {
+	return func(te *tableEngine) {
+		te.deckProvider = provider
+	}
+}
+
+func WithTableStore(store TableStore) TableEngineOpt {
+	return func(te *tableEngine) {
+		te.store = store
+	}
+}
+
+func WithEventLogger(logger TableEventLogger) TableEngineOpt {
+	return func(te *tableEngine) {
+		te.recorder = newTableEventRecorder(logger)
+	}
+}
+
+// WithTableEventBus 將桌次事件同時發布到指定的事件匯流排 (多個桌次共用時可跨桌訂閱)
+func WithTableEventBus(bus TableEventBus) TableEngineOpt {
+	return func(te *tableEngine) {
+		te.sharedBuses = append(te.sharedBuses, bus)
+	}
+}
+
+/*
+WithTablePatch 桌次更新時另外發出與上一次桌次的差異 (OnTablePatched)
+  - 差異為旁觀者視角 (ViewForObserver)，不含牌組與未攤牌玩家的底牌，可以直接廣播
+  - 玩家視角的差異由接收端以 NewPlayerTablePatchEncoder 各自產生
+*/
+func WithTablePatch() TableEngineOpt {
+	return func(te *tableEngine) {
+		// 每個桌次各自比對
+		te.patchEncoder = NewObserverTablePatchEncoder()
+	}
+}
+
 func (te *tableEngine) OnTableUpdated(fn func(*Table)) {
 	te.onTableUpdated = fn
 }
@@ -154,8 +248,32 @@ func (te *tableEngine) OnReadyOpenFirstTableGame(fn func(competitionID, tableID
 	te.onReadyOpenFirstTableGame = fn
 }
 
-func (te *tableEngine) ReleaseTable() error {
-	te.isReleased = true
+func (te *tableEngine) OnTablePlayerTimeBankUsed(fn func(competitionID, tableID string, playerState *TablePlayerState, duration int)) {
+	te.onTablePlayerTimeBankUsed = fn
+}
+
+func (te *tableEngine) OnTablePlayerActionTimeout(fn func(competitionID, tableID string, playerState *TablePlayerState)) {
+	te.onTablePlayerActionTimeout = fn
+}
+
+func (te *tableEngine) OnGameSettled(fn func(result TableGameResult)) {
+	te.onGameSettled = fn
+}
+
+func (te *tableEngine) OnTablePatched(fn func(patch TablePatch)) {
+	te.onTablePatched = fn
+}
+
+func (te *tableEngine) Subscribe(handler TableEventHandler, opts ...TableEventSubscribeOpt) TableEventSubscription {
+	return te.bus.Subscribe(handler, opts...)
+}
+
+func (te *tableEngine) ReleaseTable() (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("ReleaseTable", "", nil)(&err)
+
+	te.releaseTable()
 	return nil
 }
 
@@ -167,30 +285,44 @@ func (te *tableEngine) GetGame() Game {
 	return te.game
 }
 
-func (te *tableEngine) CreateTable(tableSetting TableSetting) (*Table, error) {
+func (te *tableEngine) CreateTable(tableSetting TableSetting) (_ *Table, err error) {
 	// validate tableSetting
 	if len(tableSetting.JoinPlayers) > tableSetting.Meta.TableMaxSeatCount {
 		return nil, ErrTableInvalidCreateSetting
 	}
 
+	if tableSetting.Meta.BettingStructure != "" && !funk.ContainsString(SupportedBettingStructures, tableSetting.Meta.BettingStructure) {
+		return nil, ErrTableInvalidCreateSetting
+	}
+
+	if tableSetting.Meta.Rake.Percent < 0 || tableSetting.Meta.Rake.Percent > 100 {
+		return nil, ErrTableInvalidCreateSetting
+	}
+
+	if tableSetting.Meta.MaxRunItTimes < 0 || tableSetting.Meta.MaxRunItTimes > MaxRunItTimes {
+		return nil, ErrTableInvalidCreateSetting
+	}
+
+	if !isValidAnteMode(tableSetting.Blind.AnteMode) {
+		return nil, ErrTableInvalidCreateSetting
+	}
+
+	if !isValidStraddleSetting(tableSetting.Meta) {
+		return nil, ErrTableInvalidCreateSetting
+	}
+
+	if !isValidBombPotSetting(tableSetting.Meta) {
+		return nil, ErrTableInvalidCreateSetting
+	}
+
+	// 有記錄事件時，固定桌次亂數來源的種子 (重播時可重現相同的座位與牌組)
+	randomSeed := te.newRandomSeed()
+
 	// init seat manager
-	te.sm = seat_manager.NewSeatManager(tableSetting.Meta.TableMaxSeatCount, tableSetting.Meta.Rule)
+	te.sm = seat_manager.NewSeatManager(tableSetting.Meta.TableMaxSeatCount, tableSetting.Meta.Rule, te.seatManagerOpts()...)
 
 	// init open game manager
-	te.ogm = open_game_manager.NewOpenGameManager(open_game_manager.OpenGameOption{
-		Timeout: 2,
-		OnOpenGameReady: func(state open_game_manager.OpenGameState) {
-			// 小於等於一個人，不開局
-			if len(state.Participants) <= 1 {
-				return
-			}
-
-			// 大於一個人，開局
-			if err := te.tableGameOpen(); err != nil {
-				te.emitErrorEvent("OnOpenGameReady#tableGameOpen", "", err)
-			}
-		},
-	})
+	te.ogm = open_game_manager.NewOpenGameManager(te.newOpenGameOption())
 
 	// create table instance
 	table := &Table{
@@ -215,11 +347,13 @@ func (te *tableEngine) CreateTable(tableSetting TableSetting) (*Table, error) {
 		SeatMap:              NewDefaultSeatMap(tableSetting.Meta.TableMaxSeatCount),
 		PlayerStates:         make([]*TablePlayerState, 0),
 		GamePlayerIndexes:    make([]int, 0),
+		GameBlindPosts:       make(map[int]GameBlindPost),
 		Status:               status,
 		NextBBOrderPlayerIDs: make([]string, 0),
 	}
 	table.State = &state
 	te.table = table
+	defer te.recordUnlockedCommand("CreateTable", "", te.newTableEventLogTableArgs(&tableSetting, nil, randomSeed))(&err)
 
 	te.emitEvent("CreateTable", "")
 	te.emitTableStateEvent(TableStateEvent_Created)
@@ -246,9 +380,15 @@ func (te *tableEngine) CreateTable(tableSetting TableSetting) (*Table, error) {
 PauseTable 暫停桌
   - 適用時機: 外部暫停自動開桌
 */
-func (te *tableEngine) PauseTable() error {
+func (te *tableEngine) PauseTable() (err error) {
+	defer te.recordUnlockedCommand("PauseTable", "", nil)(&err)
+
+	te.lock.Lock()
 	te.table.State.Status = TableStateStatus_TablePausing
-	te.emitTableStateEvent(TableStateEvent_StatusUpdated)
+	table := te.cloneTable()
+	te.lock.Unlock()
+
+	te.publishTableStateEvent(TableStateEvent_StatusUpdated, table)
 	return nil
 }
 
@@ -256,32 +396,36 @@ func (te *tableEngine) PauseTable() error {
 CloseTable 關閉桌次
   - 適用時機: 強制關閉、逾期自動關閉、正常關閉
 */
-func (te *tableEngine) CloseTable() error {
+func (te *tableEngine) CloseTable() (err error) {
+	defer te.recordUnlockedCommand("CloseTable", "", nil)(&err)
+
+	te.lock.Lock()
 	te.table.State.Status = TableStateStatus_TableClosed
-	te.ReleaseTable()
+	te.releaseTable()
+	update := te.refreshTable("CloseTable", "")
+	te.lock.Unlock()
 
-	te.emitEvent("CloseTable", "")
-	te.emitTableStateEvent(TableStateEvent_StatusUpdated)
+	te.publishTableUpdate(update)
+	te.publishTableStateEvent(TableStateEvent_StatusUpdated, update.table)
 	return nil
 }
 
-func (te *tableEngine) StartTableGame() error {
-	if te.table.State.StartAt != UnsetValue {
-		fmt.Println("[DEBUG#StartTableGame] Table game is already started.")
-		return nil
-	}
-
-	// 更新開始時間
-	te.table.State.StartAt = time.Now().Unix()
-	te.emitEvent("StartTableGame", "")
-
-	//  開局
-	te.emitReadyOpenFirstTableGame(te.table.State.GameCount, te.table.State.PlayerStates)
-	return nil
+func (te *tableEngine) StartTableGame() (err error) {
+	defer te.recordUnlockedCommand("StartTableGame", "", nil)(&err)
 
+	return te.startTableGame()
 }
 
 func (te *tableEngine) UpdateBlind(level int, ante, dealer, sb, bb int64) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("UpdateBlind", "", TableBlindState{Level: level, Ante: ante, Dealer: dealer, SB: sb, BB: bb})(nil)
+
+	// 盲注升級時補充玩家時間銀行
+	if level > te.table.State.BlindState.Level {
+		te.topUpTimeBank()
+	}
+
 	te.table.State.BlindState.Level = level
 	te.table.State.BlindState.Ante = ante
 	te.table.State.BlindState.Dealer = dealer
@@ -289,6 +433,25 @@ func (te *tableEngine) UpdateBlind(level int, ante, dealer, sb, bb int64) {
 	te.table.State.BlindState.BB = bb
 }
 
+/*
+UpdateAnteMode 更新前注模式
+  - 空值或 AnteMode_PerPlayer: 每位玩家各自支付前注
+  - AnteMode_BigBlind / AnteMode_Button: 由大盲或按鈕位代付全桌前注
+  - 下一手開始生效
+*/
+func (te *tableEngine) UpdateAnteMode(mode string) (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("UpdateAnteMode", "", map[string]interface{}{"mode": mode})(&err)
+
+	if !isValidAnteMode(mode) {
+		return ErrTableInvalidAnteMode
+	}
+
+	te.table.State.BlindState.AnteMode = mode
+	return nil
+}
+
 /*
 SetUpTableGame 設定某手遊戲
   - 適用時機:
@@ -296,6 +459,8 @@ SetUpTableGame 設定某手遊戲
     2. 每手結束，在 Continue 階段，準備開下一手
 */
 func (te *tableEngine) SetUpTableGame(gameCount int, participants map[string]int) {
+	defer te.recordUnlockedCommand("SetUpTableGame", "", map[string]interface{}{"game_count": gameCount, "participants": participants})(nil)
+
 	te.ogm.Setup(gameCount, participants)
 }
 
@@ -303,9 +468,10 @@ func (te *tableEngine) SetUpTableGame(gameCount int, participants map[string]int
 UpdateTablePlayers 更新桌上玩家數量
   - 適用時機: 每手遊戲結束後
 */
-func (te *tableEngine) UpdateTablePlayers(joinPlayers []JoinPlayer, leavePlayerIDs []string) (map[string]int, error) {
+func (te *tableEngine) UpdateTablePlayers(joinPlayers []JoinPlayer, leavePlayerIDs []string) (playerIdxes map[string]int, err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("UpdateTablePlayers", "", map[string]interface{}{"join_players": joinPlayers, "leave_player_ids": leavePlayerIDs})(&err)
 
 	// remove players
 	if len(leavePlayerIDs) > 0 {
@@ -335,9 +501,10 @@ func (te *tableEngine) UpdateTablePlayers(joinPlayers []JoinPlayer, leavePlayerI
 PlayerReserve 玩家確認座位
   - 適用時機: 玩家帶籌碼報名或補碼
 */
-func (te *tableEngine) PlayerReserve(joinPlayer JoinPlayer) error {
+func (te *tableEngine) PlayerReserve(joinPlayer JoinPlayer) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerReserve", joinPlayer.PlayerID, joinPlayer)(&err)
 
 	// find player index in PlayerStates
 	targetPlayerIdx := te.table.FindPlayerIdx(joinPlayer.PlayerID)
@@ -372,52 +539,62 @@ func (te *tableEngine) PlayerReserve(joinPlayer JoinPlayer) error {
 PlayerJoin 玩家入桌
   - 適用時機: 玩家已經確認座位後入桌
 */
-func (te *tableEngine) PlayerJoin(playerID string) error {
+func (te *tableEngine) PlayerJoin(playerID string) (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerJoin", playerID, nil)(&err)
+
+	return te.playerJoin(playerID)
+}
+
+/*
+PlayerSettlementFinish 玩家結算完成
+  - 適用時機: 玩家已經看完結算動畫
+*/
+func (te *tableEngine) PlayerSettlementFinish(playerID string) (err error) {
+	defer te.recordUnlockedCommand("PlayerSettlementFinish", playerID, nil)(&err)
+
 	playerIdx := te.table.FindPlayerIdx(playerID)
 	if playerIdx == UnsetValue {
 		return ErrTablePlayerNotFound
 	}
 
-	if te.table.State.PlayerStates[playerIdx].Seat == UnsetValue {
+	if !te.table.State.PlayerStates[playerIdx].IsIn {
 		return ErrTablePlayerInvalidAction
 	}
 
-	if te.table.State.PlayerStates[playerIdx].IsIn {
-		return nil
-	}
-
-	te.table.State.PlayerStates[playerIdx].IsIn = true
-
-	// 有設定 ReadyGroup，且玩家尚未 Ready 時，則 Ready
-	if isReady, exist := te.rg.GetParticipantStates()[int64(playerIdx)]; exist && !isReady {
-		te.rg.Ready(int64(playerIdx))
-	}
-
-	// 更新 seat manager
-	if err := te.sm.JoinPlayers([]string{playerID}); err != nil {
-		return err
-	}
+	te.ogm.Ready(playerID)
 
-	te.emitEvent("PlayerJoin", playerID)
 	return nil
 }
 
 /*
-PlayerSettlementFinish 玩家結算完成
-  - 適用時機: 玩家已經看完結算動畫
+PlayerVoteBombPot 玩家投票同意下一手進行 Bomb Pot
+  - 適用時機: 開局前 (SetUpTableGame 之後，開局之前)
+  - 本手參與的玩家皆同意才會進行 Bomb Pot
 */
-func (te *tableEngine) PlayerSettlementFinish(playerID string) error {
-	playerIdx := te.table.FindPlayerIdx(playerID)
-	if playerIdx == UnsetValue {
+func (te *tableEngine) PlayerVoteBombPot(playerID string) (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerVoteBombPot", playerID, nil)(&err)
+
+	if !te.table.Meta.BombPot.VoteEnabled || !isValidBombPotSetting(te.table.Meta) {
+		return ErrTableBombPotNotAllowed
+	}
+
+	if te.table.FindPlayerIdx(playerID) == UnsetValue {
 		return ErrTablePlayerNotFound
 	}
 
-	if !te.table.State.PlayerStates[playerIdx].IsIn {
-		return ErrTablePlayerInvalidAction
+	if !te.isBombPotVoteOpen() {
+		return ErrTableBombPotVoteClosed
 	}
 
-	te.ogm.Ready(playerID)
+	if err := te.ogm.VoteBombPot(playerID); err != nil {
+		return err
+	}
 
+	te.emitEvent("PlayerVoteBombPot", playerID)
 	return nil
 }
 
@@ -425,7 +602,9 @@ func (te *tableEngine) PlayerSettlementFinish(playerID string) error {
 PlayerRedeemChips 增購籌碼
   - 適用時機: 增購
 */
-func (te *tableEngine) PlayerRedeemChips(joinPlayer JoinPlayer) error {
+func (te *tableEngine) PlayerRedeemChips(joinPlayer JoinPlayer) (err error) {
+	defer te.recordUnlockedCommand("PlayerRedeemChips", joinPlayer.PlayerID, joinPlayer)(&err)
+
 	// find player index in PlayerStates
 	playerIdx := te.table.FindPlayerIdx(joinPlayer.PlayerID)
 	if playerIdx == UnsetValue {
@@ -447,9 +626,10 @@ PlayerLeave 玩家們離開桌次
   - CT 放棄補碼 (玩家沒有籌碼)
   - CT 停止買入後被淘汰
 */
-func (te *tableEngine) PlayersLeave(playerIDs []string) error {
+func (te *tableEngine) PlayersLeave(playerIDs []string) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayersLeave", "", map[string]interface{}{"player_ids": playerIDs})(&err)
 
 	if err := te.batchRemovePlayers(playerIDs); err != nil {
 		return err
@@ -461,21 +641,133 @@ func (te *tableEngine) PlayersLeave(playerIDs []string) error {
 	return nil
 }
 
+/*
+PlayerSitOut 玩家暫離
+  - 適用時機: 玩家保留座位與籌碼，暫時不參與牌局
+  - 現金桌/CT: 從下一手開始不再發牌給玩家，輪轉位置時跳過該玩家
+  - MTT: 依然參與牌局，輪到玩家時由桌次引擎自動過牌或棄牌 (需開啟 TableMeta.ActionTimeoutEnforced)
+*/
+func (te *tableEngine) PlayerSitOut(playerID string) (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerSitOut", playerID, nil)(&err)
+
+	playerIdx := te.table.FindPlayerIdx(playerID)
+	if playerIdx == UnsetValue {
+		return ErrTablePlayerNotFound
+	}
+
+	playerState := te.table.State.PlayerStates[playerIdx]
+	if playerState.IsSitOut {
+		return nil
+	}
+
+	if err := te.sitOutPlayer(playerState); err != nil {
+		return err
+	}
+
+	te.emitEvent("PlayerSitOut", playerID)
+	te.emitTablePlayerStateEvent(playerState)
+	return nil
+}
+
+/*
+PlayerSitIn 玩家回座
+  - 適用時機: 暫離玩家回到牌局
+  - 現金桌: 暫離期間錯過盲注的玩家，需等待輪到大盲才能回到牌局；開啟 TableMeta.PostDeadBlinds 時則於下一手補盲注回到牌局
+  - CT/MTT: 下一手直接回到牌局
+*/
+func (te *tableEngine) PlayerSitIn(playerID string) (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerSitIn", playerID, nil)(&err)
+
+	playerIdx := te.table.FindPlayerIdx(playerID)
+	if playerIdx == UnsetValue {
+		return ErrTablePlayerNotFound
+	}
+
+	playerState := te.table.State.PlayerStates[playerIdx]
+	if !playerState.IsSitOut {
+		return nil
+	}
+
+	if te.isSeatSitOutEnabled() {
+		// 現金桌錯過盲注時，依設定等待大盲或於下一手補盲注
+		waitForBB := te.table.Meta.Mode == CompetitionMode_Cash && !te.table.Meta.PostDeadBlinds
+		if err := te.sm.SitInPlayer(playerID, waitForBB); err != nil {
+			return err
+		}
+
+		if te.table.Meta.Mode != CompetitionMode_Cash {
+			if err := te.sm.ClearMissedBlinds(playerID); err != nil {
+				return err
+			}
+			playerState.MissedSB = false
+			playerState.MissedBB = false
+		}
+	}
+	playerState.IsSitOut = false
+	playerState.ActionTimeoutCount = 0
+
+	te.emitEvent("PlayerSitIn", playerID)
+	te.emitTablePlayerStateEvent(playerState)
+	return nil
+}
+
 /*
 PlayerExtendActionDeadline 延長玩家動作結束時間
   - 適用時機: 當玩家動作時間計時器開始時
+  - 有設定時間銀行時，只有當前動作玩家可以延長，並從玩家時間銀行扣除 (不足時只延長剩餘秒數)
 */
-func (te *tableEngine) PlayerExtendActionDeadline(playerID string, duration int) (int64, error) {
-	endAt := time.Unix(te.table.State.CurrentActionEndAt, 0)
-	currentActionEndAt := endAt.Add(time.Duration(duration) * time.Second).Unix()
-	te.table.State.CurrentActionEndAt = currentActionEndAt
-	te.emitEvent("PlayerExtendActionDeadline", "")
+func (te *tableEngine) PlayerExtendActionDeadline(playerID string, duration int) (_ int64, err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerExtendActionDeadline", playerID, map[string]interface{}{"duration": duration})(&err)
+
+	// 未設定時間銀行時不限制延長秒數
+	if !te.isTimeBankEnabled() {
+		currentActionEndAt := te.extendCurrentActionEndAt(duration)
+		te.emitEvent("PlayerExtendActionDeadline", "")
+		return currentActionEndAt, nil
+	}
+
+	if duration <= 0 {
+		return -1, ErrTablePlayerInvalidAction
+	}
+
+	playerIdx := te.table.FindPlayerIdx(playerID)
+	if playerIdx == UnsetValue {
+		return -1, ErrTablePlayerNotFound
+	}
+
+	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
+	gs := te.table.State.GameState
+	if gamePlayerIdx == UnsetValue || gs == nil || gs.Status.CurrentPlayer != gamePlayerIdx {
+		return -1, ErrTablePlayerNotCurrentActor
+	}
+
+	playerState := te.table.State.PlayerStates[playerIdx]
+	if playerState.TimeBank <= 0 {
+		return -1, ErrTablePlayerTimeBankExhausted
+	}
+
+	if duration > playerState.TimeBank {
+		duration = playerState.TimeBank
+	}
+	playerState.TimeBank -= duration
+
+	currentActionEndAt := te.extendCurrentActionEndAt(duration)
+	te.emitTablePlayerTimeBankUsedEvent(playerState, duration)
+	te.emitTablePlayerStateEvent(playerState)
+	te.emitEvent("PlayerExtendActionDeadline", playerID)
 	return currentActionEndAt, nil
 }
 
-func (te *tableEngine) PlayerReady(playerID string) error {
+func (te *tableEngine) PlayerReady(playerID string) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerReady", playerID, nil)(&err)
 
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
@@ -495,9 +787,10 @@ func (te *tableEngine) PlayerReady(playerID string) error {
 	return err
 }
 
-func (te *tableEngine) PlayerPay(playerID string, chips int64) error {
+func (te *tableEngine) PlayerPay(playerID string, chips int64) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerPay", playerID, map[string]interface{}{"chips": chips})(&err)
 
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
@@ -517,9 +810,10 @@ func (te *tableEngine) PlayerPay(playerID string, chips int64) error {
 	return err
 }
 
-func (te *tableEngine) PlayerBet(playerID string, chips int64) error {
+func (te *tableEngine) PlayerBet(playerID string, chips int64) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerBet", playerID, map[string]interface{}{"chips": chips})(&err)
 
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
@@ -531,6 +825,11 @@ func (te *tableEngine) PlayerBet(playerID string, chips int64) error {
 		return ErrGamePlayerNotFound
 	}
 
+	chips = te.fixedLimitWager(gamePlayerIdx, WagerAction_Bet, chips)
+	if err := te.validateWager(gamePlayerIdx, WagerAction_Bet, chips); err != nil {
+		return err
+	}
+
 	gs, err := te.game.Bet(gamePlayerIdx, chips)
 	if err == nil {
 		te.table.State.LastPlayerGameAction = te.createPlayerGameAction(playerID, playerIdx, WagerAction_Bet, chips, gs.GetPlayer(gamePlayerIdx))
@@ -538,6 +837,7 @@ func (te *tableEngine) PlayerBet(playerID string, chips int64) error {
 
 		playerState := te.table.State.PlayerStates[playerIdx]
 		playerState.GameStatistics.ActionTimes++
+		playerState.ActionTimeoutCount = 0
 		if te.game.GetGameState().Status.CurrentRaiser == gamePlayerIdx {
 			playerState.GameStatistics.RaiseTimes++
 		}
@@ -554,9 +854,10 @@ func (te *tableEngine) PlayerBet(playerID string, chips int64) error {
 	return err
 }
 
-func (te *tableEngine) PlayerRaise(playerID string, chipLevel int64) error {
+func (te *tableEngine) PlayerRaise(playerID string, chipLevel int64) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerRaise", playerID, map[string]interface{}{"chip_level": chipLevel})(&err)
 
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
@@ -568,6 +869,11 @@ func (te *tableEngine) PlayerRaise(playerID string, chipLevel int64) error {
 		return ErrGamePlayerNotFound
 	}
 
+	chipLevel = te.fixedLimitWager(gamePlayerIdx, WagerAction_Raise, chipLevel)
+	if err := te.validateWager(gamePlayerIdx, WagerAction_Raise, chipLevel); err != nil {
+		return err
+	}
+
 	gs, err := te.game.Raise(gamePlayerIdx, chipLevel)
 	if err == nil {
 		playerState := te.table.State.PlayerStates[playerIdx]
@@ -575,6 +881,7 @@ func (te *tableEngine) PlayerRaise(playerID string, chipLevel int64) error {
 		te.emitGamePlayerActionEvent(*te.table.State.LastPlayerGameAction)
 
 		playerState.GameStatistics.ActionTimes++
+		playerState.ActionTimeoutCount = 0
 		playerState.GameStatistics.RaiseTimes++
 
 		if playerState.GameStatistics.IsVPIPChance {
@@ -603,9 +910,10 @@ func (te *tableEngine) PlayerRaise(playerID string, chipLevel int64) error {
 	return err
 }
 
-func (te *tableEngine) PlayerCall(playerID string) error {
+func (te *tableEngine) PlayerCall(playerID string) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerCall", playerID, nil)(&err)
 
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
@@ -629,6 +937,7 @@ func (te *tableEngine) PlayerCall(playerID string) error {
 
 		playerState := te.table.State.PlayerStates[playerIdx]
 		playerState.GameStatistics.ActionTimes++
+		playerState.ActionTimeoutCount = 0
 		playerState.GameStatistics.CallTimes++
 
 		if playerState.GameStatistics.IsVPIPChance {
@@ -639,9 +948,10 @@ func (te *tableEngine) PlayerCall(playerID string) error {
 	return err
 }
 
-func (te *tableEngine) PlayerAllin(playerID string) error {
+func (te *tableEngine) PlayerAllin(playerID string) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerAllin", playerID, nil)(&err)
 
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
@@ -658,6 +968,10 @@ func (te *tableEngine) PlayerAllin(playerID string) error {
 		wager = te.table.State.GameState.GetPlayer(gamePlayerIdx).StackSize
 	}
 
+	if err := te.validateWager(gamePlayerIdx, WagerAction_AllIn, 0); err != nil {
+		return err
+	}
+
 	gs, err := te.game.Allin(gamePlayerIdx)
 	if err == nil {
 		te.table.State.LastPlayerGameAction = te.createPlayerGameAction(playerID, playerIdx, WagerAction_AllIn, wager, gs.GetPlayer(gamePlayerIdx))
@@ -665,6 +979,7 @@ func (te *tableEngine) PlayerAllin(playerID string) error {
 
 		playerState := te.table.State.PlayerStates[playerIdx]
 		playerState.GameStatistics.ActionTimes++
+		playerState.ActionTimeoutCount = 0
 		if te.game.GetGameState().Status.CurrentRaiser == gamePlayerIdx {
 			playerState.GameStatistics.RaiseTimes++
 			if playerState.GameStatistics.IsPFRChance {
@@ -694,10 +1009,16 @@ func (te *tableEngine) PlayerAllin(playerID string) error {
 	return err
 }
 
-func (te *tableEngine) PlayerCheck(playerID string) error {
+func (te *tableEngine) PlayerCheck(playerID string) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerCheck", playerID, nil)(&err)
+
+	return te.playerCheck(playerID)
+}
 
+// playerCheck 玩家過牌 (需持有 te.lock)
+func (te *tableEngine) playerCheck(playerID string) error {
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
 		return err
@@ -715,16 +1036,23 @@ func (te *tableEngine) PlayerCheck(playerID string) error {
 
 		playerState := te.table.State.PlayerStates[playerIdx]
 		playerState.GameStatistics.ActionTimes++
+		playerState.ActionTimeoutCount = 0
 		playerState.GameStatistics.CheckTimes++
 	}
 
 	return err
 }
 
-func (te *tableEngine) PlayerFold(playerID string) error {
+func (te *tableEngine) PlayerFold(playerID string) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerFold", playerID, nil)(&err)
 
+	return te.playerFold(playerID)
+}
+
+// playerFold 玩家棄牌 (需持有 te.lock)
+func (te *tableEngine) playerFold(playerID string) error {
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
 		return err
@@ -742,6 +1070,7 @@ func (te *tableEngine) PlayerFold(playerID string) error {
 
 		playerState := te.table.State.PlayerStates[playerIdx]
 		playerState.GameStatistics.ActionTimes++
+		playerState.ActionTimeoutCount = 0
 		playerState.GameStatistics.IsFold = true
 		playerState.GameStatistics.FoldRound = te.game.GetGameState().Status.Round
 
@@ -757,9 +1086,10 @@ func (te *tableEngine) PlayerFold(playerID string) error {
 	return err
 }
 
-func (te *tableEngine) PlayerPass(playerID string) error {
+func (te *tableEngine) PlayerPass(playerID string) (err error) {
 	te.lock.Lock()
 	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerPass", playerID, nil)(&err)
 
 	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
 	if err := te.validateGameMove(gamePlayerIdx); err != nil {
@@ -779,3 +1109,91 @@ func (te *tableEngine) PlayerPass(playerID string) error {
 
 	return err
 }
+
+/*
+PlayerRunItMultiple 全下玩家回覆同意的發牌次數
+  - 適用時機: 所有玩家全下後，玩家可執行 Action_RunItMultiple 時
+  - times 為 1 表示不同意，最多為 Meta.MaxRunItTimes
+  - 所有全下玩家都同意才會多次發牌 (次數取玩家同意的最小值)，任一玩家不同意或逾時只發一次牌
+*/
+func (te *tableEngine) PlayerRunItMultiple(playerID string, times int) (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerRunItMultiple", playerID, map[string]interface{}{"times": times})(&err)
+
+	if times < 1 || times > te.table.Meta.MaxRunItTimes {
+		return ErrTablePlayerInvalidGameAction
+	}
+
+	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
+	if err := te.validateGameMove(gamePlayerIdx); err != nil {
+		return err
+	}
+
+	playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gamePlayerIdx)
+	if playerIdx == UnsetValue {
+		return ErrGamePlayerNotFound
+	}
+
+	runout := te.table.State.GameRunout
+	if runout == nil || !te.game.GetGameState().HasAction(gamePlayerIdx, Action_RunItMultiple) {
+		return ErrTablePlayerInvalidGameAction
+	}
+
+	agree := times > 1
+	gs, err := te.game.RunItMultiple(gamePlayerIdx, agree)
+	if err == nil {
+		// 持有 te.lock，結算 (settleRunouts) 必定在記錄同意之後
+		if agree {
+			runout.AgreedTimes[playerID] = times
+		}
+
+		te.table.State.LastPlayerGameAction = te.createPlayerGameAction(playerID, playerIdx, Action_RunItMultiple, 0, gs.GetPlayer(gamePlayerIdx))
+		te.emitGamePlayerActionEvent(*te.table.State.LastPlayerGameAction)
+	}
+
+	return err
+}
+
+/*
+PlayerStraddle 玩家 Straddle
+  - 適用時機: 現金桌開局後、發牌前 (玩家 Ready 之前)
+  - Straddle 籌碼量為本手大盲的兩倍，於盲注支付後計入本輪下注
+  - 翻牌前由 Straddle 玩家的下一位開始行動，Straddle 玩家最後行動
+*/
+func (te *tableEngine) PlayerStraddle(playerID string) (err error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+	defer te.recordCommand("PlayerStraddle", playerID, nil)(&err)
+
+	gamePlayerIdx := te.table.FindGamePlayerIdx(playerID)
+	if err := te.validateGameMove(gamePlayerIdx); err != nil {
+		return err
+	}
+
+	playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gamePlayerIdx)
+	if playerIdx == UnsetValue {
+		return ErrGamePlayerNotFound
+	}
+
+	chips := te.table.State.GameBlindState.BB * 2
+	if err := te.validateStraddle(gamePlayerIdx, chips); err != nil {
+		return err
+	}
+
+	gs, err := te.game.Straddle(gamePlayerIdx, chips)
+	if err != nil {
+		return err
+	}
+
+	post := te.table.State.GameBlindPosts[gamePlayerIdx]
+	post.Straddle = chips
+	te.table.State.GameBlindPosts[gamePlayerIdx] = post
+
+	player := te.table.State.PlayerStates[playerIdx]
+	player.Positions = append(player.Positions, Position_Straddle)
+
+	te.table.State.LastPlayerGameAction = te.createPlayerGameAction(playerID, playerIdx, Action_Straddle, chips, gs.GetPlayer(gamePlayerIdx))
+	te.emitEvent("PlayerStraddle", playerID)
+	return nil
+}
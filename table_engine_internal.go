@@ -1,11 +1,14 @@
 package pokertable
 
 import (
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/thoas/go-funk"
 	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable/open_game_manager"
 	"github.com/weedbox/pokertable/seat_manager"
 	"github.com/weedbox/syncsaga"
 )
@@ -43,14 +46,13 @@ func (te *tableEngine) delay(interval int, fn func() error) error {
 	return err
 }
 
+/*
+updateGameState 遊戲狀態更新
+  - 適用時機: 遊戲引擎的狀態更新 goroutine
+  - 更新桌次時持有 te.lock (與桌次動作、快照互斥)，發出事件時不持有 (監聽器可以直接執行玩家動作)
+*/
 func (te *tableEngine) updateGameState(gs *pokerface.GameState) {
-	te.table.State.GameState = gs
-
-	if te.table.State.Status == TableStateStatus_TableGamePlaying {
-		te.updateCurrentPlayerGameStatistics(gs)
-	}
-
-	event, ok := pokerface.GameEventBySymbol[gs.Status.CurrentEvent]
+	event, update, ok := te.applyGameState(gs)
 	if !ok {
 		te.emitErrorEvent("handle updateGameState", "", ErrGameUnknownEvent)
 		return
@@ -62,17 +64,55 @@ func (te *tableEngine) updateGameState(gs *pokerface.GameState) {
 			te.emitErrorEvent("onGameClosed", "", err)
 		}
 	default:
-		te.updateCurrentActionEndAt(event, gs)
-		te.emitEvent(gs.Status.CurrentEvent, "")
-		te.emitTableStateEvent(TableStateEvent_GameUpdated)
+		te.publishTableUpdate(update)
+		te.publishTableStateEvent(TableStateEvent_GameUpdated, update.table)
 		if event == pokerface.GameEvent_RoundClosed {
+			te.lock.Lock()
 			te.table.State.LastPlayerGameAction = nil
+			te.table.State.GameRoundBetCount = 0
+			te.lock.Unlock()
 		}
 	}
 }
 
+// applyGameState 將遊戲狀態更新到桌次 (持有 te.lock)，回傳待發出的桌次更新 (本手結束時由結算發出)
+func (te *tableEngine) applyGameState(gs *pokerface.GameState) (pokerface.GameEvent, *tableUpdate, bool) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+
+	te.recordGameState(gs)
+	prev := te.table.State.GameState
+	te.table.State.GameState = gs
+
+	if te.table.State.Status == TableStateStatus_TableGamePlaying {
+		te.updateCurrentPlayerGameStatistics(gs)
+	}
+
+	event, ok := pokerface.GameEventBySymbol[gs.Status.CurrentEvent]
+	if !ok || event == pokerface.GameEvent_GameClosed {
+		return event, nil, ok
+	}
+
+	te.countRoundBet(prev, gs)
+	te.updateCurrentActionEndAt(event, gs)
+	te.refreshWagerLimit(event, gs)
+	te.refreshActionTimer(event, gs)
+	return event, te.refreshTable(gs.Status.CurrentEvent, ""), true
+}
+
 func (te *tableEngine) updateCurrentActionEndAt(event pokerface.GameEvent, gs *pokerface.GameState) {
+	if te.isWagerActionRequired(event, gs) {
+		te.table.State.CurrentActionEndAt = te.clock.Now().Add(time.Second * time.Duration(te.table.Meta.ActionTime)).Unix()
+	}
+}
+
+// isWagerActionRequired 是否正在等待當前玩家做下注動作
+func (te *tableEngine) isWagerActionRequired(event pokerface.GameEvent, gs *pokerface.GameState) bool {
 	p := gs.GetPlayer(gs.Status.CurrentPlayer)
+	if p == nil {
+		return false
+	}
+
 	validRounds := []string{GameRound_Preflop, GameRound_Flop, GameRound_Turn, GameRound_River}
 	validRoundState := te.table.State.Status == TableStateStatus_TableGamePlaying && event == pokerface.GameEvent_RoundStarted && funk.Contains(validRounds, gs.Status.Round)
 	validActions := []string{WagerAction_Call, WagerAction_Raise, WagerAction_AllIn, WagerAction_Check, WagerAction_Fold, WagerAction_Bet}
@@ -86,9 +126,155 @@ func (te *tableEngine) updateCurrentActionEndAt(event pokerface.GameEvent, gs *p
 	}
 
 	playerUnmoved := len(p.AllowedActions) > 0 && !p.Acted
-	if validRoundState && playerUnmoved && isActionValid {
-		te.table.State.CurrentActionEndAt = time.Now().Add(time.Second * time.Duration(te.table.Meta.ActionTime)).Unix()
+	return validRoundState && playerUnmoved && isActionValid
+}
+
+func (te *tableEngine) newOpenGameOption() open_game_manager.OpenGameOption {
+	return open_game_manager.OpenGameOption{
+		Timeout: 2,
+		Timer:   te.tbForOpenGameReady,
+		OnOpenGameReady: func(state open_game_manager.OpenGameState) {
+			// 小於等於一個人，不開局
+			if len(state.Participants) <= 1 {
+				return
+			}
+
+			// 大於一個人，開局
+			if err := te.tableGameOpen(); err != nil {
+				te.emitErrorEvent("OnOpenGameReady#tableGameOpen", "", err)
+			}
+		},
+	}
+}
+
+func (te *tableEngine) persistTable() {
+	if te.store == nil || te.isReleased {
+		return
+	}
+
+	snapshot, err := te.snapshot()
+	if err != nil {
+		te.emitErrorEvent("persistTable#GetSnapshot", "", err)
+		return
+	}
+
+	if err := te.store.Save(snapshot); err != nil {
+		te.emitErrorEvent("persistTable#Save", "", err)
+	}
+}
+
+/*
+recordCommand 記錄桌次指令與執行結果
+  - 需持有 te.lock: 指令序號於此時決定，與指令實際執行的順序一致
+  - 回傳的函式於指令結束時呼叫 (defer)，將執行結果一併寫入紀錄
+*/
+func (te *tableEngine) recordCommand(name, playerID string, args interface{}) func(*error) {
+	if te.recorder == nil || te.table == nil {
+		return func(*error) {}
+	}
+
+	record, err := te.recorder.newCommand(te.table, name, playerID, args)
+	if err != nil {
+		te.emitErrorEvent("recordCommand", playerID, err)
+		return func(*error) {}
+	}
+
+	return func(cmdErr *error) {
+		if cmdErr != nil && *cmdErr != nil {
+			record.Error = (*cmdErr).Error()
+		}
+
+		if err := te.recorder.recordCommand(record); err != nil {
+			te.emitErrorEvent("recordCommand", playerID, err)
+		}
+	}
+}
+
+// recordUnlockedCommand 記錄不需全程持有 te.lock 的指令 (執行中會觸發外部監聽器)，只在決定指令序號時取得 te.lock
+func (te *tableEngine) recordUnlockedCommand(name, playerID string, args interface{}) func(*error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+
+	return te.recordCommand(name, playerID, args)
+}
+
+func (te *tableEngine) recordGameState(gs *pokerface.GameState) {
+	if te.recorder == nil {
+		return
+	}
+
+	if err := te.recorder.recordGameState(te.table, gs); err != nil {
+		te.emitErrorEvent("recordGameState", "", err)
+	}
+}
+
+func (te *tableEngine) recordTable(eventName, playerID string) {
+	if te.recorder == nil {
+		return
+	}
+
+	if err := te.recorder.recordTable(te.table, eventName, playerID); err != nil {
+		te.emitErrorEvent("recordTable", playerID, err)
+	}
+}
+
+// releaseTable 釋放桌次 (需持有 te.lock)
+func (te *tableEngine) releaseTable() {
+	te.isReleased = true
+	atomic.AddInt64(&te.actionTimerSerial, 1)
+	te.tbForAction.Cancel()
+	te.bus.Close()
+}
+
+// startTableGame 開始桌次 (只在更新桌次時持有 te.lock，監聽者會同步設定開局)
+func (te *tableEngine) startTableGame() error {
+	te.lock.Lock()
+	if te.table.State.StartAt != UnsetValue {
+		te.lock.Unlock()
+		fmt.Println("[DEBUG#StartTableGame] Table game is already started.")
+		return nil
+	}
+
+	// 更新開始時間
+	te.table.State.StartAt = te.clock.Now().Unix()
+	update := te.refreshTable("StartTableGame", "")
+	te.lock.Unlock()
+	te.publishTableUpdate(update)
+
+	//  開局
+	te.emitReadyOpenFirstTableGame(update.table.State.GameCount, update.table.State.PlayerStates)
+	return nil
+}
+
+// playerJoin 玩家入桌 (需持有 te.lock)
+func (te *tableEngine) playerJoin(playerID string) error {
+	playerIdx := te.table.FindPlayerIdx(playerID)
+	if playerIdx == UnsetValue {
+		return ErrTablePlayerNotFound
+	}
+
+	if te.table.State.PlayerStates[playerIdx].Seat == UnsetValue {
+		return ErrTablePlayerInvalidAction
+	}
+
+	if te.table.State.PlayerStates[playerIdx].IsIn {
+		return nil
+	}
+
+	te.table.State.PlayerStates[playerIdx].IsIn = true
+
+	// 有設定 ReadyGroup，且玩家尚未 Ready 時，則 Ready
+	if isReady, exist := te.rg.GetParticipantStates()[int64(playerIdx)]; exist && !isReady {
+		te.rg.Ready(int64(playerIdx))
+	}
+
+	// 更新 seat manager
+	if err := te.sm.JoinPlayers([]string{playerID}); err != nil {
+		return err
 	}
+
+	te.emitEvent("PlayerJoin", playerID)
+	return nil
 }
 
 func (te *tableEngine) shouldAutoGameOpen() bool {
@@ -156,7 +342,7 @@ func (te *tableEngine) createPlayerGameAction(playerID string, playerIdx int, ac
 		CompetitionID: te.table.Meta.CompetitionID,
 		TableID:       te.table.ID,
 		GameCount:     te.table.State.GameCount,
-		UpdateAt:      time.Now().Unix(),
+		UpdateAt:      te.clock.Now().Unix(),
 		PlayerID:      playerID,
 		Action:        action,
 		Chips:         chips,
@@ -226,6 +412,7 @@ func (te *tableEngine) batchAddPlayers(players []JoinPlayer) error {
 			Positions:      []string{},
 			IsParticipated: false,
 			Bankroll:       player.RedeemChips,
+			TimeBank:       te.table.Meta.TimeBankInitial,
 			IsIn:           false,
 			GameStatistics: NewPlayerGameStatistics(),
 		}
@@ -250,26 +437,36 @@ func (te *tableEngine) batchAddPlayers(players []JoinPlayer) error {
 	return nil
 }
 
+/*
+playersAutoIn 等待玩家入座，時間到了還沒有入座則自動入座
+  - 等待中再有玩家加入時只加入 ReadyGroup，不重新啟動 (重新啟動會與 ReadyGroup 的 goroutine 互相競爭)
+*/
 func (te *tableEngine) playersAutoIn() {
-	// Preparing ready group for waiting all players' join
-	te.rg.Stop()
-	te.rg.SetTimeoutInterval(17)
-	te.rg.OnTimeout(func(rg *syncsaga.ReadyGroup) {
-		// Auto Ready By Default
-		states := rg.GetParticipantStates()
-		for playerIdx, isReady := range states {
-			if !isReady {
-				rg.Ready(playerIdx)
+	if te.isPlayersAutoInWaiting {
+		participantStates := te.rg.GetParticipantStates()
+		for playerIdx := range te.table.State.PlayerStates {
+			if _, exist := participantStates[int64(playerIdx)]; !exist && !te.table.State.PlayerStates[playerIdx].IsIn {
+				te.rg.Add(int64(playerIdx), false)
 			}
 		}
-	})
+		te.newPlayersAutoInTask()
+		return
+	}
+
+	// Preparing ready group for waiting all players' join
+	te.isPlayersAutoInWaiting = true
+	te.rg.Stop()
 	te.rg.OnCompleted(func(rg *syncsaga.ReadyGroup) {
+		te.tbForPlayersAutoIn.Cancel()
+
+		te.lock.Lock()
+		te.isPlayersAutoInWaiting = false
 		isInCount := 0
 		alivePlayers := 0
 		for playerIdx, player := range te.table.State.PlayerStates {
 			// 如果時間到了還沒有入座則自動入座
 			if !player.IsIn {
-				te.PlayerJoin(player.PlayerID)
+				te.playerJoin(player.PlayerID)
 			}
 
 			if te.table.State.PlayerStates[playerIdx].IsIn {
@@ -290,11 +487,14 @@ func (te *tableEngine) playersAutoIn() {
 		}
 		isGameRunning := funk.Contains(gameStartingStatuses, te.table.State.Status)
 		// 非中場休息，有活著的玩家，且未開始遊戲
-		if isInCount >= 2 && alivePlayers >= 2 && !isGameRunning && te.table.State.BlindState.Level > 0 && te.table.State.GameCount == 0 {
+		shouldStart := isInCount >= 2 && alivePlayers >= 2 && !isGameRunning && te.table.State.BlindState.Level > 0 && te.table.State.GameCount == 0
+		te.lock.Unlock()
+
+		if shouldStart {
 			// 尚未開第一手，StartTableGame (MTT Only, CT 是由 competition 決定開始)
 			// TODO 是否考慮 CT 是否有暫停
 			if te.table.Meta.Mode == CompetitionMode_MTT {
-				if err := te.StartTableGame(); err != nil {
+				if err := te.startTableGame(); err != nil {
 					te.emitErrorEvent("StartTableGame", "", err)
 				}
 			}
@@ -310,6 +510,23 @@ func (te *tableEngine) playersAutoIn() {
 	}
 
 	te.rg.Start()
+	te.newPlayersAutoInTask()
+}
+
+// newPlayersAutoInTask 重設自動入座的計時器
+func (te *tableEngine) newPlayersAutoInTask() {
+	te.tbForPlayersAutoIn.NewTask(time.Second*17, func(isCancelled bool) {
+		if isCancelled {
+			return
+		}
+
+		// Auto Ready By Default
+		for playerIdx, isReady := range te.rg.GetParticipantStates() {
+			if !isReady {
+				te.rg.Ready(playerIdx)
+			}
+		}
+	})
 }
 
 func (te *tableEngine) batchRemovePlayers(playerIDs []string) error {
@@ -411,3 +628,41 @@ func (te *tableEngine) calcGamePlayerIndexes(rule string, maxSeatCount, currentD
 
 	return gamePlayerIndexes
 }
+
+// isSeatSitOutEnabled 暫離玩家是否從座位輪轉中移除 (現金桌/CT)
+func (te *tableEngine) isSeatSitOutEnabled() bool {
+	return te.table.Meta.Mode == CompetitionMode_Cash || te.table.Meta.Mode == CompetitionMode_CT
+}
+
+func (te *tableEngine) sitOutPlayer(playerState *TablePlayerState) error {
+	if te.isSeatSitOutEnabled() {
+		if err := te.sm.SitOutPlayer(playerState.PlayerID); err != nil {
+			return err
+		}
+	}
+
+	playerState.IsSitOut = true
+	return nil
+}
+
+func (te *tableEngine) isTimeBankEnabled() bool {
+	return te.table.Meta.TimeBankInitial > 0 || te.table.Meta.TimeBankLevelTopUp > 0
+}
+
+func (te *tableEngine) extendCurrentActionEndAt(duration int) int64 {
+	endAt := time.Unix(te.table.State.CurrentActionEndAt, 0)
+	te.table.State.CurrentActionEndAt = endAt.Add(time.Duration(duration) * time.Second).Unix()
+	te.tbForAction.Extend(time.Duration(duration) * time.Second)
+	return te.table.State.CurrentActionEndAt
+}
+
+// topUpTimeBank 盲注升級時補充所有玩家的時間銀行
+func (te *tableEngine) topUpTimeBank() {
+	if te.table.Meta.TimeBankLevelTopUp <= 0 {
+		return
+	}
+
+	for _, player := range te.table.State.PlayerStates {
+		player.TimeBank += te.table.Meta.TimeBankLevelTopUp
+	}
+}
@@ -0,0 +1,236 @@
+package pokertable
+
+import (
+	"errors"
+
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable/open_game_manager"
+	"github.com/weedbox/pokertable/seat_manager"
+)
+
+var (
+	ErrTableInvalidSnapshot = errors.New("table: invalid snapshot")
+)
+
+type TableEngineSnapshot struct {
+	Table                 *Table                          `json:"table"`                   // 桌次
+	SeatManager           seat_manager.SeatManagerState   `json:"seat_manager"`            // 座位管理狀態
+	OpenGame              open_game_manager.OpenGameState `json:"open_game"`               // 開局管理狀態
+	GameState             *pokerface.GameState            `json:"game_state"`              // 進行中的本手狀態
+	GameParticipantStates map[int64]bool                  `json:"game_participant_states"` // 本手 ReadyGroup 玩家準備狀態 (key: game player index)
+}
+
+/*
+NewTableEngineFromSnapshot 從快照建立桌次引擎
+  - 適用時機: 桌次轉移到其他服務 (Graceful Shutdown) 後還原
+*/
+func NewTableEngineFromSnapshot(options *TableEngineOptions, callbacks *TableEngineCallbacks, snapshot TableEngineSnapshot, opts ...TableEngineOpt) (TableEngine, error) {
+	if callbacks == nil {
+		callbacks = NewTableEngineCallbacks()
+	}
+
+	tableEngine := NewTableEngine(options, opts...)
+	tableEngine.OnTableUpdated(callbacks.OnTableUpdated)
+	tableEngine.OnTableErrorUpdated(callbacks.OnTableErrorUpdated)
+	tableEngine.OnTableStateUpdated(callbacks.OnTableStateUpdated)
+	tableEngine.OnTablePlayerStateUpdated(callbacks.OnTablePlayerStateUpdated)
+	tableEngine.OnTablePlayerReserved(callbacks.OnTablePlayerReserved)
+	tableEngine.OnGamePlayerActionUpdated(callbacks.OnGamePlayerActionUpdated)
+	tableEngine.OnAutoGameOpenEnd(callbacks.OnAutoGameOpenEnd)
+	tableEngine.OnReadyOpenFirstTableGame(callbacks.OnReadyOpenFirstTableGame)
+	tableEngine.OnTablePlayerTimeBankUsed(callbacks.OnTablePlayerTimeBankUsed)
+	tableEngine.OnTablePlayerActionTimeout(callbacks.OnTablePlayerActionTimeout)
+	tableEngine.OnGameSettled(callbacks.OnGameSettled)
+	tableEngine.OnTablePatched(callbacks.OnTablePatched)
+
+	if _, err := tableEngine.RestoreTable(snapshot); err != nil {
+		return nil, err
+	}
+
+	return tableEngine, nil
+}
+
+/*
+GetSnapshot 取得桌次快照
+  - 適用時機: 桌次轉移前保存完整狀態
+  - 會取得 te.lock，可與桌次動作、遊戲狀態更新同時呼叫
+  - 不可在持有 te.lock 時發出的事件監聽器 (例如玩家動作超時) 中同步呼叫
+*/
+func (te *tableEngine) GetSnapshot() (*TableEngineSnapshot, error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+
+	return te.snapshot()
+}
+
+// snapshot 產生桌次快照 (需持有 te.lock，或由 persistTable 在發出事件時呼叫)
+func (te *tableEngine) snapshot() (*TableEngineSnapshot, error) {
+	if te.table == nil || te.sm == nil || te.ogm == nil {
+		return nil, ErrTableInvalidSnapshot
+	}
+
+	table, err := te.table.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := TableEngineSnapshot{
+		Table:                 table,
+		SeatManager:           te.sm.GetState(),
+		OpenGame:              te.cloneOpenGameState(te.ogm.GetState()),
+		GameParticipantStates: make(map[int64]bool),
+	}
+
+	if te.game != nil && te.table.State.GameState != nil {
+		if gs := te.game.GetGameState(); gs != nil {
+			snapshot.GameState = cloneGameState(gs)
+			snapshot.GameParticipantStates = te.game.GetParticipantStates()
+		}
+	}
+
+	return &snapshot, nil
+}
+
+/*
+RestoreTable 從快照還原桌次
+  - 適用時機: 桌次轉移後，於新的服務繼續進行
+*/
+func (te *tableEngine) RestoreTable(snapshot TableEngineSnapshot) (_ *Table, err error) {
+	if snapshot.Table == nil || snapshot.Table.State == nil {
+		return nil, ErrTableInvalidSnapshot
+	}
+
+	table, err := snapshot.Table.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	// 有記錄事件時，固定桌次亂數來源的種子 (重播時可重現相同的座位與牌組)
+	randomSeed := te.newRandomSeed()
+
+	// restore seat manager & open game manager
+	te.sm = seat_manager.NewSeatManagerFromState(snapshot.SeatManager, te.seatManagerOpts()...)
+	te.ogm = open_game_manager.NewOpenGameManagerFromState(te.cloneOpenGameState(snapshot.OpenGame), te.newOpenGameOption())
+	te.table = table
+	defer te.recordUnlockedCommand("RestoreTable", "", te.newTableEventLogTableArgs(nil, &snapshot, randomSeed))(&err)
+
+	// 還原後的第一個桌次差異改為完整桌次
+	if te.patchEncoder != nil {
+		te.patchEncoder.Reset()
+	}
+
+	// restore game
+	gameStatuses := []TableStateStatus{
+		TableStateStatus_TableGamePlaying,
+		TableStateStatus_TableGameSettled,
+	}
+	if funk.Contains(gameStatuses, te.table.State.Status) {
+		gs := snapshot.GameState
+		if gs == nil {
+			gs = te.table.State.GameState
+		}
+
+		if gs == nil {
+			return nil, ErrTableInvalidSnapshot
+		}
+
+		gameOpts := []GameOpt{WithGameBlindPosts(te.table.State.GameBlindPosts), withGameTimer(te.tbForGameReady)}
+		if te.table.State.GameBombPot > 0 {
+			gameOpts = append(gameOpts, WithGameBombPot())
+		}
+
+		// 已詢問過多次發牌時，只有仍停在詢問當下才需要重新詢問
+		runout := te.table.State.GameRunout
+		if te.table.Meta.MaxRunItTimes > 1 && (runout == nil || (gs.Status.CurrentEvent == pokerface.GameEventSymbols[pokerface.GameEvent_RoundClosed] && len(gs.Status.Board) == runout.BoardSize)) {
+			gameOpts = append(gameOpts, WithGameRunItTimes(te.table.Meta.MaxRunItTimes))
+		}
+
+		te.game = NewGameFromState(te.gameBackend, gs, gameOpts...)
+		te.equityRand = te.newEquityRand()
+		te.table.State.GameState = te.game.GetGameState()
+	}
+
+	te.emitEvent("RestoreTable", "")
+	te.emitTableStateEvent(TableStateEvent_Restored)
+
+	if err := te.resumeTable(snapshot.GameParticipantStates); err != nil {
+		return nil, err
+	}
+
+	return te.table, nil
+}
+
+func (te *tableEngine) resumeTable(gameParticipantStates map[int64]bool) error {
+	// 還沒入座的玩家，重新等待入座
+	for _, player := range te.table.State.PlayerStates {
+		if !player.IsIn {
+			te.playersAutoIn()
+			break
+		}
+	}
+
+	switch te.table.State.Status {
+	case TableStateStatus_TableGameOpened:
+		// 已開局但遊戲引擎尚未啟動
+		if te.table.State.GameState == nil {
+			return te.startGame()
+		}
+	case TableStateStatus_TableGamePlaying:
+		te.bindGameEvents()
+		gs, err := te.game.Resume(gameParticipantStates)
+		if err != nil {
+			return err
+		}
+
+		// 等待玩家下注時不會再經過遊戲狀態更新，依還原前的動作結束時間重新設定計時器 (已逾時則立即觸發)
+		if event, ok := pokerface.GameEventBySymbol[gs.Status.CurrentEvent]; ok {
+			te.refreshActionTimer(event, gs)
+		}
+
+		// 本手已結束但尚未結算
+		if gs.Status.CurrentEvent == pokerface.GameEventSymbols[pokerface.GameEvent_GameClosed] {
+			go func() {
+				if err := te.onGameClosed(); err != nil {
+					te.emitErrorEvent("RestoreTable#onGameClosed", "", err)
+				}
+			}()
+		}
+	case TableStateStatus_TableGameSettled:
+		// 已結算，繼續下一手
+		alivePlayers := te.table.AlivePlayers()
+		go func() {
+			if err := te.continueGame(alivePlayers); err != nil {
+				te.emitErrorEvent("RestoreTable#continueGame", "", err)
+			}
+		}()
+	case TableStateStatus_TableGameStandby:
+		// 尚未設定下一手開局，繼續下一手
+		if te.ogm.GetState().GameCount <= te.table.State.GameCount {
+			alivePlayers := te.table.AlivePlayers()
+			go func() {
+				if err := te.continueGame(alivePlayers); err != nil {
+					te.emitErrorEvent("RestoreTable#continueGame", "", err)
+				}
+			}()
+		}
+	}
+
+	return nil
+}
+
+func (te *tableEngine) cloneOpenGameState(state open_game_manager.OpenGameState) open_game_manager.OpenGameState {
+	participants := make(map[string]*open_game_manager.OpenGameParticipant)
+	for id, participant := range state.Participants {
+		if participant != nil {
+			p := *participant
+			participants[id] = &p
+		}
+	}
+
+	return open_game_manager.OpenGameState{
+		Timeout:      state.Timeout,
+		GameCount:    state.GameCount,
+		Participants: participants,
+	}
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/thoas/go-funk"
 	"github.com/weedbox/pokerface"
-	"github.com/weedbox/pokerface/settlement"
 )
 
 func (te *tableEngine) tableGameOpen() error {
@@ -71,7 +70,8 @@ func (te *tableEngine) tableGameOpen() error {
 			return err
 		}
 	}
-	te.table = newTable
+	// 只更新桌次狀態 (桌次 ID 與設定不變，發出事件時不需持有 te.lock 即可讀取)
+	te.table.State = newTable.State
 	te.emitEvent("tableGameOpen", "")
 
 	// 啟動本手遊戲引擎
@@ -117,6 +117,12 @@ func (te *tableEngine) openGame(oldTable *Table) (*Table, error) {
 			return oldTable, err
 		}
 		player.IsParticipated = active
+
+		// 同步現金桌錯過盲注紀錄
+		if sp, exist := te.sm.Seats()[player.Seat]; exist && sp != nil && sp.ID == player.PlayerID {
+			player.MissedSB = sp.MissedSB
+			player.MissedBB = sp.MissedBB
+		}
 	}
 
 	// update gamePlayerIndexes & positions
@@ -139,6 +145,9 @@ func (te *tableEngine) openGame(oldTable *Table) (*Table, error) {
 	cloneTable.State.CurrentSBSeat = te.sm.CurrentSBSeatID()
 	cloneTable.State.CurrentBBSeat = te.sm.CurrentBBSeatID()
 
+	// Step 7: 決定本手是否為 Bomb Pot
+	cloneTable.State.GameBombPot = te.calcGameBombPot(cloneTable)
+
 	return cloneTable, nil
 }
 
@@ -158,14 +167,25 @@ func (te *tableEngine) startGame() error {
 		opts.RequiredHoleCardsCount = 2
 	}
 
-	// preparing blind
-	opts.Ante = blind.Ante
+	// preparing blind (代付全桌前注不經過遊戲的前注階段)
+	bombPot := te.table.State.GameBombPot
+	if !blind.IsTableAnte() {
+		opts.Ante = blind.Ante
+	}
 	opts.Blind = pokerface.BlindSetting{
 		Dealer: blind.Dealer,
 		SB:     blind.SB,
 		BB:     blind.BB,
 	}
 
+	// Bomb Pot: 以前注收取每位玩家的籌碼，只保留大盲作為最小下注量 (不支付盲注)
+	if bombPot > 0 {
+		opts.Ante = bombPot
+		opts.Blind = pokerface.BlindSetting{
+			BB: blind.BB,
+		}
+	}
+
 	// preparing players
 	playerSettings := make([]*pokerface.PlayerSetting, 0)
 	for _, playerIdx := range te.table.State.GamePlayerIndexes {
@@ -181,58 +201,151 @@ func (te *tableEngine) startGame() error {
 	opts.Players = playerSettings
 
 	// create game
-	te.game = NewGame(te.gameBackend, opts)
+	gameOpts := []GameOpt{withGameTimer(te.tbForGameReady)}
+	if deck := te.newDeck(opts.Deck); deck != nil {
+		gameOpts = append(gameOpts, WithGameDeck(deck))
+	}
+	te.equityRand = te.newEquityRand()
+	posts := make(map[int]GameBlindPost)
+	if bombPot > 0 {
+		// Bomb Pot 不補盲注，錯過盲注的玩家留待下一手補
+		gameOpts = append(gameOpts, WithGameBombPot())
+	} else {
+		posts = te.newGameBlindPosts()
+		te.addGameAntePost(posts, playerSettings)
+	}
+	if len(posts) > 0 {
+		gameOpts = append(gameOpts, WithGameBlindPosts(posts))
+	}
+	if te.table.Meta.MaxRunItTimes > 1 {
+		gameOpts = append(gameOpts, WithGameRunItTimes(te.table.Meta.MaxRunItTimes))
+	}
+	te.game = NewGame(te.gameBackend, opts, gameOpts...)
+	te.bindGameEvents()
+	te.clearPostedMissedBlinds(posts)
+
+	// start game
+	if _, err := te.game.Start(); err != nil {
+		return err
+	}
+
+	te.table.State.Status = TableStateStatus_TableGamePlaying
+	te.table.State.GameBlindState = &TableBlindState{
+		Level:    blind.Level,
+		Ante:     blind.Ante,
+		AnteMode: blind.AnteMode,
+		Dealer:   blind.Dealer,
+		SB:       blind.SB,
+		BB:       blind.BB,
+	}
+	return nil
+}
+
+func (te *tableEngine) bindGameEvents() {
+	// 遊戲事件在遊戲引擎的 goroutine 觸發，持有 te.lock 讀寫桌次，釋放後才發出事件
 	te.game.OnGameStateUpdated(func(gs *pokerface.GameState) {
 		te.updateGameState(gs)
 	})
 	te.game.OnGameErrorUpdated(func(gs *pokerface.GameState, err error) {
+		te.lock.Lock()
 		te.table.State.GameState = gs
+		te.lock.Unlock()
 		go te.emitErrorEvent("OnGameErrorUpdated", "", err)
 	})
 	te.game.OnAntesReceived(func(gs *pokerface.GameState) {
+		te.lock.Lock()
+		gameActions := make([]*TablePlayerGameAction, 0)
 		for gpIdx, p := range gs.Players {
 			if playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gpIdx); playerIdx != UnsetValue {
+				// 籌碼不足時只付剩餘籌碼
+				ante := gs.Meta.Ante
+				if p.Bankroll < ante {
+					ante = p.Bankroll
+				}
+
 				player := te.table.State.PlayerStates[playerIdx]
-				pga := te.createPlayerGameAction(player.PlayerID, playerIdx, "pay", player.Bankroll, p)
+				pga := te.createPlayerGameAction(player.PlayerID, playerIdx, "pay", ante, p)
 				pga.Round = "ante"
-				te.emitGamePlayerActionEvent(*pga)
+				gameActions = append(gameActions, pga)
 			}
 		}
+		te.lock.Unlock()
+
+		for _, pga := range gameActions {
+			te.emitGamePlayerActionEvent(*pga)
+		}
 	})
 	te.game.OnBlindsReceived(func(gs *pokerface.GameState) {
+		te.lock.Lock()
+		gameActions := make([]*TablePlayerGameAction, 0)
+
+		// 代付全桌前注 (先於盲注支付)
+		for gpIdx, p := range gs.Players {
+			if post := te.table.State.GameBlindPosts[gpIdx]; post.Ante > 0 {
+				if playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gpIdx); playerIdx != UnsetValue {
+					player := te.table.State.PlayerStates[playerIdx]
+					pga := te.createPlayerGameAction(player.PlayerID, playerIdx, "pay", post.Ante, p)
+					pga.Round = "ante"
+					gameActions = append(gameActions, pga)
+				}
+			}
+		}
+
 		for gpIdx, p := range gs.Players {
+			post := te.table.State.GameBlindPosts[gpIdx]
+			isPosted := post.Live > 0 || post.Dead > 0
 			for _, pos := range p.Positions {
 				if funk.Contains([]string{Position_SB, Position_BB}, pos) {
-					if playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gpIdx); playerIdx != UnsetValue {
-						player := te.table.State.PlayerStates[playerIdx]
-						pga := te.createPlayerGameAction(player.PlayerID, playerIdx, "pay", player.Bankroll, p)
-						te.emitGamePlayerActionEvent(*pga)
-					}
+					isPosted = true
+				}
+			}
+
+			if isPosted {
+				if playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gpIdx); playerIdx != UnsetValue {
+					player := te.table.State.PlayerStates[playerIdx]
+					pga := te.createPlayerGameAction(player.PlayerID, playerIdx, "pay", p.Wager+post.Dead, p)
+					gameActions = append(gameActions, pga)
 				}
 			}
 		}
+
+		// Straddle (於盲注後支付)
+		for gpIdx, p := range gs.Players {
+			if post := te.table.State.GameBlindPosts[gpIdx]; post.Straddle > 0 {
+				if playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(gpIdx); playerIdx != UnsetValue {
+					player := te.table.State.PlayerStates[playerIdx]
+					pga := te.createPlayerGameAction(player.PlayerID, playerIdx, Action_Straddle, p.Wager, p)
+					gameActions = append(gameActions, pga)
+				}
+			}
+		}
+		te.lock.Unlock()
+
+		for _, pga := range gameActions {
+			te.emitGamePlayerActionEvent(*pga)
+		}
 	})
 	te.game.OnGameRoundClosed(func(gs *pokerface.GameState) {
+		te.lock.Lock()
+		defer te.lock.Unlock()
+
 		te.table.State.CurrentActionEndAt = 0
+		te.updateGameEquity(gs)
 	})
+	te.game.OnRunItMultipleRequested(func(gs *pokerface.GameState) {
+		te.lock.Lock()
+		defer te.lock.Unlock()
 
-	// start game
-	if _, err := te.game.Start(); err != nil {
-		return err
-	}
-
-	te.table.State.Status = TableStateStatus_TableGamePlaying
-	te.table.State.GameBlindState = &TableBlindState{
-		Level:  blind.Level,
-		Ante:   blind.Ante,
-		Dealer: blind.Dealer,
-		SB:     blind.SB,
-		BB:     blind.BB,
-	}
-	return nil
+		// 從快照還原後重新詢問時保留已同意的玩家
+		if te.table.State.GameRunout == nil {
+			te.table.State.GameRunout = te.newGameRunout(gs)
+		}
+	})
 }
 
 func (te *tableEngine) settleGame() []*TablePlayerState {
+	// 結算期間持有 te.lock，發出事件前釋放 (監聽者可能同步呼叫桌次動作)
+	te.lock.Lock()
 	te.table.State.Status = TableStateStatus_TableGameSettled
 
 	// 計算攤牌勝率用
@@ -244,26 +357,36 @@ func (te *tableEngine) settleGame() []*TablePlayerState {
 		}
 	}
 
-	// 計算贏家
-	rank := settlement.NewRank()
-	for _, player := range te.table.State.GameState.Players {
-		if !player.Fold {
-			rank.AddContributor(player.Combination.Power, player.Idx)
-		}
-	}
-	rank.Calculate()
-	winnerGamePlayerIndexes := rank.GetWinners()
+	// 補齊平手的贏家 (後續的死盲、多次發牌與抽水都依各池贏家分配)
+	te.settlePotWinners()
+
+	// 死盲併入主池 (需在多次發牌之前，才會依各次公牌分配)
+	te.settleDeadBlinds()
+
+	// 多次發牌重新分配各池
+	te.settleRunouts()
+
+	// 計算贏家 (多次發牌時任一次公牌有分到籌碼即為贏家)
 	winnerPlayerIndexes := make(map[int]bool)
-	for _, winnerGamePlayerIndex := range winnerGamePlayerIndexes {
-		playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(winnerGamePlayerIndex)
-		if playerIdx == UnsetValue {
-			fmt.Printf("[DEBUGsettleGame] can't find player index from game player index (%d)", winnerGamePlayerIndex)
-			continue
-		}
+	for _, pot := range te.table.State.GameState.Result.Pots {
+		for _, winner := range pot.Winners {
+			if winner.Withdraw <= 0 {
+				continue
+			}
 
-		winnerPlayerIndexes[playerIdx] = true
+			playerIdx := te.table.FindPlayerIndexFromGamePlayerIndex(winner.Idx)
+			if playerIdx == UnsetValue {
+				fmt.Printf("[DEBUGsettleGame] can't find player index from game player index (%d)", winner.Idx)
+				continue
+			}
+
+			winnerPlayerIndexes[playerIdx] = true
+		}
 	}
 
+	// 現金桌抽水
+	te.settleRake()
+
 	// 把玩家輸贏籌碼更新到 Bankroll
 	alivePlayers := make([]*TablePlayerState, 0)
 	for _, player := range te.table.State.GameState.Result.Players {
@@ -290,62 +413,59 @@ func (te *tableEngine) settleGame() []*TablePlayerState {
 	// 更新 NextBBOrderPlayerIDs (移除沒有籌碼的玩家)
 	te.table.State.NextBBOrderPlayerIDs = te.refreshNextBBOrderPlayerIDs(te.sm.CurrentBBSeatID(), te.table.Meta.TableMaxSeatCount, te.table.State.PlayerStates, te.table.State.SeatMap)
 
-	te.emitEvent("SettleTableGameResult", "")
-	te.emitTableStateEvent(TableStateEvent_GameSettled)
+	// 先產生結算結果，避免 GameSettled 監聽者調整玩家後影響結果
+	result := te.newGameResult()
+	update := te.refreshTable("SettleTableGameResult", "")
+	te.lock.Unlock()
+
+	te.publishTableUpdate(update)
+	te.publishTableStateEvent(TableStateEvent_GameSettled, update.table)
+	te.emitGameSettledEvent(result)
 
 	return alivePlayers
 }
 
 func (te *tableEngine) continueGame(alivePlayers []*TablePlayerState) error {
-	// Reset table state
-	te.table.State.Status = TableStateStatus_TableGameStandby
-	te.table.State.GamePlayerIndexes = make([]int, 0)
-	te.table.State.NextBBOrderPlayerIDs = make([]string, 0)
-	te.table.State.CurrentActionEndAt = 0
-	te.table.State.GameState = nil
-	te.table.State.LastPlayerGameAction = nil
-	for i := 0; i < len(te.table.State.PlayerStates); i++ {
-		playerState := te.table.State.PlayerStates[i]
-		playerState.Positions = make([]string, 0)
-		playerState.GameStatistics = NewPlayerGameStatistics()
-		if err := te.sm.UpdatePlayerHasChips(playerState.PlayerID, playerState.Bankroll > 0); err != nil {
-			return err
-		}
-		active, err := te.sm.IsPlayerActive(playerState.PlayerID)
-		if err != nil {
-			return err
-		}
-
-		playerState.IsParticipated = active
+	table, err := te.resetGame()
+	if err != nil {
+		return err
 	}
 
+	// 本手已重置，監聽者 (例如 MTT 換桌) 可在下一手開局前調整玩家
+	te.publishTableStateEvent(TableStateEvent_StatusUpdated, table)
+
 	var nextMoveInterval int
 	var nextMoveHandler func() error
 
 	// 桌次時間到了則不自動開下一手 (CT/Cash)
 	ctMTTAutoGameOpenEnd := false
-	if te.table.Meta.Mode == CompetitionMode_CT || te.table.Meta.Mode == CompetitionMode_Cash {
-		tableEndAt := time.Unix(te.table.State.StartAt, 0).Add(time.Second * time.Duration(te.table.Meta.MaxDuration)).Unix()
-		ctMTTAutoGameOpenEnd = time.Now().Unix() > tableEndAt
+	if table.Meta.Mode == CompetitionMode_CT || table.Meta.Mode == CompetitionMode_Cash {
+		tableEndAt := time.Unix(table.State.StartAt, 0).Add(time.Second * time.Duration(table.Meta.MaxDuration)).Unix()
+		ctMTTAutoGameOpenEnd = te.clock.Now().Unix() > tableEndAt
 	}
 
 	if ctMTTAutoGameOpenEnd {
 		nextMoveInterval = 1
 		nextMoveHandler = func() error {
-			fmt.Printf("[DEBUG#continueGame] delay -> not auto opened %s table (%s), end: %s, now: %s\n", te.table.Meta.Mode, te.table.ID, time.Unix(te.table.State.StartAt, 0).Add(time.Second*time.Duration(te.table.Meta.MaxDuration)), time.Now())
-			te.onAutoGameOpenEnd(te.table.Meta.CompetitionID, te.table.ID)
+			fmt.Printf("[DEBUG#continueGame] delay -> not auto opened %s table (%s), end: %s, now: %s\n", table.Meta.Mode, table.ID, time.Unix(table.State.StartAt, 0).Add(time.Second*time.Duration(table.Meta.MaxDuration)), te.clock.Now())
+			te.emitAutoGameOpenEndEvent()
 			return nil
 		}
 	} else {
 		nextMoveInterval = te.options.GameContinueInterval
 		nextMoveHandler = func() error {
+			// 等待期間監聽者可能調整桌次，持有 te.lock 讀取
+			te.lock.Lock()
+
 			// 如果在 Interval 這期間，該桌已關閉，則不繼續動作
 			if te.table.State.Status == TableStateStatus_TableClosed {
+				te.lock.Unlock()
 				return nil
 			}
 
 			// 如果在 Interval 這期間，該桌已釋放，則不繼續動作
 			if te.isReleased {
+				te.lock.Unlock()
 				return nil
 			}
 
@@ -353,23 +473,27 @@ func (te *tableEngine) continueGame(alivePlayers []*TablePlayerState) error {
 			if te.table.ShouldPause() {
 				// 暫停處理
 				te.table.State.Status = TableStateStatus_TablePausing
-				te.emitEvent("ContinueGame -> Pause", "")
-				te.emitTableStateEvent(TableStateEvent_StatusUpdated)
+				update := te.refreshTable("ContinueGame -> Pause", "")
+				te.lock.Unlock()
+				te.publishTableUpdate(update)
+				te.publishTableStateEvent(TableStateEvent_StatusUpdated, update.table)
 			} else {
 				if te.shouldAutoGameOpen() {
 					// fmt.Println("[DEBUG#continueGame] delay -> TableGameOpen")
 					// return te.TableGameOpen()
 					nextGameCount := te.table.State.GameCount + 1
+					te.lock.Unlock()
 					participants := make(map[string]int)
 					for idx, player := range alivePlayers {
 						participants[player.PlayerID] = idx
 					}
-					te.SetUpTableGame(nextGameCount, participants)
+					te.ogm.Setup(nextGameCount, participants)
 					return nil
 				}
 
 				// Unhandled Situation
 				str, _ := te.table.GetJSON()
+				te.lock.Unlock()
 				fmt.Printf("[DEBUG#continueGame] delay -> unhandled issue. Table: %s\n", str)
 			}
 			return nil
@@ -378,3 +502,39 @@ func (te *tableEngine) continueGame(alivePlayers []*TablePlayerState) error {
 
 	return te.delay(nextMoveInterval, nextMoveHandler)
 }
+
+// resetGame 重置本手狀態 (持有 te.lock)，回傳重置後的桌次複本
+func (te *tableEngine) resetGame() (*Table, error) {
+	te.lock.Lock()
+	defer te.lock.Unlock()
+
+	te.table.State.Status = TableStateStatus_TableGameStandby
+	te.table.State.GamePlayerIndexes = make([]int, 0)
+	te.table.State.GameBlindPosts = make(map[int]GameBlindPost)
+	te.table.State.GameRake = nil
+	te.table.State.GameRunout = nil
+	te.table.State.GameEquity = nil
+	te.equityRand = nil
+	te.table.State.GameBombPot = 0
+	te.table.State.GameRoundBetCount = 0
+	te.table.State.NextBBOrderPlayerIDs = make([]string, 0)
+	te.table.State.CurrentActionEndAt = 0
+	te.table.State.GameState = nil
+	te.table.State.LastPlayerGameAction = nil
+	for i := 0; i < len(te.table.State.PlayerStates); i++ {
+		playerState := te.table.State.PlayerStates[i]
+		playerState.Positions = make([]string, 0)
+		playerState.GameStatistics = NewPlayerGameStatistics()
+		if err := te.sm.UpdatePlayerHasChips(playerState.PlayerID, playerState.Bankroll > 0); err != nil {
+			return nil, err
+		}
+		active, err := te.sm.IsPlayerActive(playerState.PlayerID)
+		if err != nil {
+			return nil, err
+		}
+
+		playerState.IsParticipated = active
+	}
+
+	return te.cloneTable(), nil
+}
@@ -0,0 +1,205 @@
+package pokertable
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrTablePatchGap           = errors.New("table patch: update serial gap")
+	ErrTablePatchTableMismatch = errors.New("table patch: table mismatch")
+)
+
+type TablePatch struct {
+	TableID      string               `json:"table_id"`      // 桌次 ID
+	BaseSerial   int64                `json:"base_serial"`   // 套用前的桌次更新序列號 (0 表示完整桌次)
+	UpdateSerial int64                `json:"update_serial"` // 套用後的桌次更新序列號
+	Operations   []JSONPatchOperation `json:"operations"`    // JSON Patch (RFC 6902) 操作
+}
+
+// IsFull 是否為完整桌次 (不需要先前的桌次即可套用)
+func (p TablePatch) IsFull() bool {
+	return p.BaseSerial == 0
+}
+
+/*
+TablePatchEncoder 產生桌次差異 (伺服器端)
+  - 每個接收端 (或每種視角) 各自使用一個 Encoder
+  - 第一次或 Reset 後產生完整桌次，之後產生與上一次桌次的差異
+  - 依建立方式決定視角: 完整桌次 (含牌組與所有玩家底牌，只能用於伺服器內部)、玩家視角 (ViewFor) 或旁觀者視角 (ViewForObserver)
+*/
+type TablePatchEncoder struct {
+	mu           sync.Mutex
+	view         func(table *Table) (*Table, error)
+	lastTable    interface{}
+	lastSerial   int64
+	hasLastTable bool
+}
+
+// NewTablePatchEncoder 完整桌次的差異 (含牌組與所有玩家底牌，不可直接發送給玩家)
+func NewTablePatchEncoder() *TablePatchEncoder {
+	return &TablePatchEncoder{}
+}
+
+// NewPlayerTablePatchEncoder 指定玩家視角的差異 (ViewFor)
+func NewPlayerTablePatchEncoder(playerID string) *TablePatchEncoder {
+	return &TablePatchEncoder{
+		view: func(table *Table) (*Table, error) {
+			return table.ViewFor(playerID)
+		},
+	}
+}
+
+// NewObserverTablePatchEncoder 旁觀者視角的差異 (ViewForObserver)
+func NewObserverTablePatchEncoder() *TablePatchEncoder {
+	return &TablePatchEncoder{
+		view: func(table *Table) (*Table, error) {
+			return table.ViewForObserver()
+		},
+	}
+}
+
+// Encode 產生與上一次桌次的差異 (依 Encoder 的視角隱藏私密資訊)
+func (e *TablePatchEncoder) Encode(table *Table) (*TablePatch, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.view != nil {
+		view, err := e.view(table)
+		if err != nil {
+			return nil, err
+		}
+		table = view
+	}
+
+	current, err := toJSONObject(table)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &TablePatch{
+		TableID:      table.ID,
+		BaseSerial:   0,
+		UpdateSerial: table.UpdateSerial,
+	}
+
+	if e.hasLastTable {
+		patch.BaseSerial = e.lastSerial
+		patch.Operations, err = createJSONPatch(e.lastTable, current)
+	} else {
+		patch.Operations = make([]JSONPatchOperation, 0, 1)
+		err = appendJSONPatchValue(&patch.Operations, JSONPatchOp_Replace, "", current)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.lastTable = current
+	e.lastSerial = table.UpdateSerial
+	e.hasLastTable = true
+
+	return patch, nil
+}
+
+// Reset 下一次改為產生完整桌次 (接收端發現缺漏時使用)
+func (e *TablePatchEncoder) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastTable = nil
+	e.lastSerial = 0
+	e.hasLastTable = false
+}
+
+/*
+TablePatchDecoder 套用桌次差異 (客戶端)
+  - 收到完整桌次時直接取代目前桌次
+  - BaseSerial 與目前的桌次更新序列號不同時回傳 ErrTablePatchGap，需要向伺服器重新取得完整桌次
+*/
+type TablePatchDecoder struct {
+	mu           sync.Mutex
+	tableID      string
+	document     interface{}
+	updateSerial int64
+}
+
+func NewTablePatchDecoder() *TablePatchDecoder {
+	return &TablePatchDecoder{}
+}
+
+// Apply 套用桌次差異並回傳套用後的桌次
+func (d *TablePatchDecoder) Apply(patch *TablePatch) (*Table, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !patch.IsFull() {
+		if d.document == nil || patch.BaseSerial != d.updateSerial {
+			return nil, ErrTablePatchGap
+		}
+
+		if patch.TableID != d.tableID {
+			return nil, ErrTablePatchTableMismatch
+		}
+	}
+
+	// 先套用到複本，失敗時保留原本的桌次
+	var document interface{}
+	if d.document != nil {
+		encoded, err := json.Marshal(d.document)
+		if err != nil {
+			return nil, err
+		}
+		if err := decodeJSONWithNumber(encoded, &document); err != nil {
+			return nil, err
+		}
+	}
+
+	document, err := applyJSONPatch(document, patch.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := decodeTableDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	d.tableID = patch.TableID
+	d.document = document
+	d.updateSerial = patch.UpdateSerial
+
+	return table, nil
+}
+
+// UpdateSerial 目前桌次的更新序列號 (0 表示尚未收到完整桌次)
+func (d *TablePatchDecoder) UpdateSerial() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.updateSerial
+}
+
+// Reset 清除目前桌次，等待下一個完整桌次
+func (d *TablePatchDecoder) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.tableID = ""
+	d.document = nil
+	d.updateSerial = 0
+}
+
+func decodeTableDocument(document interface{}) (*Table, error) {
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var table Table
+	if err := json.Unmarshal(encoded, &table); err != nil {
+		return nil, err
+	}
+
+	return &table, nil
+}
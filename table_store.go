@@ -0,0 +1,274 @@
+package pokertable
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrTableStoreTableNotFound        = errors.New("table store: table not found")
+	ErrTableStoreInvalidTable         = errors.New("table store: invalid table")
+	ErrTableStoreUpdateSerialNotFound = errors.New("table store: update serial not found")
+)
+
+type TableStore interface {
+	Save(snapshot *TableEngineSnapshot) error                                // 保存桌次快照 (以 Table.ID & Table.UpdateSerial 為鍵)
+	Load(tableID string) (*TableEngineSnapshot, error)                       // 讀取桌次最新快照
+	LoadAt(tableID string, updateSerial int64) (*TableEngineSnapshot, error) // 讀取桌次指定 UpdateSerial 的快照
+	List() ([]string, error)                                                 // 列出所有桌次 ID
+	Delete(tableID string) error                                             // 刪除桌次
+}
+
+const (
+	fileTableStoreExt                     = ".jsonl"
+	fileTableStoreDefaultCompactThreshold = 500
+)
+
+/*
+FileTableStore 檔案型桌次儲存
+  - 每張桌次一個 append-log 檔案 (<dir>/<table_id>.jsonl)，每行為一筆 JSON 快照
+  - 行數達到 CompactThreshold 時，壓縮為只保留最新快照 (壓縮前的快照無法再以 LoadAt 讀取)
+  - 重新啟動後第一次寫入桌次時，從既有檔案取得行數，壓縮不會因為重新啟動而延後
+*/
+type FileTableStore struct {
+	mu               sync.Mutex
+	dir              string
+	lineCounts       map[string]int
+	CompactThreshold int
+}
+
+func NewFileTableStore(dir string) (*FileTableStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &FileTableStore{
+		dir:              dir,
+		lineCounts:       make(map[string]int),
+		CompactThreshold: fileTableStoreDefaultCompactThreshold,
+	}, nil
+}
+
+func (s *FileTableStore) Save(snapshot *TableEngineSnapshot) error {
+	if snapshot == nil || snapshot.Table == nil || snapshot.Table.ID == "" {
+		return ErrTableStoreInvalidTable
+	}
+
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tableID := snapshot.Table.ID
+	if _, exist := s.lineCounts[tableID]; !exist {
+		lineCount, err := s.countLines(tableID)
+		if err != nil {
+			return err
+		}
+		s.lineCounts[tableID] = lineCount
+	}
+
+	f, err := os.OpenFile(s.path(tableID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(encoded, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	s.lineCounts[tableID]++
+	if s.CompactThreshold > 0 && s.lineCounts[tableID] >= s.CompactThreshold {
+		return s.compact(tableID)
+	}
+
+	return nil
+}
+
+func (s *FileTableStore) Load(tableID string) (*TableEngineSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, _, err := s.load(tableID)
+	return snapshot, err
+}
+
+func (s *FileTableStore) LoadAt(tableID string, updateSerial int64) (*TableEngineSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var target *TableEngineSnapshot
+	if _, err := s.scan(tableID, func(snapshot *TableEngineSnapshot) {
+		if snapshot.Table.UpdateSerial == updateSerial {
+			target = snapshot
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	if target == nil {
+		return nil, ErrTableStoreUpdateSerialNotFound
+	}
+
+	return target, nil
+}
+
+func (s *FileTableStore) List() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	tableIDs := make([]string, 0)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileTableStoreExt) {
+			continue
+		}
+		tableIDs = append(tableIDs, strings.TrimSuffix(entry.Name(), fileTableStoreExt))
+	}
+	sort.Strings(tableIDs)
+
+	return tableIDs, nil
+}
+
+func (s *FileTableStore) Delete(tableID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.lineCounts, tableID)
+	if err := os.Remove(s.path(tableID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *FileTableStore) path(tableID string) string {
+	return filepath.Join(s.dir, filepath.Base(tableID)+fileTableStoreExt)
+}
+
+// load 讀取最大 UpdateSerial 的快照
+func (s *FileTableStore) load(tableID string) (*TableEngineSnapshot, int, error) {
+	var latest *TableEngineSnapshot
+	lineCount, err := s.scan(tableID, func(snapshot *TableEngineSnapshot) {
+		if latest == nil || snapshot.Table.UpdateSerial >= latest.Table.UpdateSerial {
+			latest = snapshot
+		}
+	})
+	if err != nil {
+		return nil, lineCount, err
+	}
+
+	if latest == nil {
+		return nil, lineCount, ErrTableStoreTableNotFound
+	}
+
+	return latest, lineCount, nil
+}
+
+// scan 依序讀取桌次檔案中的每筆快照，忽略寫到一半的損毀行，回傳檔案行數
+func (s *FileTableStore) scan(tableID string, fn func(snapshot *TableEngineSnapshot)) (int, error) {
+	f, err := os.Open(s.path(tableID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, ErrTableStoreTableNotFound
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	lineCount := 0
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineCount++
+
+			var snapshot TableEngineSnapshot
+			if json.Unmarshal(line, &snapshot) == nil && snapshot.Table != nil {
+				fn(&snapshot)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return lineCount, err
+		}
+	}
+
+	return lineCount, nil
+}
+
+// countLines 桌次檔案既有的行數 (檔案不存在時為 0)
+func (s *FileTableStore) countLines(tableID string) (int, error) {
+	f, err := os.Open(s.path(tableID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+
+	lineCount := 0
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineCount++
+		}
+
+		if err == io.EOF {
+			return lineCount, nil
+		}
+
+		if err != nil {
+			return lineCount, err
+		}
+	}
+}
+
+func (s *FileTableStore) compact(tableID string) error {
+	snapshot, _, err := s.load(tableID)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path(tableID) + ".tmp"
+	if err := os.WriteFile(tmpPath, append(encoded, '\n'), 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, s.path(tableID)); err != nil {
+		return err
+	}
+
+	s.lineCounts[tableID] = 1
+	return nil
+}
@@ -0,0 +1,69 @@
+package pokertable
+
+import (
+	"github.com/weedbox/pokerface"
+)
+
+/*
+ViewFor 取得指定玩家可見的桌次狀態
+  - 回傳深拷貝的桌次，不會修改原本的桌次
+  - 隱藏牌組、燒牌與其他玩家的底牌
+  - 攤牌後可看到未棄牌玩家的底牌
+*/
+func (t Table) ViewFor(playerID string) (*Table, error) {
+	view, err := t.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	view.redactGameState(view.FindGamePlayerIdx(playerID))
+	return view, nil
+}
+
+/*
+ViewForObserver 取得旁觀者可見的桌次狀態
+  - 回傳深拷貝的桌次，不會修改原本的桌次
+  - 隱藏牌組、燒牌與所有玩家的底牌
+  - 攤牌後可看到未棄牌玩家的底牌
+*/
+func (t Table) ViewForObserver() (*Table, error) {
+	view, err := t.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	view.redactGameState(UnsetValue)
+	return view, nil
+}
+
+/*
+redactGameState 隱藏本手私密資訊
+  - gamePlayerIdx: 可看到自己底牌的玩家 (UnsetValue 表示旁觀者)
+  - 未攤牌就結束 (只剩一位未棄牌玩家) 時，贏家底牌也不公開
+*/
+func (t *Table) redactGameState(gamePlayerIdx int) {
+	gs := t.State.GameState
+	if gs == nil {
+		return
+	}
+
+	gs.Meta.Deck = []string{}
+	gs.Status.Burned = []string{}
+
+	notFoldCount := 0
+	for _, p := range gs.Players {
+		if !p.Fold {
+			notFoldCount++
+		}
+	}
+	isShowdown := gs.Status.CurrentEvent == pokerface.GameEventSymbols[pokerface.GameEvent_GameClosed] && notFoldCount > 1
+
+	for _, p := range gs.Players {
+		if p.Idx == gamePlayerIdx || (isShowdown && !p.Fold) {
+			continue
+		}
+
+		p.HoleCards = []string{}
+		p.Combination = nil
+	}
+}
@@ -0,0 +1,150 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_ActionTimeout(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// Jeffrey 斷線，只會自動準備與支付盲注，不會做下注動作
+	disconnectedPlayerID := "Jeffrey"
+	maxActionTimeoutCount := 2
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	timeoutCounts := make([]int, 0)
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTablePlayerActionTimeout = func(competitionID, tableID string, playerState *pokertable.TablePlayerState) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, disconnectedPlayerID, playerState.PlayerID, "only disconnected player should time out")
+		timeoutCounts = append(timeoutCounts, playerState.ActionTimeoutCount)
+		if playerState.ActionTimeoutCount < maxActionTimeoutCount {
+			assert.False(t, playerState.IsSitOut, "player should not sit out yet")
+			return
+		}
+
+		assert.True(t, playerState.IsSitOut, "player should sit out")
+		wg.Done()
+	}
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+				return
+			}
+
+			if playerID == disconnectedPlayerID {
+				return
+			}
+
+			if funk.Contains(actions, "check") {
+				assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			} else if funk.Contains(actions, "call") {
+				assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+			}
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.ActionTime = 1
+	tableSetting.Meta.ActionTimeoutEnforced = true
+	tableSetting.Meta.MaxActionTimeoutCount = maxActionTimeoutCount
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+
+	mu.Lock()
+	assert.Equal(t, []int{1, 2}, timeoutCounts)
+	mu.Unlock()
+
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
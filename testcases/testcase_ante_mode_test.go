@@ -0,0 +1,261 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+/*
+runAnteModeGame 以指定前注模式打一手 (SB 翻牌前棄牌，其他玩家跟注到攤牌)
+  - 回傳第一手的結算結果與玩家動作
+*/
+func runAnteModeGame(t *testing.T, blind pokertable.TableBlindState, redeemChips int64) (pokertable.TableGameResult, []pokertable.TablePlayerGameAction) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isSettled := false
+	var gameResult pokertable.TableGameResult
+	gameActions := make([]pokertable.TablePlayerGameAction, 0)
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if playerID == findPlayerID(table, "sb") && funk.Contains(actions, "fold") {
+				assert.Nil(t, tableEngine.PlayerFold(playerID), fmt.Sprintf("%s fold error", playerID))
+			} else if funk.Contains(actions, "check") {
+				assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			} else if funk.Contains(actions, "call") {
+				assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+			}
+		}
+	}
+	tableEngineCallbacks.OnGamePlayerActionUpdated = func(gameAction pokertable.TablePlayerGameAction) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if gameAction.GameCount == 1 {
+			gameActions = append(gameActions, gameAction)
+		}
+	}
+	tableEngineCallbacks.OnGameSettled = func(result pokertable.TableGameResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// 只檢查第一手
+		if isSettled {
+			return
+		}
+		isSettled = true
+		gameResult = result
+		wg.Done()
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.MaxDuration = 60
+	tableSetting.Blind = blind
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+
+	mu.Lock()
+	defer mu.Unlock()
+	return gameResult, gameActions
+}
+
+func findResultPlayerID(result pokertable.TableGameResult, position string) string {
+	for _, player := range result.Players {
+		if funk.ContainsString(player.Positions, position) {
+			return player.PlayerID
+		}
+	}
+	return ""
+}
+
+// assertTableAnteResult 檢查只有代付玩家支付前注，且前注併入主池
+func assertTableAnteResult(t *testing.T, result pokertable.TableGameResult, gameActions []pokertable.TablePlayerGameAction, payerPosition string, ante, payerWager int64) {
+	payerPlayerID := findResultPlayerID(result, payerPosition)
+	sbPlayerID := findResultPlayerID(result, pokertable.Position_SB)
+
+	// 前注 pay 動作只有代付玩家
+	anteActions := funk.Filter(gameActions, func(gameAction pokertable.TablePlayerGameAction) bool {
+		return gameAction.Action == "pay" && gameAction.Round == "ante"
+	}).([]pokertable.TablePlayerGameAction)
+	if assert.Len(t, anteActions, 1) {
+		assert.Equal(t, payerPlayerID, anteActions[0].PlayerID)
+		assert.Equal(t, ante, anteActions[0].Chips)
+	}
+
+	// 盲注 pay 動作為實際支付的盲注
+	for _, gameAction := range gameActions {
+		if gameAction.Action != "pay" || gameAction.Round == "ante" {
+			continue
+		}
+
+		switch {
+		case funk.ContainsString(gameAction.Positions, pokertable.Position_BB):
+			assert.Equal(t, int64(20), gameAction.Chips, "bb pay chips mismatch")
+		case funk.ContainsString(gameAction.Positions, pokertable.Position_SB):
+			assert.Equal(t, int64(10), gameAction.Chips, "sb pay chips mismatch")
+		}
+	}
+
+	// 玩家輸贏總和為 0，SB 只輸掉小盲
+	changed := int64(0)
+	for _, player := range result.Players {
+		changed += player.Changed
+		if player.PlayerID == sbPlayerID {
+			assert.Equal(t, int64(-10), player.Changed, "sb should lose the small blind")
+		}
+	}
+	assert.Equal(t, int64(0), changed, "total changed should be zero")
+
+	// 主池: SB 10 + 其他兩位各 20 + 代付前注
+	if !assert.NotEmpty(t, result.Pots) {
+		return
+	}
+	mainPot := result.Pots[0]
+	assert.Equal(t, 50+ante, mainPot.Total)
+	for _, contributor := range mainPot.Contributors {
+		switch contributor.PlayerID {
+		case payerPlayerID:
+			assert.Equal(t, payerWager+ante, contributor.Chips, "payer contribution mismatch")
+		case sbPlayerID:
+			assert.Equal(t, int64(10), contributor.Chips, "sb contribution mismatch")
+		default:
+			assert.Equal(t, int64(20), contributor.Chips, fmt.Sprintf("%s contribution mismatch", contributor.PlayerID))
+		}
+	}
+
+	awarded := int64(0)
+	for _, pot := range result.Pots {
+		for _, winner := range pot.Winners {
+			awarded += winner.Chips
+		}
+	}
+	assert.Equal(t, 50+ante, awarded, "all chips should be awarded")
+}
+
+func TestTableGame_BigBlindAnte(t *testing.T) {
+	blind := pokertable.TableBlindState{Level: 1, Ante: 30, AnteMode: pokertable.AnteMode_BigBlind, SB: 10, BB: 20}
+	result, gameActions := runAnteModeGame(t, blind, 15000)
+	assertTableAnteResult(t, result, gameActions, pokertable.Position_BB, 30, 20)
+}
+
+func TestTableGame_BigBlindAnte_ShortStack(t *testing.T) {
+	// 大盲籌碼不足以同時支付盲注與前注時優先支付盲注
+	blind := pokertable.TableBlindState{Level: 1, Ante: 2000, AnteMode: pokertable.AnteMode_BigBlind, SB: 10, BB: 20}
+	result, gameActions := runAnteModeGame(t, blind, 1000)
+	assertTableAnteResult(t, result, gameActions, pokertable.Position_BB, 980, 20)
+}
+
+func TestTableGame_ButtonAnte(t *testing.T) {
+	blind := pokertable.TableBlindState{Level: 1, Ante: 30, AnteMode: pokertable.AnteMode_Button, SB: 10, BB: 20}
+	result, gameActions := runAnteModeGame(t, blind, 15000)
+	assertTableAnteResult(t, result, gameActions, pokertable.Position_Dealer, 30, 20)
+}
+
+func TestTableGame_InvalidAnteMode(t *testing.T) {
+	manager := pokertable.NewManager()
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Blind.AnteMode = "unknown"
+	_, err := manager.CreateTable(pokertable.NewTableEngineOptions(), nil, tableSetting)
+	assert.ErrorIs(t, err, pokertable.ErrTableInvalidCreateSetting)
+
+	table, err := manager.CreateTable(pokertable.NewTableEngineOptions(), nil, NewDefaultTableSetting())
+	assert.Nil(t, err, "create table failed")
+	assert.ErrorIs(t, manager.UpdateAnteMode(table.ID, "unknown"), pokertable.ErrTableInvalidAnteMode)
+	assert.Nil(t, manager.UpdateAnteMode(table.ID, pokertable.AnteMode_BigBlind))
+
+	// 升盲不影響前注模式
+	assert.Nil(t, manager.UpdateBlind(table.ID, 2, 40, 0, 20, 40))
+	tableEngine, err := manager.GetTableEngine(table.ID)
+	if assert.Nil(t, err) {
+		assert.Equal(t, pokertable.AnteMode_BigBlind, tableEngine.GetTable().State.BlindState.AnteMode)
+	}
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
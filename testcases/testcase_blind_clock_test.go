@@ -0,0 +1,262 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/weedbox/pokertable"
+)
+
+type blindClockEvent struct {
+	event string
+	state pokertable.BlindClockState
+}
+
+func newTestBlindSchedule() pokertable.BlindSchedule {
+	return pokertable.BlindSchedule{
+		Levels: []pokertable.BlindLevel{
+			{Level: 1, Ante: 0, Dealer: 0, SB: 10, BB: 20, Duration: 2},
+			pokertable.NewBreakLevel(2),
+			{Level: 2, Ante: 5, Dealer: 0, SB: 20, BB: 40, Duration: 0},
+		},
+	}
+}
+
+func waitBlindClockEvent(t *testing.T, events chan blindClockEvent, timeout time.Duration) blindClockEvent {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(timeout):
+		t.Fatal("wait blind clock event timeout")
+	}
+	return blindClockEvent{}
+}
+
+func TestTableGame_BlindClock(t *testing.T) {
+	// given conditions
+	competitionID := uuid.New().String()
+	schedule := newTestBlindSchedule()
+
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableIDs := make([]string, 0)
+	for i := 0; i < 2; i++ {
+		tableSetting := NewDefaultTableSetting()
+		tableSetting.Meta.CompetitionID = competitionID
+		tableSetting.Meta.Mode = pokertable.CompetitionMode_MTT
+		table, err := manager.CreateTable(tableEngineOption, nil, tableSetting)
+		assert.Nil(t, err, "create table failed")
+		tableIDs = append(tableIDs, table.ID)
+	}
+
+	// 其他賽事的桌次不受影響
+	otherTable, err := manager.CreateTable(tableEngineOption, nil, NewDefaultTableSetting())
+	assert.Nil(t, err, "create other table failed")
+
+	// 時鐘在另一個 goroutine 更新桌次，經由快照讀取
+	assertTableBlind := func(blind pokertable.TableBlindState) {
+		for _, tableID := range tableIDs {
+			snapshot, err := manager.GetTableSnapshot(tableID)
+			if assert.Nil(t, err) {
+				assert.Equal(t, blind, *snapshot.Table.State.BlindState, fmt.Sprintf("table (%s) blind mismatch", tableID))
+			}
+		}
+	}
+
+	// 不合法的盲注結構
+	_, err = pokertable.NewBlindClock(manager, competitionID, pokertable.BlindSchedule{})
+	assert.ErrorIs(t, err, pokertable.ErrBlindScheduleEmpty)
+	_, err = pokertable.NewBlindClock(manager, competitionID, pokertable.BlindSchedule{Levels: []pokertable.BlindLevel{pokertable.NewBreakLevel(60)}})
+	assert.ErrorIs(t, err, pokertable.ErrBlindScheduleInvalidLevel)
+
+	events := make(chan blindClockEvent, 10)
+	clock, err := pokertable.NewBlindClock(manager, competitionID, schedule,
+		pokertable.WithBlindClockAnnounceBefore(1),
+		pokertable.WithBlindClockUpdated(func(event string, state pokertable.BlindClockState) {
+			events <- blindClockEvent{event: event, state: state}
+		}),
+		pokertable.WithBlindClockErrorUpdated(func(tableID string, err error) {
+			t.Log("[BlindClock] Error:", tableID, err)
+		}),
+	)
+	assert.Nil(t, err, "create blind clock failed")
+	assert.Equal(t, pokertable.BlindClockStatus_Created, clock.State().Status)
+
+	// Level 1
+	assert.Nil(t, clock.Start())
+	assert.ErrorIs(t, clock.Start(), pokertable.ErrBlindClockAlreadyStarted)
+	e := waitBlindClockEvent(t, events, time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_LevelStarted, e.event)
+	assert.Equal(t, 0, e.state.LevelIndex)
+	assert.Equal(t, schedule.Levels[0], e.state.CurrentLevel)
+	if assert.NotNil(t, e.state.NextLevel) {
+		assert.True(t, e.state.NextLevel.IsBreak())
+	}
+	assert.Equal(t, int64(2), e.state.RemainingSeconds)
+	assert.NotZero(t, e.state.LevelEndAt)
+	assertTableBlind(pokertable.TableBlindState{Level: 1, Ante: 0, Dealer: 0, SB: 10, BB: 20})
+
+	// 預告中場休息
+	e = waitBlindClockEvent(t, events, 2*time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_NextLevelAnnounced, e.event)
+	assert.Equal(t, 0, e.state.LevelIndex)
+	assert.Equal(t, int64(1), e.state.RemainingSeconds)
+
+	// 中場休息沿用上一個等級的籌碼量
+	e = waitBlindClockEvent(t, events, 2*time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_LevelStarted, e.event)
+	assert.Equal(t, 1, e.state.LevelIndex)
+	assert.True(t, e.state.CurrentLevel.IsBreak())
+	assertTableBlind(pokertable.TableBlindState{Level: -1, Ante: 0, Dealer: 0, SB: 10, BB: 20})
+
+	e = waitBlindClockEvent(t, events, 2*time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_NextLevelAnnounced, e.event)
+	assert.Equal(t, 1, e.state.LevelIndex)
+
+	// Level 2 (不會結束)
+	e = waitBlindClockEvent(t, events, 2*time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_LevelStarted, e.event)
+	assert.Equal(t, 2, e.state.LevelIndex)
+	assert.Nil(t, e.state.NextLevel)
+	assert.Zero(t, e.state.LevelEndAt)
+	assert.Zero(t, e.state.RemainingSeconds)
+	assertTableBlind(pokertable.TableBlindState{Level: 2, Ante: 5, Dealer: 0, SB: 20, BB: 40})
+
+	otherSnapshot, err := manager.GetTableSnapshot(otherTable.ID)
+	if assert.Nil(t, err) {
+		assert.Equal(t, pokertable.TableBlindState{Level: 1, Ante: 0, Dealer: 0, SB: 10, BB: 20}, *otherSnapshot.Table.State.BlindState)
+	}
+
+	clock.Stop()
+	e = waitBlindClockEvent(t, events, time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_Stopped, e.event)
+
+	for _, tableID := range append(tableIDs, otherTable.ID) {
+		assert.Nil(t, manager.ReleaseTable(tableID))
+	}
+}
+
+func TestTableGame_BlindClock_PauseResume(t *testing.T) {
+	competitionID := uuid.New().String()
+	schedule := pokertable.BlindSchedule{
+		Levels: []pokertable.BlindLevel{
+			{Level: 1, SB: 10, BB: 20, Duration: 2},
+			{Level: 2, SB: 20, BB: 40, Duration: 0},
+		},
+	}
+
+	manager := pokertable.NewManager()
+	events := make(chan blindClockEvent, 10)
+	clock, err := pokertable.NewBlindClock(manager, competitionID, schedule,
+		pokertable.WithBlindClockUpdated(func(event string, state pokertable.BlindClockState) {
+			events <- blindClockEvent{event: event, state: state}
+		}),
+	)
+	assert.Nil(t, err, "create blind clock failed")
+	assert.ErrorIs(t, clock.Pause(), pokertable.ErrBlindClockNotRunning)
+
+	assert.Nil(t, clock.Start())
+	e := waitBlindClockEvent(t, events, time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_LevelStarted, e.event)
+
+	// 暫停期間不會升盲，剩餘時間保留
+	assert.Nil(t, clock.Pause())
+	e = waitBlindClockEvent(t, events, time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_Paused, e.event)
+	assert.Equal(t, pokertable.BlindClockStatus_Paused, e.state.Status)
+	assert.Equal(t, int64(2), e.state.RemainingSeconds)
+	assert.Zero(t, e.state.LevelEndAt)
+
+	time.Sleep(2500 * time.Millisecond)
+	assert.Len(t, events, 0)
+	state := clock.State()
+	assert.Equal(t, 0, state.LevelIndex)
+	assert.Equal(t, int64(2), state.RemainingSeconds)
+
+	// 繼續後依剩餘時間升盲
+	assert.Nil(t, clock.Resume())
+	assert.ErrorIs(t, clock.Resume(), pokertable.ErrBlindClockNotPaused)
+	e = waitBlindClockEvent(t, events, time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_Resumed, e.event)
+	assert.Equal(t, pokertable.BlindClockStatus_Running, e.state.Status)
+
+	e = waitBlindClockEvent(t, events, 3*time.Second)
+	assert.Equal(t, pokertable.BlindClockEvent_LevelStarted, e.event)
+	assert.Equal(t, 1, e.state.LevelIndex)
+
+	clock.Stop()
+}
+
+func TestTableGame_BlindClock_ResumeAfterBreak(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	competitionID := uuid.New().String()
+	playerIDs := []string{"Fred", "Jeffrey"}
+	joinPlayers := make([]pokertable.JoinPlayer, 0)
+	for _, playerID := range playerIDs {
+		joinPlayers = append(joinPlayers, pokertable.JoinPlayer{PlayerID: playerID, RedeemChips: 1000, Seat: pokertable.UnsetValue})
+	}
+
+	// create manager & table
+	var mu sync.Mutex
+	isOpened := false
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.OpenGameTimeout = 1
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if isOpened {
+			return
+		}
+		isOpened = true
+		defer wg.Done()
+
+		// 中場休息結束後以新的盲注開局
+		assert.Equal(t, 2, table.State.BlindState.Level)
+		assert.Equal(t, 1, table.State.GameCount)
+	}
+	tableSetting := NewDefaultTableSetting(joinPlayers...)
+	tableSetting.Meta.CompetitionID = competitionID
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_MTT
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+	for _, playerID := range playerIDs {
+		assert.Nil(t, manager.PlayerJoin(table.ID, playerID), fmt.Sprintf("%s join error", playerID))
+	}
+
+	events := make(chan blindClockEvent, 10)
+	clock, err := pokertable.NewBlindClock(manager, competitionID, newTestBlindSchedule(),
+		pokertable.WithBlindClockUpdated(func(event string, state pokertable.BlindClockState) {
+			events <- blindClockEvent{event: event, state: state}
+		}),
+	)
+	assert.Nil(t, err, "create blind clock failed")
+	assert.Nil(t, clock.Start())
+	waitBlindClockEvent(t, events, time.Second)
+
+	// 中場休息: 桌次開始後暫停
+	e := waitBlindClockEvent(t, events, 3*time.Second)
+	assert.True(t, e.state.CurrentLevel.IsBreak())
+	assert.Nil(t, manager.StartTableGame(table.ID))
+	assert.Nil(t, manager.PauseTable(table.ID))
+	snapshot, err := manager.GetTableSnapshot(table.ID)
+	if assert.Nil(t, err) {
+		assert.True(t, snapshot.Table.ShouldPause())
+	}
+
+	wg.Wait()
+	clock.Stop()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
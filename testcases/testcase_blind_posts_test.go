@@ -0,0 +1,51 @@
+package testcases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestNativeGameBackend_PayBlindPosts_ShortStack(t *testing.T) {
+	backend := pokertable.NewNativeGameBackend()
+
+	// given conditions
+	opts := pokerface.NewStardardGameOptions()
+	opts.Deck = pokerface.NewStandardDeckCards()
+	opts.Players = []*pokerface.PlayerSetting{
+		{Bankroll: 1000, Positions: []string{"dealer"}},
+		{Bankroll: 1000, Positions: []string{"sb"}},
+		{Bankroll: 1000, Positions: []string{"bb"}},
+		{Bankroll: 12, Positions: []string{}}, // 錯過大小盲回座，籌碼不足以補滿
+	}
+
+	gs, err := backend.CreateGame(opts)
+	assert.Nil(t, err, "create game failed")
+	gs, err = backend.ReadyForAll(gs)
+	assert.Nil(t, err, "ready for all failed")
+	assert.Equal(t, "BlindsRequested", gs.Status.CurrentEvent)
+
+	posts := map[int]pokertable.GameBlindPost{
+		3: {Live: 10, Dead: 5},
+	}
+	gs, err = backend.PayBlindPosts(gs, posts)
+	assert.Nil(t, err, "pay blind posts failed")
+
+	// 優先補活大盲，死小盲只補剩餘籌碼
+	poster := gs.GetPlayer(3)
+	assert.Equal(t, int64(10), poster.Wager, "live bb should be posted first")
+	assert.Equal(t, int64(0), poster.StackSize, "short stack should post what they can")
+	assert.Equal(t, int64(10), poster.Bankroll, "dead sb should be deducted from bankroll")
+	assert.Equal(t, "allin", poster.DidAction)
+	assert.Equal(t, int64(12), gs.Status.CurrentRoundPot, "dead and live blinds should be counted in round pot")
+	assert.Equal(t, int64(10), gs.Status.CurrentWager)
+
+	gs, err = backend.PayBlinds(gs)
+	assert.Nil(t, err, "pay blinds failed")
+	assert.Equal(t, int64(27), gs.Status.CurrentRoundPot)
+
+	_, err = backend.PayBlindPosts(gs, posts)
+	assert.ErrorIs(t, err, pokerface.ErrInvalidAction, "blind posts should be paid before blinds")
+}
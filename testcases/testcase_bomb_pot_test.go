@@ -0,0 +1,259 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+/*
+runBombPotGame 現金桌打一手 (翻牌後皆過牌或跟注到攤牌)
+  - voterIDs: 開局前投票同意 Bomb Pot 的玩家
+  - 回傳第一手的結算結果、玩家動作與是否曾要求支付盲注
+*/
+func runBombPotGame(t *testing.T, setting pokertable.TableBombPotSetting, voterIDs []string) (pokertable.TableGameResult, []pokertable.TablePlayerGameAction, bool) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isSettled := false
+	isBlindsRequested := false
+	var gameResult pokertable.TableGameResult
+	gameActions := make([]pokertable.TablePlayerGameAction, 0)
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying || table.State.GameCount != 1 {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_AnteRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerPay(playerID, table.State.GameState.Meta.Ante), fmt.Sprintf("%s pay ante error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			mu.Lock()
+			isBlindsRequested = true
+			mu.Unlock()
+
+			blind := table.State.BlindState
+
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if funk.Contains(actions, "check") {
+				assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			} else if funk.Contains(actions, "call") {
+				assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+			}
+		}
+	}
+	tableEngineCallbacks.OnGamePlayerActionUpdated = func(gameAction pokertable.TablePlayerGameAction) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if gameAction.GameCount == 1 {
+			gameActions = append(gameActions, gameAction)
+		}
+	}
+	tableEngineCallbacks.OnGameSettled = func(result pokertable.TableGameResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// 只檢查第一手
+		if isSettled {
+			return
+		}
+		isSettled = true
+		gameResult = result
+		wg.Done()
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+
+		// 開局前投票
+		for _, playerID := range voterIDs {
+			assert.Nil(t, tableEngine.PlayerVoteBombPot(playerID), fmt.Sprintf("%s vote bomb pot error", playerID))
+		}
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.MaxDuration = 60
+	tableSetting.Meta.BombPot = setting
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+
+	mu.Lock()
+	defer mu.Unlock()
+	return gameResult, gameActions, isBlindsRequested
+}
+
+// assertBombPotResult 檢查每位玩家支付 Bomb Pot 籌碼、不支付盲注且沒有翻牌前下注
+func assertBombPotResult(t *testing.T, result pokertable.TableGameResult, gameActions []pokertable.TablePlayerGameAction, isBlindsRequested bool, amount int64) {
+	assert.Equal(t, amount, result.BombPot)
+	assert.False(t, isBlindsRequested, "bomb pot should not request blinds")
+	assert.Len(t, result.Board, 5)
+
+	for _, gameAction := range gameActions {
+		if gameAction.Action == "pay" {
+			assert.Equal(t, "ante", gameAction.Round, fmt.Sprintf("%s should only pay the bomb pot", gameAction.PlayerID))
+			assert.Equal(t, amount, gameAction.Chips, fmt.Sprintf("%s bomb pot chips mismatch", gameAction.PlayerID))
+		} else {
+			assert.NotEqual(t, pokertable.GameRound_Preflop, gameAction.Round, fmt.Sprintf("%s should not act preflop", gameAction.PlayerID))
+		}
+	}
+	assert.Len(t, funk.Filter(gameActions, func(gameAction pokertable.TablePlayerGameAction) bool {
+		return gameAction.Action == "pay"
+	}), len(result.Players))
+
+	// 主池: 每位玩家各支付 Bomb Pot 籌碼 (翻牌後皆過牌)
+	if assert.NotEmpty(t, result.Pots) {
+		mainPot := result.Pots[0]
+		assert.Equal(t, amount*int64(len(result.Players)), mainPot.Total)
+		for _, contributor := range mainPot.Contributors {
+			assert.Equal(t, amount, contributor.Chips, fmt.Sprintf("%s contribution mismatch", contributor.PlayerID))
+		}
+	}
+
+	changed := int64(0)
+	for _, player := range result.Players {
+		changed += player.Changed
+	}
+	assert.Equal(t, int64(0), changed, "total changed should be zero")
+}
+
+func TestTableGame_BombPot_Interval(t *testing.T) {
+	setting := pokertable.TableBombPotSetting{Amount: 100, Interval: 1}
+	result, gameActions, isBlindsRequested := runBombPotGame(t, setting, nil)
+	assertBombPotResult(t, result, gameActions, isBlindsRequested, 100)
+}
+
+func TestTableGame_BombPot_Vote(t *testing.T) {
+	setting := pokertable.TableBombPotSetting{Amount: 100, VoteEnabled: true}
+	result, gameActions, isBlindsRequested := runBombPotGame(t, setting, []string{"Fred", "Jeffrey", "Chuck"})
+	assertBombPotResult(t, result, gameActions, isBlindsRequested, 100)
+}
+
+func TestTableGame_BombPot_VoteNotPassed(t *testing.T) {
+	// 未全數同意時為一般牌局
+	setting := pokertable.TableBombPotSetting{Amount: 100, VoteEnabled: true}
+	result, _, isBlindsRequested := runBombPotGame(t, setting, []string{"Fred", "Jeffrey"})
+	assert.Equal(t, int64(0), result.BombPot)
+	assert.True(t, isBlindsRequested, "normal game should request blinds")
+}
+
+func TestTableGame_BombPot_InvalidSetting(t *testing.T) {
+	manager := pokertable.NewManager()
+
+	// 只開放現金桌
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.BombPot = pokertable.TableBombPotSetting{Amount: 100, Interval: 5}
+	_, err := manager.CreateTable(pokertable.NewTableEngineOptions(), nil, tableSetting)
+	assert.ErrorIs(t, err, pokertable.ErrTableInvalidCreateSetting)
+
+	// 需設定觸發方式
+	tableSetting = NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.BombPot = pokertable.TableBombPotSetting{Amount: 100}
+	_, err = manager.CreateTable(pokertable.NewTableEngineOptions(), nil, tableSetting)
+	assert.ErrorIs(t, err, pokertable.ErrTableInvalidCreateSetting)
+
+	// 未開放投票
+	tableSetting = NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.BombPot = pokertable.TableBombPotSetting{Amount: 100, Interval: 5}
+	table, err := manager.CreateTable(pokertable.NewTableEngineOptions(), nil, tableSetting)
+	if assert.Nil(t, err, "create table failed") {
+		assert.ErrorIs(t, manager.PlayerVoteBombPot(table.ID, "Fred"), pokertable.ErrTableBombPotNotAllowed)
+		assert.Nil(t, manager.ReleaseTable(table.ID))
+	}
+
+	// 尚未設定開局時不可投票
+	tableSetting = NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.BombPot = pokertable.TableBombPotSetting{Amount: 100, VoteEnabled: true}
+	table, err = manager.CreateTable(pokertable.NewTableEngineOptions(), nil, tableSetting)
+	if assert.Nil(t, err, "create table failed") {
+		tableEngine, err := manager.GetTableEngine(table.ID)
+		assert.Nil(t, err, "get table engine failed")
+		assert.Nil(t, tableEngine.PlayerReserve(pokertable.JoinPlayer{PlayerID: "Fred", RedeemChips: 15000, Seat: pokertable.UnsetValue}))
+		assert.ErrorIs(t, manager.PlayerVoteBombPot(table.ID, "Fred"), pokertable.ErrTableBombPotVoteClosed)
+		assert.Nil(t, manager.ReleaseTable(table.ID))
+	}
+}
@@ -0,0 +1,195 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_Cash_PostDeadBlinds(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// Chuck 第 1 手後暫離，暫離 3 手 (一定會錯過大盲與小盲) 後回座，下一手立即補盲注
+	sitOutPlayerID := "Chuck"
+	sitOutGameCount := 1
+	sitInGameCount := 4
+	returnGameCount := sitInGameCount + 1
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	handledGames := make(map[int]bool)
+	isPostedLiveBBChecked := false
+	isBBOptionChecked := false
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			// 同一個遊戲狀態只處理一次
+			mu.Lock()
+			if handledStates[table.State.GameState.UpdatedAt] {
+				mu.Unlock()
+				return
+			}
+			handledStates[table.State.GameState.UpdatedAt] = true
+			mu.Unlock()
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerIdx := range table.State.GamePlayerIndexes {
+					playerID := table.State.PlayerStates[playerIdx].PlayerID
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				// 暫離玩家的 SB 位置為空
+				if sbPlayerID := findPlayerID(table, "sb"); sbPlayerID != "" {
+					assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+				}
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				playerID, actions := currentPlayerMove(table)
+
+				if table.State.GameCount == returnGameCount && table.State.GameState.Status.Round == "preflop" && len(table.State.GameBlindPosts) > 0 {
+					// 補活大盲的玩家視同已跟注大盲
+					if playerID == sitOutPlayerID && !isPostedLiveBBChecked {
+						isPostedLiveBBChecked = true
+						assert.Contains(t, actions, "check", fmt.Sprintf("%s should be able to check after posting live bb", playerID))
+					}
+
+					// 大盲依然保有行動權
+					if playerID == findPlayerID(table, "bb") {
+						isBBOptionChecked = true
+					}
+				}
+
+				if funk.Contains(actions, "pass") {
+					assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+				} else if funk.Contains(actions, "check") {
+					assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+				} else if funk.Contains(actions, "call") {
+					assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+				}
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			gameCount := table.State.GameCount
+
+			// 同一手只處理一次
+			mu.Lock()
+			if handledGames[gameCount] {
+				mu.Unlock()
+				return
+			}
+			handledGames[gameCount] = true
+			mu.Unlock()
+
+			// 死盲併入主池，總籌碼不變
+			totalBankroll := int64(0)
+			for _, playerState := range table.State.PlayerStates {
+				totalBankroll += playerState.Bankroll
+			}
+			assert.Equal(t, redeemChips*int64(len(playerIDs)), totalBankroll, fmt.Sprintf("game %d: total bankroll changed", gameCount))
+
+			playerIdx := table.FindPlayerIdx(sitOutPlayerID)
+			playerState := table.State.PlayerStates[playerIdx]
+			gamePlayerIdx := table.FindGamePlayerIdx(sitOutPlayerID)
+
+			switch {
+			case gameCount == sitOutGameCount:
+				go func() {
+					assert.Nil(t, tableEngine.PlayerSitOut(sitOutPlayerID), fmt.Sprintf("%s sit out error", sitOutPlayerID))
+				}()
+			case gameCount == sitInGameCount:
+				assert.Equal(t, pokertable.UnsetValue, gamePlayerIdx, fmt.Sprintf("%s should not participate", sitOutPlayerID))
+				assert.True(t, playerState.MissedSB, fmt.Sprintf("%s should miss sb", sitOutPlayerID))
+				assert.True(t, playerState.MissedBB, fmt.Sprintf("%s should miss bb", sitOutPlayerID))
+				go func() {
+					assert.Nil(t, tableEngine.PlayerSitIn(sitOutPlayerID), fmt.Sprintf("%s sit in error", sitOutPlayerID))
+				}()
+			case gameCount == returnGameCount:
+				// 立即補盲注回到牌局
+				assert.NotEqual(t, pokertable.UnsetValue, gamePlayerIdx, fmt.Sprintf("%s should come back", sitOutPlayerID))
+				assert.False(t, playerState.MissedSB)
+				assert.False(t, playerState.MissedBB)
+
+				if funk.Contains(playerState.Positions, pokertable.Position_BB) {
+					// 回座即輪到大盲，不需補盲注
+					assert.Empty(t, table.State.GameBlindPosts)
+				} else {
+					assert.Equal(t, pokertable.GameBlindPost{Live: table.State.BlindState.BB, Dead: table.State.BlindState.SB}, table.State.GameBlindPosts[gamePlayerIdx])
+					assert.True(t, isPostedLiveBBChecked, "posted player should act preflop")
+					assert.True(t, isBBOptionChecked, "bb should keep the option")
+				}
+				wg.Done()
+			}
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.MaxDuration = 600
+	tableSetting.Meta.PostDeadBlinds = true
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
@@ -0,0 +1,193 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_EventBus(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	manager := pokertable.NewManager()
+
+	// 跨桌次訂閱: 非同步接收所有事件
+	globalEventTypes := make(map[string]int)
+	globalTableIDs := make(map[string]bool)
+	globalSettled := false
+	globalSub := manager.Subscribe(func(event pokertable.TableEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		globalEventTypes[event.Type]++
+		globalTableIDs[event.TableID] = true
+
+		if event.Type == pokertable.TableEventType_GameSettled && !globalSettled {
+			globalSettled = true
+			assert.NotNil(t, event.GameResult)
+			wg.Done()
+		}
+	}, pokertable.WithTableEventAsync(1024))
+	defer globalSub.Unsubscribe()
+
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.MaxDuration = 60
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// 第一個訂閱者: 驅動牌局
+	tableEngine.Subscribe(func(event pokertable.TableEvent) {
+		table := event.Table
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		gameEvent, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch gameEvent {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if funk.Contains(actions, "check") {
+				assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			} else if funk.Contains(actions, "call") {
+				assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+			}
+		}
+	}, pokertable.WithTableEventTypes(pokertable.TableEventType_TableUpdated))
+
+	// 第二個訂閱者: 只接收玩家動作，收到第一個動作後取消訂閱
+	gameActionCount := 0
+	var actionSub pokertable.TableEventSubscription
+	actionSub = tableEngine.Subscribe(func(event pokertable.TableEvent) {
+		assert.Equal(t, pokertable.TableEventType_GamePlayerActionUpdated, event.Type)
+		assert.NotNil(t, event.GameAction)
+
+		mu.Lock()
+		gameActionCount++
+		mu.Unlock()
+		actionSub.Unsubscribe()
+	}, pokertable.WithTableEventTypes(pokertable.TableEventType_GamePlayerActionUpdated))
+
+	// 第三個訂閱者: 緩衝區很小且處理很慢，多出來的事件會被丟棄
+	slowSub := tableEngine.Subscribe(func(event pokertable.TableEvent) {
+		time.Sleep(time.Millisecond * 200)
+	}, pokertable.WithTableEventAsync(1))
+	defer slowSub.Unsubscribe()
+
+	// 第四個訂閱者: 與第一個訂閱者同時接收桌次更新並確認結算
+	settled := false
+	tableEngine.Subscribe(func(event pokertable.TableEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if settled {
+			return
+		}
+		settled = true
+		defer wg.Done()
+
+		assert.Equal(t, table.ID, event.TableID)
+		assert.Equal(t, table.Meta.CompetitionID, event.CompetitionID)
+		assert.Equal(t, 1, event.GameResult.GameCount)
+	}, pokertable.WithTableEventTypes(pokertable.TableEventType_GameSettled))
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, 1, gameActionCount, "unsubscribed handler should receive only one event")
+	assert.Greater(t, slowSub.DroppedCount(), int64(0), "slow subscriber should drop events")
+	assert.Equal(t, map[string]bool{table.ID: true}, globalTableIDs)
+	for _, eventType := range []string{
+		pokertable.TableEventType_TableUpdated,
+		pokertable.TableEventType_TableStateUpdated,
+		pokertable.TableEventType_TablePlayerReserved,
+		pokertable.TableEventType_GamePlayerActionUpdated,
+		pokertable.TableEventType_ReadyOpenFirstTableGame,
+		pokertable.TableEventType_GameSettled,
+	} {
+		assert.Greater(t, globalEventTypes[eventType], 0, fmt.Sprintf("global subscriber should receive %s", eventType))
+	}
+}
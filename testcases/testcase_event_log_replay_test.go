@@ -0,0 +1,190 @@
+package testcases
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_EventLog_Replay(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// event log
+	var buf bytes.Buffer
+	eventLogger := pokertable.NewJSONTableEventLogger(&buf)
+
+	// record table json of every update serial
+	var mu sync.Mutex
+	tableJSONs := make(map[int64]string)
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var settledOnce sync.Once
+	manager := pokertable.NewManager(pokertable.WithManagerEventLogger(eventLogger))
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		cloneTable, err := table.Clone()
+		assert.Nil(t, err)
+		tableJSON, _ := cloneTable.GetJSON()
+
+		mu.Lock()
+		tableJSONs[cloneTable.UpdateSerial] = tableJSON
+		mu.Unlock()
+
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerID := range playerIDs {
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				sbPlayerID := findPlayerID(table, "sb")
+				assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				playerID, actions := currentPlayerMove(table)
+				if funk.Contains(actions, "allin") {
+					assert.Nil(t, tableEngine.PlayerAllin(playerID), fmt.Sprintf("%s allin error", playerID))
+				}
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			settledOnce.Do(func() {
+				wg.Done()
+			})
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, NewDefaultTableSetting())
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	err = tableEngine.StartTableGame()
+	assert.Nil(t, err)
+
+	wg.Wait()
+
+	// stop table & read event log
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+	log, err := pokertable.ReadTableEventLog(bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err, "read event log failed")
+
+	commands := make([]string, 0)
+	gameStateCount := 0
+	for _, entry := range log {
+		switch entry.Type {
+		case pokertable.TableEventLogType_Command:
+			commands = append(commands, entry.Name)
+		case pokertable.TableEventLogType_GameState:
+			assert.NotNil(t, entry.GameState)
+			gameStateCount++
+		}
+	}
+	assert.Contains(t, commands, "CreateTable")
+	assert.Contains(t, commands, "PlayerReserve")
+	assert.Contains(t, commands, "PlayerJoin")
+	assert.Contains(t, commands, "StartTableGame")
+	assert.Contains(t, commands, "PlayerAllin")
+	assert.Contains(t, commands, "Timeout", "open game timeout should be recorded")
+	assert.Greater(t, gameStateCount, 0)
+
+	// replay to the last update serial (every update serial is checked against the log)
+	mu.Lock()
+	lastSerial := int64(0)
+	for updateSerial := range tableJSONs {
+		if updateSerial > lastSerial {
+			lastSerial = updateSerial
+		}
+	}
+	var expected pokertable.Table
+	assert.Nil(t, json.Unmarshal([]byte(tableJSONs[lastSerial]), &expected))
+	mu.Unlock()
+
+	// 計時器依紀錄觸發，重播不需等待開局的逾時時間 (OpenGameTimeout)
+	replayStartAt := time.Now()
+	replayedTable, err := pokertable.Replay(log, table.ID, lastSerial)
+	assert.Less(t, time.Since(replayStartAt), time.Second*2, "replay should not wait for real timers")
+	if assert.Nil(t, err, fmt.Sprintf("replay #%d failed", lastSerial)) {
+		assert.Equal(t, lastSerial, replayedTable.UpdateSerial)
+		assert.Equal(t, expected.State.GameCount, replayedTable.State.GameCount)
+		assert.Equal(t, expected.State.Status, replayedTable.State.Status)
+		assert.Equal(t, expected.State.GameState.GameID, replayedTable.State.GameState.GameID)
+		assert.Equal(t, expected.State.GameState.Meta.Deck, replayedTable.State.GameState.Meta.Deck)
+		for idx, player := range expected.State.PlayerStates {
+			assert.Equal(t, player.PlayerID, replayedTable.State.PlayerStates[idx].PlayerID)
+			assert.Equal(t, player.Seat, replayedTable.State.PlayerStates[idx].Seat)
+			assert.Equal(t, player.Bankroll, replayedTable.State.PlayerStates[idx].Bankroll)
+		}
+	}
+
+	// 指令執行結果與紀錄不同
+	tamperedLog := make([]pokertable.TableEventLogEntry, len(log))
+	copy(tamperedLog, log)
+	for idx, entry := range tamperedLog {
+		if entry.Type == pokertable.TableEventLogType_Command && entry.Name == "PlayerAllin" {
+			tamperedLog[idx].Error = pokertable.ErrTablePlayerInvalidGameAction.Error()
+			break
+		}
+	}
+	_, err = pokertable.Replay(tamperedLog, table.ID, lastSerial)
+	assert.ErrorIs(t, err, pokertable.ErrEventLogReplayMismatch)
+
+	_, err = pokertable.Replay(log, table.ID, lastSerial+1000)
+	assert.ErrorIs(t, err, pokertable.ErrEventLogUpdateSerialNotFound)
+}
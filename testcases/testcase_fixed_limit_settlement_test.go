@@ -0,0 +1,193 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_FixedLimit_River_Settlement(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raiseCap int
+		raises   int
+	}{
+		{name: "default_raise_cap", raiseCap: 0, raises: pokertable.DefaultFixedLimitRaiseCap},
+		{name: "single_raise_cap", raiseCap: 1, raises: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			wg.Add(1)
+
+			// given conditions
+			playerIDs := []string{"Fred", "Jeffrey", "Chuck", "Lottie"}
+			redeemChips := int64(15000)
+			players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+				return pokertable.JoinPlayer{
+					PlayerID:    playerID,
+					RedeemChips: redeemChips,
+					Seat:        pokertable.UnsetValue,
+				}
+			}).([]pokertable.JoinPlayer)
+
+			// create manager & table
+			var tableEngine pokertable.TableEngine
+			var settledOnce sync.Once
+			var mu sync.Mutex
+			roundRaises := make(map[string][]int64)
+			manager := pokertable.NewManager()
+			tableEngineOption := pokertable.NewTableEngineOptions()
+			tableEngineOption.GameContinueInterval = 1
+			tableEngineOption.OpenGameTimeout = 2
+			tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+			tableEngineCallbacks.OnGamePlayerActionUpdated = func(gameAction pokertable.TablePlayerGameAction) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if funk.ContainsString([]string{pokertable.WagerAction_Bet, pokertable.WagerAction_Raise}, gameAction.Action) {
+					roundRaises[gameAction.Round] = append(roundRaises[gameAction.Round], gameAction.Chips)
+				}
+			}
+			tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+				switch table.State.Status {
+				case pokertable.TableStateStatus_TableGameOpened:
+					DebugPrintTableGameOpened(*table)
+				case pokertable.TableStateStatus_TableGamePlaying:
+					t.Logf("[%s] %s:", table.State.GameState.Status.Round, table.State.GameState.Status.CurrentEvent)
+					event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+					if !ok {
+						return
+					}
+
+					switch event {
+					case pokerface.GameEvent_ReadyRequested:
+						for _, playerID := range playerIDs {
+							assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+						}
+					case pokerface.GameEvent_BlindsRequested:
+						blind := table.State.BlindState
+
+						// pay sb
+						sbPlayerID := findPlayerID(table, "sb")
+						assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+						// pay bb
+						bbPlayerID := findPlayerID(table, "bb")
+						assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+					case pokerface.GameEvent_RoundStarted:
+						// 下注/加注量由引擎決定，帶入的籌碼量會被忽略
+						chips := int64(1)
+						playerID, actions := currentPlayerMove(table)
+						limit := table.State.WagerLimit
+						if funk.Contains(actions, "bet") {
+							t.Logf(fmt.Sprintf("%s's move: bet", playerID))
+							assert.Nil(t, tableEngine.PlayerBet(playerID, chips), fmt.Sprintf("%s bet error", playerID))
+						} else if funk.Contains(actions, "raise") && limit != nil && !limit.IsRaiseCapped {
+							t.Logf(fmt.Sprintf("%s's move: raise", playerID))
+							assert.Nil(t, tableEngine.PlayerRaise(playerID, chips), fmt.Sprintf("%s raise error", playerID))
+						} else if funk.Contains(actions, "call") {
+							if funk.Contains(actions, "raise") {
+								assert.ErrorIs(t, tableEngine.PlayerRaise(playerID, chips), pokertable.ErrTablePlayerRaiseCapReached)
+								assert.ErrorIs(t, tableEngine.PlayerAllin(playerID), pokertable.ErrTablePlayerRaiseCapReached)
+							}
+							t.Logf(fmt.Sprintf("%s's move: call", playerID))
+							assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+						} else if funk.Contains(actions, "check") {
+							t.Logf(fmt.Sprintf("%s's move: check", playerID))
+							assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+						}
+					}
+				case pokertable.TableStateStatus_TableGameSettled:
+					settledOnce.Do(func() {
+						mu.Lock()
+						defer mu.Unlock()
+
+						bb := table.State.BlindState.BB
+
+						// check wagers: 每條街一次下注 + N 次加注，皆為固定量
+						for _, round := range []string{pokertable.GameRound_Preflop, pokertable.GameRound_Flop, pokertable.GameRound_Turn, pokertable.GameRound_River} {
+							betSize := pokertable.FixedLimitBetSize(bb, round)
+							expected := make([]int64, 0)
+							for level := 1; level <= tc.raises; level++ {
+								if round == pokertable.GameRound_Preflop {
+									// 大盲視為第一次下注，因此只有加注
+									expected = append(expected, betSize*int64(level+1))
+								} else if level == 1 {
+									expected = append(expected, betSize, betSize*2)
+								} else {
+									expected = append(expected, betSize*int64(level+1))
+								}
+							}
+							assert.Equal(t, expected, roundRaises[round], fmt.Sprintf("invalid %s wagers", round))
+						}
+
+						// check results
+						gs := table.State.GameState
+						assert.NotNil(t, gs.Result, "invalid game result")
+						assert.Equal(t, 1, table.State.GameCount)
+
+						// 每位玩家投入: 小注 (1 + N) * 2 條街 + 大注 (1 + N) * 2 條街
+						contribution := bb * int64(tc.raises+1) * 6
+						total := int64(0)
+						for _, playerResult := range gs.Result.Players {
+							playerIdx := table.State.GamePlayerIndexes[playerResult.Idx]
+							player := table.State.PlayerStates[playerIdx]
+							assert.Equal(t, playerResult.Final, player.Bankroll)
+							assert.Equal(t, contribution, gs.GetPlayer(playerResult.Idx).Pot, "invalid player contribution")
+							total += playerResult.Final
+						}
+						assert.Equal(t, redeemChips*int64(len(playerIDs)), total)
+
+						DebugPrintTableGameSettled(*table)
+						wg.Done()
+					})
+				}
+			}
+			tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+				t.Log("[Table] Error:", err)
+			}
+			tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+				participants := map[string]int{}
+				for idx, p := range players {
+					participants[p.PlayerID] = idx
+				}
+				tableEngine.SetUpTableGame(gameCount, participants)
+			}
+			tableSetting := NewDefaultTableSetting()
+			tableSetting.Meta.BettingStructure = pokertable.BettingStructure_FixedLimit
+			tableSetting.Meta.FixedLimitRaiseCap = tc.raiseCap
+			table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+			assert.Nil(t, err, "create table failed")
+
+			// get table engine
+			tableEngine, err = manager.GetTableEngine(table.ID)
+			assert.Nil(t, err, "get table engine failed")
+
+			// players buy in
+			for _, joinPlayer := range players {
+				assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+				go func(player pokertable.JoinPlayer) {
+					time.Sleep(time.Microsecond * 10)
+					assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+				}(joinPlayer)
+			}
+
+			// Start game
+			time.Sleep(time.Microsecond * 100)
+			err = tableEngine.StartTableGame()
+			assert.Nil(t, err)
+
+			wg.Wait()
+			assert.Nil(t, manager.ReleaseTable(table.ID))
+		})
+	}
+}
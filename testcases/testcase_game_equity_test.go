@@ -0,0 +1,157 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_AllinEquity(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// 翻牌前全下，之後每輪下注結束都要有勝率
+	equityRounds := make([]string, 0)
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isSettled := false
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if funk.Contains(actions, "allin") {
+				assert.Nil(t, tableEngine.PlayerAllin(playerID), fmt.Sprintf("%s allin error", playerID))
+			}
+		case pokerface.GameEvent_RoundClosed:
+			gameEquity := table.State.GameEquity
+			if !assert.NotNil(t, gameEquity, "game equity should be published after betting is closed") {
+				return
+			}
+
+			assert.Equal(t, table.State.GameState.Status.Round, gameEquity.Round)
+			assert.ElementsMatch(t, table.State.GameState.Status.Board, gameEquity.Board)
+			assert.Equal(t, len(gameEquity.Board) > 0, gameEquity.IsExhaustive, "should enumerate after the flop")
+			assert.Greater(t, gameEquity.Samples, 0)
+
+			equitySum := 0.0
+			equityPlayerIDs := make([]string, 0)
+			for _, playerEquity := range gameEquity.Players {
+				assert.InDelta(t, playerEquity.Equity, playerEquity.Win+playerEquity.Tie/2, 1e-9)
+				equitySum += playerEquity.Equity
+				equityPlayerIDs = append(equityPlayerIDs, playerEquity.PlayerID)
+			}
+			assert.ElementsMatch(t, playerIDs, equityPlayerIDs)
+			assert.InDelta(t, 1.0, equitySum, 1e-9, "total equity should be one")
+
+			mu.Lock()
+			equityRounds = append(equityRounds, gameEquity.Round)
+			mu.Unlock()
+		}
+	}
+	tableEngineCallbacks.OnGameSettled = func(result pokertable.TableGameResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// 只檢查第一手
+		if isSettled {
+			return
+		}
+		isSettled = true
+		defer wg.Done()
+
+		assert.Equal(t, []string{"preflop", "flop", "turn", "river"}, equityRounds)
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.MaxDuration = 60
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
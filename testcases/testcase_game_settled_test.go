@@ -0,0 +1,183 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_GameSettledResult(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// SB 翻牌前棄牌，其他玩家跟注到攤牌
+	var sbPlayerID string
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isSettled := false
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			mu.Lock()
+			sbPlayerID = findPlayerID(table, "sb")
+			mu.Unlock()
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if playerID == findPlayerID(table, "sb") && funk.Contains(actions, "fold") {
+				assert.Nil(t, tableEngine.PlayerFold(playerID), fmt.Sprintf("%s fold error", playerID))
+			} else if funk.Contains(actions, "check") {
+				assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			} else if funk.Contains(actions, "call") {
+				assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+			}
+		}
+	}
+	tableEngineCallbacks.OnGameSettled = func(result pokertable.TableGameResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// 只檢查第一手
+		if isSettled {
+			return
+		}
+		isSettled = true
+		defer wg.Done()
+
+		assert.Equal(t, 1, result.GameCount)
+		assert.NotEmpty(t, result.GameID)
+		assert.Len(t, result.Board, 5)
+		assert.Equal(t, int64(0), result.Rake)
+
+		// 玩家輸贏總和為 0，SB 輸掉小盲
+		assert.Len(t, result.Players, len(playerIDs))
+		changed := int64(0)
+		for _, player := range result.Players {
+			changed += player.Changed
+			assert.Equal(t, redeemChips+player.Changed, player.Final, fmt.Sprintf("%s final mismatch", player.PlayerID))
+			if player.PlayerID == sbPlayerID {
+				assert.True(t, player.IsFold, "sb should fold")
+				assert.Equal(t, int64(-10), player.Changed, "sb should lose the small blind")
+			}
+		}
+		assert.Equal(t, int64(0), changed, "total changed should be zero")
+
+		// 主池: SB 10 + 其他兩位各 20
+		if !assert.Len(t, result.Pots, 1) {
+			return
+		}
+		mainPot := result.Pots[0]
+		assert.Equal(t, int64(50), mainPot.Total)
+
+		contributed := int64(0)
+		for _, contributor := range mainPot.Contributors {
+			contributed += contributor.Chips
+			if contributor.PlayerID == sbPlayerID {
+				assert.Equal(t, int64(10), contributor.Chips, "sb contribution mismatch")
+			} else {
+				assert.Equal(t, int64(20), contributor.Chips, fmt.Sprintf("%s contribution mismatch", contributor.PlayerID))
+			}
+		}
+		assert.Len(t, mainPot.Contributors, len(playerIDs))
+		assert.Equal(t, mainPot.Total, contributed)
+
+		awarded := int64(0)
+		for _, winner := range mainPot.Winners {
+			awarded += winner.Chips
+			assert.NotEqual(t, sbPlayerID, winner.PlayerID, "folded player should not win")
+			assert.Equal(t, 1, winner.Rank)
+			assert.NotEmpty(t, winner.Combination)
+			assert.Len(t, winner.Cards, 5)
+		}
+		assert.Equal(t, mainPot.Total, awarded)
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.MaxDuration = 60
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
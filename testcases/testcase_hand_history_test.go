@@ -0,0 +1,271 @@
+package testcases
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_HandHistory_Export(t *testing.T) {
+	testCases := []struct {
+		rule          string
+		gameType      string
+		cards         []string
+		scriptedCards []string
+		flop          string
+		river         string
+		shows         string
+	}{
+		{
+			rule:     pokertable.CompetitionRule_Default,
+			gameType: "Hold'em No Limit (10/20)",
+			cards:    pokerface.NewStandardDeckCards(),
+			scriptedCards: []string{
+				"SA", "HA", "C2", "D7", "CK", "DK",
+				"S2", "S3", "H8", "D9",
+				"S4", "CT",
+				"H2", "S5",
+			},
+			flop:  "*** FLOP *** [3s 8h 9d]",
+			river: "*** RIVER *** [3s 8h 9d Tc] [5s]",
+			shows: "As Ah",
+		},
+		{
+			rule:     pokertable.CompetitionRule_ShortDeck,
+			gameType: "6+ Hold'em No Limit (10/20)",
+			cards:    pokerface.NewShortDeckCards(),
+			scriptedCards: []string{
+				"SA", "HA", "C7", "D8", "CK", "DK",
+				"S6", "H9", "HQ", "DJ",
+				"C6", "SJ",
+				"H6", "S7",
+			},
+			flop:  "*** FLOP *** [9h Qh Jd]",
+			river: "*** RIVER *** [9h Qh Jd Js] [7s]",
+			shows: "As Ah",
+		},
+		{
+			rule:     pokertable.CompetitionRule_Omaha,
+			gameType: "Omaha No Limit (10/20)",
+			cards:    pokerface.NewStandardDeckCards(),
+			scriptedCards: []string{
+				"SA", "HA", "S6", "H6", "C2", "D7", "H3", "H4", "CK", "DK", "C4", "D4",
+				"D2", "S3", "H8", "D9",
+				"C3", "CT",
+				"H5", "S5",
+			},
+			flop:  "*** FLOP *** [3s 8h 9d]",
+			river: "*** RIVER *** [3s 8h 9d Tc] [5s]",
+			shows: "As Ah 6s 6h",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.rule, func(t *testing.T) {
+			deck := append([]string{}, tc.scriptedCards...)
+			for _, card := range tc.cards {
+				if !funk.ContainsString(tc.scriptedCards, card) {
+					deck = append(deck, card)
+				}
+			}
+
+			handHistory, positionPlayerIDs, winnerPlayerID := playHandHistoryGame(t, tc.rule, deck)
+			t.Log("\n" + handHistory)
+
+			dealer := positionPlayerIDs[pokertable.Position_Dealer]
+			sb := positionPlayerIDs[pokertable.Position_SB]
+			bb := positionPlayerIDs[pokertable.Position_BB]
+
+			assert.Contains(t, handHistory, fmt.Sprintf("PokerStars Hand #1: %s", tc.gameType))
+			assert.Contains(t, handHistory, "Seat 1: P1 (15000 in chips)")
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: posts small blind 10", sb))
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: posts big blind 20", bb))
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: raises 40 to 60", dealer))
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: folds", sb))
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: calls 40", bb))
+			assert.Contains(t, handHistory, tc.flop)
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: bets 100", dealer))
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: calls 100", bb))
+			assert.Contains(t, handHistory, tc.river)
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: bets 200", bb))
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: calls 200", dealer))
+			assert.Contains(t, handHistory, "*** SHOW DOWN ***")
+			assert.Contains(t, handHistory, fmt.Sprintf("%s: shows [%s]", winnerPlayerID, tc.shows))
+			assert.Contains(t, handHistory, fmt.Sprintf("%s collected 730 from pot", winnerPlayerID))
+			assert.Contains(t, handHistory, "Total pot 730 | Rake 0")
+			assert.Contains(t, handHistory, fmt.Sprintf("%s (small blind) folded before Flop", sb))
+			assert.NotContains(t, handHistory, "Uncalled bet")
+		})
+	}
+}
+
+// playHandHistoryGame 依腳本打完一手，回傳牌局紀錄、各位置玩家 ID 與拿到第一手底牌 (game player 0) 的玩家 ID
+func playHandHistoryGame(t *testing.T, rule string, deck []string) (string, map[string]string, string) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"P1", "P2", "P3"}
+	redeemChips := int64(15000)
+	players := make([]pokertable.JoinPlayer, 0)
+	for seat, playerID := range playerIDs {
+		players = append(players, pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        seat,
+		})
+	}
+
+	// scripted moves
+	type move struct {
+		position string
+		action   string
+		chips    int64
+	}
+	moves := []move{
+		{pokertable.Position_Dealer, pokertable.WagerAction_Raise, 60},
+		{pokertable.Position_SB, pokertable.WagerAction_Fold, 0},
+		{pokertable.Position_BB, pokertable.WagerAction_Call, 0},
+		{pokertable.Position_BB, pokertable.WagerAction_Check, 0},
+		{pokertable.Position_Dealer, pokertable.WagerAction_Bet, 100},
+		{pokertable.Position_BB, pokertable.WagerAction_Call, 0},
+		{pokertable.Position_BB, pokertable.WagerAction_Check, 0},
+		{pokertable.Position_Dealer, pokertable.WagerAction_Check, 0},
+		{pokertable.Position_BB, pokertable.WagerAction_Bet, 200},
+		{pokertable.Position_Dealer, pokertable.WagerAction_Call, 0},
+	}
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var settledOnce sync.Once
+	var mu sync.Mutex
+	handHistory := ""
+	positionPlayerIDs := make(map[string]string)
+	winnerPlayerID := ""
+	gameActions := make([]pokertable.TablePlayerGameAction, 0)
+	manager := pokertable.NewManager(pokertable.WithManagerTableEngineOpts(
+		pokertable.WithRandomSource(rand.NewSource(1)),
+		pokertable.WithDeckProvider(func(table *pokertable.Table) []string {
+			return deck
+		}),
+	))
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnGamePlayerActionUpdated = func(gameAction pokertable.TablePlayerGameAction) {
+		mu.Lock()
+		defer mu.Unlock()
+		gameActions = append(gameActions, gameAction)
+	}
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerID := range playerIDs {
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				sbPlayerID := findPlayerID(table, "sb")
+				assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				playerID, actions := currentPlayerMove(table)
+				if funk.Contains(actions, "pass") {
+					assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+					return
+				}
+				if len(moves) == 0 {
+					return
+				}
+
+				m := moves[0]
+				moves = moves[1:]
+				assert.Equal(t, findPlayerID(table, m.position), playerID, "unexpected current player")
+
+				switch m.action {
+				case pokertable.WagerAction_Raise:
+					assert.Nil(t, tableEngine.PlayerRaise(playerID, m.chips), fmt.Sprintf("%s raise error", playerID))
+				case pokertable.WagerAction_Bet:
+					assert.Nil(t, tableEngine.PlayerBet(playerID, m.chips), fmt.Sprintf("%s bet error", playerID))
+				case pokertable.WagerAction_Call:
+					assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+				case pokertable.WagerAction_Check:
+					assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+				case pokertable.WagerAction_Fold:
+					assert.Nil(t, tableEngine.PlayerFold(playerID), fmt.Sprintf("%s fold error", playerID))
+				}
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			settledOnce.Do(func() {
+				mu.Lock()
+				defer mu.Unlock()
+
+				for _, position := range []string{pokertable.Position_Dealer, pokertable.Position_SB, pokertable.Position_BB} {
+					positionPlayerIDs[position] = findPlayerID(table, position)
+				}
+
+				winnerPlayerID = table.State.PlayerStates[table.State.GamePlayerIndexes[0]].PlayerID
+
+				var err error
+				handHistory, err = pokertable.ExportHandHistory(table, gameActions, nil)
+				assert.Nil(t, err, "export hand history failed")
+				wg.Done()
+			})
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.Rule = rule
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+
+	return handHistory, positionPlayerIDs, winnerPlayerID
+}
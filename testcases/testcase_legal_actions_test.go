@@ -0,0 +1,160 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_LegalActions(t *testing.T) {
+	redeemChips := int64(15000)
+	testCases := []struct {
+		bettingStructure string
+		actions          []string
+		minRaise         int64
+		maxRaise         int64
+	}{
+		{
+			bettingStructure: pokertable.BettingStructure_NoLimit,
+			actions:          []string{"fold", "call", "raise", "allin"},
+			minRaise:         40,
+			maxRaise:         redeemChips,
+		},
+		{
+			bettingStructure: pokertable.BettingStructure_PotLimit,
+			actions:          []string{"fold", "call", "raise"},
+			minRaise:         40,
+			maxRaise:         70,
+		},
+		{
+			bettingStructure: pokertable.BettingStructure_FixedLimit,
+			actions:          []string{"fold", "call", "raise"},
+			minRaise:         40,
+			maxRaise:         40,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.bettingStructure, func(t *testing.T) {
+			var wg sync.WaitGroup
+			wg.Add(1)
+
+			// given conditions
+			playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+			players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+				return pokertable.JoinPlayer{
+					PlayerID:    playerID,
+					RedeemChips: redeemChips,
+					Seat:        pokertable.UnsetValue,
+				}
+			}).([]pokertable.JoinPlayer)
+
+			// create manager & table
+			var tableEngine pokertable.TableEngine
+			var settledOnce sync.Once
+			isFirstMove := true
+			manager := pokertable.NewManager()
+			tableEngineOption := pokertable.NewTableEngineOptions()
+			tableEngineOption.GameContinueInterval = 1
+			tableEngineOption.OpenGameTimeout = 2
+			tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+			tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+				switch table.State.Status {
+				case pokertable.TableStateStatus_TableGamePlaying:
+					event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+					if !ok {
+						return
+					}
+
+					switch event {
+					case pokerface.GameEvent_ReadyRequested:
+						for _, playerID := range playerIDs {
+							assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+						}
+					case pokerface.GameEvent_BlindsRequested:
+						blind := table.State.BlindState
+
+						sbPlayerID := findPlayerID(table, "sb")
+						assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+						bbPlayerID := findPlayerID(table, "bb")
+						assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+					case pokerface.GameEvent_RoundStarted:
+						playerID, _ := currentPlayerMove(table)
+						legalActions, err := tableEngine.PlayerLegalActions(playerID)
+						assert.Nil(t, err, fmt.Sprintf("%s get legal actions error", playerID))
+						assert.Equal(t, legalActions, table.LegalActions(playerID))
+
+						// preflop: 第一位動作玩家面對大盲
+						if isFirstMove {
+							isFirstMove = false
+							assert.Equal(t, findPlayerID(table, pokertable.Position_Dealer), playerID)
+							assert.ElementsMatch(t, tc.actions, legalActions.Actions)
+							assert.Equal(t, int64(20), legalActions.CallAmount)
+							assert.Equal(t, tc.minRaise, legalActions.MinRaise)
+							assert.Equal(t, tc.maxRaise, legalActions.MaxRaise)
+							if legalActions.HasAction("allin") {
+								assert.Equal(t, redeemChips, legalActions.AllinAmount)
+							}
+						}
+
+						if legalActions.HasAction("pass") {
+							assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+						} else if legalActions.HasAction("check") {
+							assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+						} else if legalActions.HasAction("call") {
+							assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+						}
+					}
+				case pokertable.TableStateStatus_TableGameSettled:
+					settledOnce.Do(func() {
+						_, err := tableEngine.PlayerLegalActions("Nobody")
+						assert.ErrorIs(t, err, pokertable.ErrGamePlayerNotFound)
+						wg.Done()
+					})
+				}
+			}
+			tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+				t.Log("[Table] Error:", err)
+			}
+			tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+				participants := map[string]int{}
+				for idx, p := range players {
+					participants[p.PlayerID] = idx
+				}
+				tableEngine.SetUpTableGame(gameCount, participants)
+			}
+			tableSetting := NewDefaultTableSetting()
+			tableSetting.Meta.BettingStructure = tc.bettingStructure
+			table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+			assert.Nil(t, err, "create table failed")
+
+			// get table engine
+			tableEngine, err = manager.GetTableEngine(table.ID)
+			assert.Nil(t, err, "get table engine failed")
+
+			// players buy in
+			for _, joinPlayer := range players {
+				assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+				go func(player pokertable.JoinPlayer) {
+					time.Sleep(time.Microsecond * 10)
+					assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+				}(joinPlayer)
+			}
+
+			// Start game
+			time.Sleep(time.Microsecond * 100)
+			assert.Nil(t, tableEngine.StartTableGame())
+
+			wg.Wait()
+			assert.Nil(t, manager.ReleaseTable(table.ID))
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func newMTTJoinPlayers(playerIDs []string, redeemChips int64) []pokertable.JoinPlayer {
+	return funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+}
+
+func newMTTTableSetting(competitionID string, joinPlayers []pokertable.JoinPlayer) pokertable.TableSetting {
+	tableSetting := NewDefaultTableSetting(joinPlayers...)
+	tableSetting.Meta.CompetitionID = competitionID
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_MTT
+	tableSetting.Meta.TableMaxSeatCount = 6
+	return tableSetting
+}
+
+func TestTableGame_MTTCoordinator_Balance(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions: 大桌 6 人開打，小桌 2 人
+	competitionID := uuid.New().String()
+	redeemChips := int64(1000)
+	largePlayerIDs := []string{"Fred", "Jeffrey", "Chuck", "Lottie", "Kimi", "Jerry"}
+	smallPlayerIDs := []string{"Ivan", "Judy"}
+
+	// create manager & tables
+	var largeTableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	nextBBOrderPlayerIDs := make([]string, 0)
+	isMoved := false
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		// 結算時記錄下一個 BB 的順序 (重置後才會移動玩家)
+		if table.State.Status == pokertable.TableStateStatus_TableGameSettled {
+			mu.Lock()
+			if len(nextBBOrderPlayerIDs) == 0 {
+				nextBBOrderPlayerIDs = append(nextBBOrderPlayerIDs, table.State.NextBBOrderPlayerIDs...)
+			}
+			mu.Unlock()
+			return
+		}
+
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		// 所有玩家棄牌給 BB
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerIdx := range table.State.GamePlayerIndexes {
+				playerID := table.State.PlayerStates[playerIdx].PlayerID
+				assert.Nil(t, largeTableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, largeTableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, largeTableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, largeTableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if funk.Contains(actions, "fold") {
+				assert.Nil(t, largeTableEngine.PlayerFold(playerID), fmt.Sprintf("%s fold error", playerID))
+			} else if funk.Contains(actions, "check") {
+				assert.Nil(t, largeTableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			}
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		largeTableEngine.SetUpTableGame(gameCount, participants)
+	}
+	largeTable, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, newMTTTableSetting(competitionID, newMTTJoinPlayers(largePlayerIDs, redeemChips)))
+	assert.Nil(t, err, "create large table failed")
+	smallTable, err := manager.CreateTable(tableEngineOption, nil, newMTTTableSetting(competitionID, newMTTJoinPlayers(smallPlayerIDs, redeemChips)))
+	assert.Nil(t, err, "create small table failed")
+
+	// get table engine
+	largeTableEngine, err = manager.GetTableEngine(largeTable.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// coordinator
+	coordinator := pokertable.NewMTTCoordinator(manager, competitionID,
+		pokertable.WithMTTPlayersMoved(func(moves []pokertable.MTTPlayerMove) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if isMoved {
+				return
+			}
+			isMoved = true
+			defer wg.Done()
+
+			// 從大桌移動 2 人到小桌，即將輪到 BB 的玩家先移動
+			largeTable := largeTableEngine.GetTable()
+			if !assert.Len(t, moves, 2) {
+				return
+			}
+			for i, move := range moves {
+				assert.Equal(t, largeTable.ID, move.FromTableID)
+				assert.Equal(t, smallTable.ID, move.ToTableID)
+				assert.Equal(t, nextBBOrderPlayerIDs[i], move.PlayerID, "move order should follow next bb order")
+				assert.Equal(t, redeemChips, move.Chips, "all players fold to bb, moved players should not be blinds")
+
+				assert.Equal(t, pokertable.UnsetValue, largeTable.FindPlayerIdx(move.PlayerID), fmt.Sprintf("%s should leave large table", move.PlayerID))
+				playerIdx := smallTable.FindPlayerIdx(move.PlayerID)
+				if assert.NotEqual(t, pokertable.UnsetValue, playerIdx, fmt.Sprintf("%s should join small table", move.PlayerID)) {
+					player := smallTable.State.PlayerStates[playerIdx]
+					assert.True(t, player.IsIn)
+					assert.Equal(t, move.Chips, player.Bankroll)
+					assert.Equal(t, move.Seat, player.Seat)
+				}
+			}
+
+			assert.Len(t, largeTable.AlivePlayers(), 4)
+			assert.Len(t, smallTable.AlivePlayers(), 4)
+		}),
+		pokertable.WithMTTCoordinatorErrorUpdated(func(tableID string, err error) {
+			t.Log("[MTTCoordinator] Error:", tableID, err)
+		}),
+	)
+
+	// players join
+	for _, playerID := range largePlayerIDs {
+		assert.Nil(t, largeTableEngine.PlayerJoin(playerID), fmt.Sprintf("%s join error", playerID))
+	}
+	for _, playerID := range smallPlayerIDs {
+		assert.Nil(t, manager.PlayerJoin(smallTable.ID, playerID), fmt.Sprintf("%s join error", playerID))
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, largeTableEngine.StartTableGame())
+
+	wg.Wait()
+	coordinator.Stop()
+	assert.Nil(t, manager.ReleaseTable(largeTable.ID))
+	assert.Nil(t, manager.ReleaseTable(smallTable.ID))
+}
+
+func TestTableGame_MTTCoordinator_BreakTable(t *testing.T) {
+	// given conditions: 兩桌共 6 人，可以坐進同一桌
+	competitionID := uuid.New().String()
+	redeemChips := int64(1000)
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck", "Lottie"}
+	breakPlayerIDs := []string{"Ivan", "Judy"}
+
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	table, err := manager.CreateTable(tableEngineOption, nil, newMTTTableSetting(competitionID, newMTTJoinPlayers(playerIDs, redeemChips)))
+	assert.Nil(t, err, "create table failed")
+	breakTable, err := manager.CreateTable(tableEngineOption, nil, newMTTTableSetting(competitionID, newMTTJoinPlayers(breakPlayerIDs, redeemChips)))
+	assert.Nil(t, err, "create break table failed")
+
+	// 其他賽事的桌次不受影響
+	otherTable, err := manager.CreateTable(tableEngineOption, nil, newMTTTableSetting(uuid.New().String(), newMTTJoinPlayers([]string{"Kimi"}, redeemChips)))
+	assert.Nil(t, err, "create other table failed")
+
+	var brokenTableID string
+	var brokenMoves []pokertable.MTTPlayerMove
+	coordinator := pokertable.NewMTTCoordinator(manager, competitionID,
+		pokertable.WithMTTTableBroken(func(tableID string, moves []pokertable.MTTPlayerMove) {
+			brokenTableID = tableID
+			brokenMoves = moves
+		}),
+		pokertable.WithMTTCoordinatorErrorUpdated(func(tableID string, err error) {
+			t.Log("[MTTCoordinator] Error:", tableID, err)
+		}),
+	)
+
+	// 暫停中的桌次可以直接拆桌
+	assert.Nil(t, manager.PauseTable(otherTable.ID))
+	assert.Nil(t, manager.PauseTable(breakTable.ID))
+
+	assert.Equal(t, breakTable.ID, brokenTableID)
+	assert.Len(t, brokenMoves, len(breakPlayerIDs))
+	for _, move := range brokenMoves {
+		assert.Equal(t, breakTable.ID, move.FromTableID)
+		assert.Equal(t, table.ID, move.ToTableID)
+		assert.Equal(t, redeemChips, move.Chips)
+	}
+
+	// 拆掉的桌次已關閉，玩家全部移到另一桌
+	_, err = manager.GetTableEngine(breakTable.ID)
+	assert.ErrorIs(t, err, pokertable.ErrManagerTableNotFound)
+	assert.Equal(t, pokertable.TableStateStatus_TableClosed, breakTable.State.Status)
+	assert.Len(t, table.State.PlayerStates, len(playerIDs)+len(breakPlayerIDs))
+	for _, playerID := range breakPlayerIDs {
+		playerIdx := table.FindPlayerIdx(playerID)
+		if assert.NotEqual(t, pokertable.UnsetValue, playerIdx, fmt.Sprintf("%s should join table", playerID)) {
+			assert.True(t, table.State.PlayerStates[playerIdx].IsIn)
+		}
+	}
+	assert.Len(t, otherTable.State.PlayerStates, 1)
+
+	coordinator.Stop()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+	assert.Nil(t, manager.ReleaseTable(otherTable.ID))
+}
@@ -0,0 +1,178 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_PotLimit(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// scripted moves: 先嘗試超過底池限注的動作，再依腳本執行合法動作
+	type move struct {
+		position     string
+		action       string
+		chips        int64
+		overLimit    int64
+		maxChipLevel int64
+	}
+	moves := []move{
+		// preflop: 跟注後底池 = 10 + 20 + 20 = 50，最多加注到 20 + 50 = 70
+		{pokertable.Position_Dealer, pokertable.WagerAction_Raise, 70, 80, 70},
+		// 跟注後底池 = 10 + 20 + 70 + 60 = 160，最多加注到 70 + 160 = 230
+		{pokertable.Position_SB, pokertable.WagerAction_Fold, 0, 0, 230},
+		{pokertable.Position_BB, pokertable.WagerAction_Call, 0, 0, 0},
+		// flop: 底池 = 10 + 70 + 70 = 150
+		{pokertable.Position_BB, pokertable.WagerAction_Bet, 150, 200, 150},
+		{pokertable.Position_Dealer, pokertable.WagerAction_Call, 0, 0, 0},
+		{pokertable.Position_BB, pokertable.WagerAction_Check, 0, 0, 0},
+		{pokertable.Position_Dealer, pokertable.WagerAction_Check, 0, 0, 0},
+		{pokertable.Position_BB, pokertable.WagerAction_Check, 0, 0, 0},
+		{pokertable.Position_Dealer, pokertable.WagerAction_Check, 0, 0, 0},
+	}
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var settledOnce sync.Once
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerID := range playerIDs {
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				sbPlayerID := findPlayerID(table, "sb")
+				assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				playerID, actions := currentPlayerMove(table)
+				if funk.Contains(actions, "pass") {
+					assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+					return
+				}
+				if len(moves) == 0 {
+					return
+				}
+
+				m := moves[0]
+				moves = moves[1:]
+				assert.Equal(t, findPlayerID(table, m.position), playerID, "unexpected current player")
+
+				// 下注限制
+				if m.maxChipLevel > 0 {
+					assert.NotNil(t, table.State.WagerLimit, "wager limit should be provided")
+					if table.State.WagerLimit != nil {
+						if m.action == pokertable.WagerAction_Bet {
+							assert.Equal(t, m.maxChipLevel, table.State.WagerLimit.MaxBet, "invalid max bet")
+						} else {
+							assert.Equal(t, m.maxChipLevel, table.State.WagerLimit.MaxRaise, "invalid max raise")
+						}
+					}
+				}
+
+				switch m.action {
+				case pokertable.WagerAction_Raise:
+					assert.ErrorIs(t, tableEngine.PlayerRaise(playerID, m.overLimit), pokertable.ErrTablePlayerWagerOverLimit)
+					assert.ErrorIs(t, tableEngine.PlayerAllin(playerID), pokertable.ErrTablePlayerWagerOverLimit)
+					assert.Nil(t, tableEngine.PlayerRaise(playerID, m.chips), fmt.Sprintf("%s raise error", playerID))
+				case pokertable.WagerAction_Bet:
+					assert.ErrorIs(t, tableEngine.PlayerBet(playerID, m.overLimit), pokertable.ErrTablePlayerWagerOverLimit)
+					assert.ErrorIs(t, tableEngine.PlayerAllin(playerID), pokertable.ErrTablePlayerWagerOverLimit)
+					assert.Nil(t, tableEngine.PlayerBet(playerID, m.chips), fmt.Sprintf("%s bet error", playerID))
+				case pokertable.WagerAction_Call:
+					assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+				case pokertable.WagerAction_Check:
+					assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+				case pokertable.WagerAction_Fold:
+					assert.Nil(t, tableEngine.PlayerFold(playerID), fmt.Sprintf("%s fold error", playerID))
+				}
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			settledOnce.Do(func() {
+				assert.Empty(t, moves, "all scripted moves should be played")
+				assert.Nil(t, table.State.WagerLimit, "wager limit should be cleared")
+
+				// 底池 = 10 + 220 + 220 = 450
+				total := int64(0)
+				for _, playerResult := range table.State.GameState.Result.Players {
+					total += playerResult.Final
+				}
+				assert.Equal(t, redeemChips*int64(len(playerIDs)), total)
+
+				DebugPrintTableGameSettled(*table)
+				wg.Done()
+			})
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.BettingStructure = pokertable.BettingStructure_PotLimit
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	err = tableEngine.StartTableGame()
+	assert.Nil(t, err)
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
@@ -0,0 +1,181 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_Cash_Rake(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// 奇數手翻牌前棄牌給大盲 (不抽水)，偶數手全員跟注到攤牌 (底池 60，抽 10% 但 3 人上限為 5)
+	rakeSetting := pokertable.TableRakeSetting{
+		Percent: 10,
+		Caps: []pokertable.TableRakeCap{
+			{MinPlayerCount: 2, Cap: 10},
+			{MinPlayerCount: 3, Cap: 5},
+		},
+		NoFlopNoDrop: true,
+	}
+	maxGameCount := 4
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	handledGames := make(map[int]bool)
+	totalRake := int64(0)
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			// 同一個遊戲狀態只處理一次
+			mu.Lock()
+			if handledStates[table.State.GameState.UpdatedAt] {
+				mu.Unlock()
+				return
+			}
+			handledStates[table.State.GameState.UpdatedAt] = true
+			mu.Unlock()
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerIdx := range table.State.GamePlayerIndexes {
+					playerID := table.State.PlayerStates[playerIdx].PlayerID
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				sbPlayerID := findPlayerID(table, "sb")
+				assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				playerID, actions := currentPlayerMove(table)
+				if funk.Contains(actions, "pass") {
+					assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+				} else if table.State.GameCount%2 == 1 && funk.Contains(actions, "fold") {
+					assert.Nil(t, tableEngine.PlayerFold(playerID), fmt.Sprintf("%s fold error", playerID))
+				} else if funk.Contains(actions, "check") {
+					assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+				} else if funk.Contains(actions, "call") {
+					assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+				}
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			gameCount := table.State.GameCount
+
+			// 同一手只處理一次
+			mu.Lock()
+			if handledGames[gameCount] {
+				mu.Unlock()
+				return
+			}
+			handledGames[gameCount] = true
+			mu.Unlock()
+
+			gameRake := table.State.GameRake
+			if !assert.NotNil(t, gameRake, fmt.Sprintf("game %d: game rake should be recorded", gameCount)) {
+				wg.Done()
+				return
+			}
+			assert.Equal(t, table.State.GameState.GameID, gameRake.GameID)
+			assert.Equal(t, gameCount, gameRake.GameCount)
+			assert.Equal(t, len(playerIDs), gameRake.PlayerCount)
+			assert.Equal(t, int64(5), gameRake.Cap)
+
+			if gameCount%2 == 1 {
+				// 未發翻牌不抽水
+				assert.Empty(t, table.State.GameState.Status.Board)
+				assert.Equal(t, int64(0), gameRake.Total, fmt.Sprintf("game %d: no flop no drop", gameCount))
+			} else {
+				assert.Equal(t, int64(5), gameRake.Total, fmt.Sprintf("game %d: rake should be capped", gameCount))
+			}
+
+			playerRake := int64(0)
+			for _, rake := range gameRake.Players {
+				playerRake += rake
+			}
+			assert.Equal(t, gameRake.Total, playerRake, fmt.Sprintf("game %d: player rake mismatch", gameCount))
+
+			// 總籌碼 + 累積抽水 = 總買入
+			totalRake += gameRake.Total
+			totalBankroll := int64(0)
+			for _, playerState := range table.State.PlayerStates {
+				totalBankroll += playerState.Bankroll
+			}
+			assert.Equal(t, redeemChips*int64(len(playerIDs)), totalBankroll+totalRake, fmt.Sprintf("game %d: total chips mismatch", gameCount))
+
+			if gameCount == maxGameCount {
+				wg.Done()
+			}
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.MaxDuration = 600
+	tableSetting.Meta.Rake = rakeSetting
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
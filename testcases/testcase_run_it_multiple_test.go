@@ -0,0 +1,200 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_Cash_RunItTwice(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// 翻牌前全下，桌次最多發三次牌，兩位玩家分別同意發三次與兩次牌 (取最小值)
+	maxRunItTimes := 3
+	agreedTimes := map[string]int{"Fred": 3, "Jeffrey": 2}
+	runItTimes := 2
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isSettled := false
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if funk.Contains(actions, "allin") {
+				assert.Nil(t, tableEngine.PlayerAllin(playerID), fmt.Sprintf("%s allin error", playerID))
+			}
+		case pokerface.GameEvent_RoundClosed:
+			// 只在詢問當下回覆
+			if table.State.GameRunout == nil || len(table.State.GameState.Status.Board) != table.State.GameRunout.BoardSize {
+				return
+			}
+
+			assert.Equal(t, 0, table.State.GameRunout.BoardSize, "should ask before the flop")
+			assert.ElementsMatch(t, playerIDs, table.State.GameRunout.PlayerIDs)
+			for _, playerID := range playerIDs {
+				gamePlayerIdx := table.FindGamePlayerIdx(playerID)
+				assert.Contains(t, table.State.GameState.Players[gamePlayerIdx].AllowedActions, pokertable.Action_RunItMultiple)
+			}
+
+			go func() {
+				for _, playerID := range playerIDs {
+					assert.Nil(t, tableEngine.PlayerRunItMultiple(playerID, agreedTimes[playerID]), fmt.Sprintf("%s run it multiple error", playerID))
+				}
+			}()
+		}
+	}
+	tableEngineCallbacks.OnGameSettled = func(result pokertable.TableGameResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// 只檢查第一手
+		if isSettled {
+			return
+		}
+		isSettled = true
+		defer wg.Done()
+
+		// 每次發牌都是完整的五張公牌，且第一次沿用本手公牌
+		if !assert.Len(t, result.Boards, runItTimes) {
+			return
+		}
+		assert.Equal(t, result.Board, result.Boards[0])
+		usedCards := make(map[string]bool)
+		for _, board := range result.Boards {
+			assert.Len(t, board, 5)
+			for _, card := range board {
+				assert.False(t, usedCards[card], fmt.Sprintf("card %s should be dealt once", card))
+				usedCards[card] = true
+			}
+		}
+
+		// 總籌碼不變，底池全數分配
+		changed := int64(0)
+		for _, player := range result.Players {
+			changed += player.Changed
+		}
+		assert.Equal(t, int64(0), changed, "total changed should be zero")
+
+		for _, pot := range result.Pots {
+			awarded := int64(0)
+			for _, winner := range pot.Winners {
+				awarded += winner.Chips
+			}
+			assert.Equal(t, pot.Total, awarded, "pot should be fully awarded")
+		}
+
+		// 任一次公牌有分到籌碼即為攤牌獲勝
+		winnerPlayerIDs := make(map[string]bool)
+		for _, pot := range result.Pots {
+			for _, winner := range pot.Winners {
+				if winner.Chips > 0 {
+					winnerPlayerIDs[winner.PlayerID] = true
+				}
+			}
+		}
+		// 結算事件在釋放 te.lock 後發出，經由快照讀取玩家統計
+		snapshot, err := tableEngine.GetSnapshot()
+		if !assert.Nil(t, err) {
+			return
+		}
+		for _, player := range snapshot.Table.State.PlayerStates {
+			assert.True(t, player.GameStatistics.ShowdownWinningChance, fmt.Sprintf("%s should have showdown winning chance", player.PlayerID))
+			assert.Equal(t, winnerPlayerIDs[player.PlayerID], player.GameStatistics.IsShowdownWinning, fmt.Sprintf("%s showdown winning mismatch", player.PlayerID))
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.MaxDuration = 60
+	tableSetting.Meta.MaxRunItTimes = maxRunItTimes
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
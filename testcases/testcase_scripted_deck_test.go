@@ -0,0 +1,147 @@
+package testcases
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_Scripted_Deck(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// scripted deck: hole cards (game player 0, 1), burn, flop, burn, turn, burn, river
+	scriptedCards := []string{
+		"SA", "HA",
+		"C2", "D7",
+		"S2",
+		"SK", "SQ", "SJ",
+		"S3",
+		"ST",
+		"S4",
+		"H9",
+	}
+	deck := append([]string{}, scriptedCards...)
+	for _, card := range pokerface.NewStandardDeckCards() {
+		if !funk.ContainsString(scriptedCards, card) {
+			deck = append(deck, card)
+		}
+	}
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var settledOnce sync.Once
+	manager := pokertable.NewManager(pokertable.WithManagerTableEngineOpts(
+		pokertable.WithRandomSource(rand.NewSource(20231017)),
+		pokertable.WithDeckProvider(func(table *pokertable.Table) []string {
+			return deck
+		}),
+	))
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGameOpened:
+			DebugPrintTableGameOpened(*table)
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerID := range playerIDs {
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				sbPlayerID := findPlayerID(table, "sb")
+				assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				playerID, actions := currentPlayerMove(table)
+				if funk.Contains(actions, "allin") {
+					assert.Nil(t, tableEngine.PlayerAllin(playerID), fmt.Sprintf("%s allin error", playerID))
+				}
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			settledOnce.Do(func() {
+				gs := table.State.GameState
+				assert.Equal(t, []string{"SA", "HA"}, gs.Players[0].HoleCards)
+				assert.Equal(t, []string{"C2", "D7"}, gs.Players[1].HoleCards)
+				assert.Equal(t, []string{"SK", "SQ", "SJ", "ST", "H9"}, gs.Status.Board)
+
+				// game player 0 makes a royal flush
+				assert.NotNil(t, gs.Result, "invalid game result")
+				for _, playerResult := range gs.Result.Players {
+					if playerResult.Idx == 0 {
+						assert.Equal(t, redeemChips*2, playerResult.Final)
+					} else {
+						assert.Equal(t, int64(0), playerResult.Final)
+					}
+				}
+
+				DebugPrintTableGameSettled(*table)
+				wg.Done()
+			})
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, NewDefaultTableSetting())
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	err = tableEngine.StartTableGame()
+	assert.Nil(t, err)
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
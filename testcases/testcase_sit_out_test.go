@@ -0,0 +1,164 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_Cash_SitOut_SitIn(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// Chuck 第 1 手後暫離，暫離 2 手 (一定會錯過大盲) 後回座
+	sitOutPlayerID := "Chuck"
+	sitOutGameCount := 1
+	sitInGameCount := 3
+	maxGameCount := 10
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledGames := make(map[int]bool)
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerIdx := range table.State.GamePlayerIndexes {
+					playerID := table.State.PlayerStates[playerIdx].PlayerID
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				// 暫離玩家的 SB 位置為空
+				if sbPlayerID := findPlayerID(table, "sb"); sbPlayerID != "" {
+					assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+				}
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				playerID, actions := currentPlayerMove(table)
+				if funk.Contains(actions, "pass") {
+					assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+				} else if funk.Contains(actions, "check") {
+					assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+				} else if funk.Contains(actions, "call") {
+					assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+				}
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			gameCount := table.State.GameCount
+
+			// 同一手只處理一次
+			mu.Lock()
+			if handledGames[gameCount] {
+				mu.Unlock()
+				return
+			}
+			handledGames[gameCount] = true
+			mu.Unlock()
+
+			playerIdx := table.FindPlayerIdx(sitOutPlayerID)
+			playerState := table.State.PlayerStates[playerIdx]
+			isParticipated := funk.ContainsInt(table.State.GamePlayerIndexes, playerIdx)
+
+			switch {
+			case gameCount <= sitOutGameCount:
+				assert.True(t, isParticipated, fmt.Sprintf("game %d: %s should participate", gameCount, sitOutPlayerID))
+				if gameCount == sitOutGameCount {
+					go func() {
+						assert.Nil(t, tableEngine.PlayerSitOut(sitOutPlayerID), fmt.Sprintf("%s sit out error", sitOutPlayerID))
+						assert.Nil(t, tableEngine.PlayerSitOut(sitOutPlayerID), "sit out twice should be ignored")
+					}()
+				}
+			case gameCount <= sitInGameCount:
+				assert.True(t, playerState.IsSitOut, fmt.Sprintf("game %d: %s should sit out", gameCount, sitOutPlayerID))
+				assert.False(t, isParticipated, fmt.Sprintf("game %d: %s should not participate", gameCount, sitOutPlayerID))
+				assert.Equal(t, 2, len(table.State.GamePlayerIndexes))
+				if gameCount == sitInGameCount {
+					go func() {
+						assert.Nil(t, tableEngine.PlayerSitIn(sitOutPlayerID), fmt.Sprintf("%s sit in error", sitOutPlayerID))
+					}()
+				}
+			default:
+				assert.False(t, playerState.IsSitOut, fmt.Sprintf("game %d: %s should sit in", gameCount, sitOutPlayerID))
+				if isParticipated {
+					// 現金桌暫離期間錯過大盲，回座後需等到大盲才能回到牌局
+					assert.Contains(t, playerState.Positions, pokertable.Position_BB, fmt.Sprintf("game %d: %s should come back as bb", gameCount, sitOutPlayerID))
+					wg.Done()
+				} else if gameCount >= maxGameCount {
+					assert.Fail(t, fmt.Sprintf("%s never comes back", sitOutPlayerID))
+					wg.Done()
+				}
+			}
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.MaxDuration = 600
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+
+	assert.ErrorIs(t, manager.PlayerSitOut(table.ID, "Nobody"), pokertable.ErrTablePlayerNotFound)
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
@@ -0,0 +1,296 @@
+package testcases
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_Snapshot_Restore(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+
+	// restored manager & table
+	var restoredTableEngine pokertable.TableEngine
+	var settledOnce sync.Once
+	restoredManager := pokertable.NewManager()
+	restoredCallbacks := pokertable.NewTableEngineCallbacks()
+	restoredCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok || event != pokerface.GameEvent_RoundStarted || restoredTableEngine == nil {
+				return
+			}
+
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "allin") {
+				t.Logf(fmt.Sprintf("[restored] %s's move: allin", playerID))
+				assert.Nil(t, restoredTableEngine.PlayerAllin(playerID), fmt.Sprintf("%s allin error", playerID))
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			settledOnce.Do(func() {
+				assert.NotNil(t, table.State.GameState.Result, "invalid game result")
+				assert.Equal(t, 1, table.State.GameCount)
+
+				total := int64(0)
+				for _, playerResult := range table.State.GameState.Result.Players {
+					playerIdx := table.State.GamePlayerIndexes[playerResult.Idx]
+					player := table.State.PlayerStates[playerIdx]
+					assert.Equal(t, playerResult.Final, player.Bankroll)
+					total += player.Bankroll
+				}
+				assert.Equal(t, redeemChips*int64(len(playerIDs)), total)
+
+				DebugPrintTableGameSettled(*table)
+				wg.Done()
+			})
+		}
+	}
+	restoredCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Restored Table] Error:", err)
+	}
+
+	restore := func(snapshot *pokertable.TableEngineSnapshot) {
+		// snapshot should survive serialization
+		encoded, err := json.Marshal(snapshot)
+		assert.Nil(t, err, "encode snapshot failed")
+
+		var decoded pokertable.TableEngineSnapshot
+		assert.Nil(t, json.Unmarshal(encoded, &decoded), "decode snapshot failed")
+
+		table, err := restoredManager.RestoreTable(tableEngineOption, restoredCallbacks, decoded)
+		assert.Nil(t, err, "restore table failed")
+		assert.Equal(t, snapshot.Table.State.CurrentActionEndAt, table.State.CurrentActionEndAt)
+
+		restoredTableEngine, err = restoredManager.GetTableEngine(table.ID)
+		assert.Nil(t, err, "get restored table engine failed")
+
+		// first move after restoring
+		playerID, actions := currentPlayerMove(table)
+		if funk.Contains(actions, "allin") {
+			t.Logf(fmt.Sprintf("[restored] %s's move: allin", playerID))
+			assert.Nil(t, restoredTableEngine.PlayerAllin(playerID), fmt.Sprintf("%s allin error", playerID))
+		}
+	}
+
+	// original manager & table
+	var tableEngine pokertable.TableEngine
+	var snapshotOnce sync.Once
+	manager := pokertable.NewManager()
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGameOpened:
+			DebugPrintTableGameOpened(*table)
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerID := range playerIDs {
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				sbPlayerID := findPlayerID(table, "sb")
+				assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				// take snapshot at preflop & move table to another manager
+				snapshotOnce.Do(func() {
+					snapshot, err := manager.GetTableSnapshot(table.ID)
+					assert.Nil(t, err, "get table snapshot failed")
+					assert.NotNil(t, snapshot.GameState)
+					assert.Nil(t, manager.ReleaseTable(table.ID))
+
+					go restore(snapshot)
+				})
+			}
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, NewDefaultTableSetting())
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	err = tableEngine.StartTableGame()
+	assert.Nil(t, err)
+
+	wg.Wait()
+}
+
+func TestTableGame_Snapshot_Restore_ActionTimeout(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+
+	// 還原後沒有玩家動作，等待中的玩家應由還原後的計時器超時
+	var mu sync.Mutex
+	var actorPlayerID string
+	var timeoutOnce sync.Once
+	restoredManager := pokertable.NewManager()
+	restoredCallbacks := pokertable.NewTableEngineCallbacks()
+	restoredCallbacks.OnTablePlayerActionTimeout = func(competitionID, tableID string, playerState *pokertable.TablePlayerState) {
+		timeoutOnce.Do(func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			assert.Equal(t, actorPlayerID, playerState.PlayerID, "waiting player should time out after restoring")
+			assert.Equal(t, 1, playerState.ActionTimeoutCount)
+			wg.Done()
+		})
+	}
+	restoredCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Restored Table] Error:", err)
+	}
+
+	// original manager & table
+	var tableEngine pokertable.TableEngine
+	var snapshotOnce sync.Once
+	manager := pokertable.NewManager()
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			// take snapshot while waiting on the first player's move & move table to another manager
+			snapshotOnce.Do(func() {
+				snapshot, err := manager.GetTableSnapshot(table.ID)
+				assert.Nil(t, err, "get table snapshot failed")
+				assert.Nil(t, manager.ReleaseTable(table.ID))
+
+				mu.Lock()
+				actorPlayerID, _ = currentPlayerMove(snapshot.Table)
+				mu.Unlock()
+
+				go func() {
+					_, err := restoredManager.RestoreTable(tableEngineOption, restoredCallbacks, *snapshot)
+					assert.Nil(t, err, "restore table failed")
+				}()
+			})
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.ActionTime = 1
+	tableSetting.Meta.ActionTimeoutEnforced = true
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, restoredManager.ReleaseTable(table.ID))
+}
@@ -0,0 +1,286 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+type straddleGameResult struct {
+	straddlerID   string
+	preflopOrder  []string
+	gameActions   []pokertable.TablePlayerGameAction
+	firstMinRaise int64
+	straddlerActs []string
+	statistics    map[string]pokertable.TablePlayerGameStatistics
+	result        pokertable.TableGameResult
+}
+
+/*
+runStraddleGame 現金桌打一手 Straddle 牌局
+  - 發牌前由 straddlePosition 位置的玩家 Straddle，其他玩家翻牌前皆跟注，Straddle 玩家過牌
+  - 翻牌後皆過牌到攤牌
+*/
+func runStraddleGame(t *testing.T, straddleMode, straddlePosition string) straddleGameResult {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck", "Lottie"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isSettled := false
+	gameResult := straddleGameResult{
+		preflopOrder: make([]string, 0),
+		gameActions:  make([]pokertable.TablePlayerGameAction, 0),
+		statistics:   make(map[string]pokertable.TablePlayerGameStatistics),
+	}
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying || table.State.GameCount != 1 {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			// 發牌前: Straddle 後才 Ready
+			if table.State.GameState.Status.Round == "" {
+				straddlerID := findPlayerID(table, straddlePosition)
+				assert.Nil(t, tableEngine.PlayerStraddle(straddlerID), fmt.Sprintf("%s straddle error", straddlerID))
+				assert.ErrorIs(t, tableEngine.PlayerStraddle(straddlerID), pokertable.ErrGameInvalidAction, "only one straddle per game")
+				assert.ErrorIs(t, tableEngine.PlayerStraddle(findPlayerID(table, pokertable.Position_SB)), pokertable.ErrTableStraddleInvalidPlayer, "sb can't straddle")
+
+				mu.Lock()
+				gameResult.straddlerID = straddlerID
+				mu.Unlock()
+			}
+
+			for _, playerIdx := range table.State.GamePlayerIndexes {
+				playerID := table.State.PlayerStates[playerIdx].PlayerID
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+
+			// 發牌前 Straddle 視窗已關閉
+			if table.State.GameState.Status.Round == "" {
+				assert.Error(t, tableEngine.PlayerStraddle(findPlayerID(table, pokertable.Position_Dealer)))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+
+			if table.State.GameState.Status.Round == pokertable.GameRound_Preflop {
+				mu.Lock()
+				if len(gameResult.preflopOrder) == 0 && table.State.WagerLimit != nil {
+					gameResult.firstMinRaise = table.State.WagerLimit.MinRaise
+				}
+				gameResult.preflopOrder = append(gameResult.preflopOrder, playerID)
+				if playerID == gameResult.straddlerID {
+					gameResult.straddlerActs = actions
+				}
+				mu.Unlock()
+			}
+
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if funk.Contains(actions, "check") {
+				assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			} else if funk.Contains(actions, "call") {
+				assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+			}
+		}
+	}
+	tableEngineCallbacks.OnGamePlayerActionUpdated = func(gameAction pokertable.TablePlayerGameAction) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if gameAction.GameCount == 1 {
+			gameResult.gameActions = append(gameResult.gameActions, gameAction)
+		}
+	}
+	tableEngineCallbacks.OnGameSettled = func(result pokertable.TableGameResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// 只檢查第一手
+		if isSettled {
+			return
+		}
+		isSettled = true
+		gameResult.result = result
+		for _, playerState := range tableEngine.GetTable().State.PlayerStates {
+			gameResult.statistics[playerState.PlayerID] = playerState.GameStatistics
+		}
+		wg.Done()
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.MaxDuration = 60
+	tableSetting.Meta.StraddleMode = straddleMode
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+
+	mu.Lock()
+	defer mu.Unlock()
+	return gameResult
+}
+
+// assertStraddleGame 檢查 Straddle 玩家翻牌前最後行動，且 Straddle 視同盲注
+func assertStraddleGame(t *testing.T, gameResult straddleGameResult, expectedOrder []string) {
+	straddlerID := gameResult.straddlerID
+	assert.Equal(t, expectedOrder, funk.Map(gameResult.preflopOrder, func(playerID string) string {
+		for _, player := range gameResult.result.Players {
+			if player.PlayerID == playerID {
+				return funk.Filter(player.Positions, func(position string) bool {
+					return position != pokertable.Position_Straddle
+				}).([]string)[0]
+			}
+		}
+		return ""
+	}), "preflop action order mismatch")
+
+	// 最小加注到兩倍 Straddle，Straddle 玩家最後行動時保有過牌與加注權
+	assert.Equal(t, int64(80), gameResult.firstMinRaise)
+	assert.Contains(t, gameResult.straddlerActs, "check")
+	assert.Contains(t, gameResult.straddlerActs, "raise")
+
+	// Straddle 動作與位置
+	straddleActions := funk.Filter(gameResult.gameActions, func(gameAction pokertable.TablePlayerGameAction) bool {
+		return gameAction.Action == pokertable.Action_Straddle
+	}).([]pokertable.TablePlayerGameAction)
+	if assert.Len(t, straddleActions, 1) {
+		assert.Equal(t, straddlerID, straddleActions[0].PlayerID)
+		assert.Equal(t, int64(40), straddleActions[0].Chips)
+		assert.Equal(t, pokertable.GameRound_Preflop, straddleActions[0].Round)
+		assert.Contains(t, straddleActions[0].Positions, pokertable.Position_Straddle)
+	}
+	for _, player := range gameResult.result.Players {
+		assert.Equal(t, player.PlayerID == straddlerID, funk.ContainsString(player.Positions, pokertable.Position_Straddle), fmt.Sprintf("%s straddle position mismatch", player.PlayerID))
+	}
+
+	// 主池: 4 位玩家各 40
+	if assert.NotEmpty(t, gameResult.result.Pots) {
+		assert.Equal(t, int64(160), gameResult.result.Pots[0].Total)
+	}
+	changed := int64(0)
+	for _, player := range gameResult.result.Players {
+		changed += player.Changed
+	}
+	assert.Equal(t, int64(0), changed, "total changed should be zero")
+
+	// Straddle 為強制下注: Straddle 玩家過牌不算 VPIP，也沒有玩家翻前加注
+	for playerID, statistics := range gameResult.statistics {
+		if playerID == straddlerID {
+			assert.False(t, statistics.IsVPIP, "straddler should not be vpip")
+		}
+		assert.False(t, statistics.IsPFR, fmt.Sprintf("%s pfr mismatch", playerID))
+		assert.False(t, statistics.Is3B, fmt.Sprintf("%s 3-bet mismatch", playerID))
+	}
+}
+
+func TestTableGame_Cash_Straddle_UTG(t *testing.T) {
+	gameResult := runStraddleGame(t, pokertable.StraddleMode_UTG, pokertable.Position_UG)
+	assertStraddleGame(t, gameResult, []string{pokertable.Position_Dealer, pokertable.Position_SB, pokertable.Position_BB, pokertable.Position_UG})
+}
+
+func TestTableGame_Cash_Straddle_Mississippi(t *testing.T) {
+	// 按鈕位 Straddle: 由小盲開始行動，按鈕位最後行動
+	gameResult := runStraddleGame(t, pokertable.StraddleMode_Mississippi, pokertable.Position_Dealer)
+	assertStraddleGame(t, gameResult, []string{pokertable.Position_SB, pokertable.Position_BB, pokertable.Position_UG, pokertable.Position_Dealer})
+}
+
+func TestTableGame_Straddle_InvalidSetting(t *testing.T) {
+	manager := pokertable.NewManager()
+
+	// 只開放現金桌
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.StraddleMode = pokertable.StraddleMode_UTG
+	_, err := manager.CreateTable(pokertable.NewTableEngineOptions(), nil, tableSetting)
+	assert.ErrorIs(t, err, pokertable.ErrTableInvalidCreateSetting)
+
+	// 固定限注不開放
+	tableSetting = NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.BettingStructure = pokertable.BettingStructure_FixedLimit
+	tableSetting.Meta.StraddleMode = pokertable.StraddleMode_UTG
+	_, err = manager.CreateTable(pokertable.NewTableEngineOptions(), nil, tableSetting)
+	assert.ErrorIs(t, err, pokertable.ErrTableInvalidCreateSetting)
+
+	tableSetting = NewDefaultTableSetting()
+	tableSetting.Meta.Mode = pokertable.CompetitionMode_Cash
+	tableSetting.Meta.StraddleMode = "unknown"
+	_, err = manager.CreateTable(pokertable.NewTableEngineOptions(), nil, tableSetting)
+	assert.ErrorIs(t, err, pokertable.ErrTableInvalidCreateSetting)
+}
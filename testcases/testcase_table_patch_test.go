@@ -0,0 +1,221 @@
+package testcases
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_TablePatch(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// 客戶端依序套用桌次差異，每次都要與完整桌次相同
+	decoder := pokertable.NewTablePatchDecoder()
+	playerEncoder := pokertable.NewPlayerTablePatchEncoder(playerIDs[0])
+	playerDecoder := pokertable.NewTablePatchDecoder()
+	decodedTables := make(map[int64]string)
+	patchCount := 0
+	verifiedCount := 0
+	fullSize := 0
+	patchSize := 0
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isSettled := false
+	manager := pokertable.NewManager(pokertable.WithManagerTableEngineOpts(pokertable.WithTablePatch()))
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTablePatched = func(patch pokertable.TablePatch) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if isSettled {
+			return
+		}
+
+		patchCount++
+		if patchCount == 1 {
+			assert.True(t, patch.IsFull(), "first patch should be full table")
+		} else {
+			assert.False(t, patch.IsFull(), "following patches should be diffs")
+			assert.Equal(t, decoder.UpdateSerial(), patch.BaseSerial)
+		}
+
+		// 跳過差異的客戶端需要重新取得完整桌次
+		if !patch.IsFull() {
+			_, err := pokertable.NewTablePatchDecoder().Apply(&patch)
+			assert.ErrorIs(t, err, pokertable.ErrTablePatchGap)
+		}
+
+		table, err := decoder.Apply(&patch)
+		if !assert.Nil(t, err, "apply table patch error") {
+			return
+		}
+		assert.Equal(t, patch.UpdateSerial, table.UpdateSerial)
+		assert.Equal(t, patch.UpdateSerial, decoder.UpdateSerial())
+
+		// 不可洩漏牌組與玩家底牌
+		if gs := table.State.GameState; gs != nil {
+			assert.Empty(t, gs.Meta.Deck, "deck should be redacted")
+			if gs.Status.CurrentEvent != pokerface.GameEventSymbols[pokerface.GameEvent_GameClosed] {
+				for _, p := range gs.Players {
+					assert.Empty(t, p.HoleCards, "hole cards should be redacted")
+				}
+			}
+		}
+
+		tableJSON, err := table.GetJSON()
+		assert.Nil(t, err)
+		decodedTables[patch.UpdateSerial] = tableJSON
+
+		encoded, err := json.Marshal(patch)
+		assert.Nil(t, err)
+		fullSize += len(tableJSON)
+		patchSize += len(encoded)
+	}
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		// 桌次差異會在桌次更新前發出，且為旁觀者視角
+		view, err := table.ViewForObserver()
+		assert.Nil(t, err)
+		tableJSON, err := view.GetJSON()
+		assert.Nil(t, err)
+
+		// 玩家視角的差異由接收端各自產生
+		playerView, err := table.ViewFor(playerIDs[0])
+		assert.Nil(t, err)
+		playerTableJSON, err := playerView.GetJSON()
+		assert.Nil(t, err)
+
+		mu.Lock()
+		if decodedTableJSON, exist := decodedTables[table.UpdateSerial]; exist {
+			assert.JSONEq(t, tableJSON, decodedTableJSON, fmt.Sprintf("table #%d mismatch", table.UpdateSerial))
+			verifiedCount++
+		}
+
+		playerPatch, err := playerEncoder.Encode(table)
+		if assert.Nil(t, err, "encode player table patch error") {
+			decoded, err := playerDecoder.Apply(playerPatch)
+			if assert.Nil(t, err, "apply player table patch error") {
+				decodedJSON, err := decoded.GetJSON()
+				assert.Nil(t, err)
+				assert.JSONEq(t, playerTableJSON, decodedJSON, fmt.Sprintf("player table #%d mismatch", table.UpdateSerial))
+			}
+		}
+		mu.Unlock()
+
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			sbPlayerID := findPlayerID(table, "sb")
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if funk.Contains(actions, "check") {
+				assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			} else if funk.Contains(actions, "call") {
+				assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+			}
+		}
+	}
+	tableEngineCallbacks.OnGameSettled = func(result pokertable.TableGameResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// 只檢查第一手
+		if isSettled {
+			return
+		}
+		isSettled = true
+		defer wg.Done()
+
+		assert.Greater(t, patchCount, 10)
+		assert.Equal(t, patchCount, verifiedCount, "every patch should be verified")
+		assert.Less(t, patchSize, fullSize/2, "patches should be much smaller than full tables")
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.MaxDuration = 60
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
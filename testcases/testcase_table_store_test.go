@@ -0,0 +1,251 @@
+package testcases
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_TableStore_Recover(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	storeDir := t.TempDir()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+
+	// recovered manager (after restart)
+	var recoveredManager pokertable.Manager
+	var settledOnce sync.Once
+	recoveredCallbacks := pokertable.NewTableEngineCallbacks()
+	recoveredCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok || event != pokerface.GameEvent_RoundStarted {
+				return
+			}
+
+			if _, err := recoveredManager.GetTableEngine(table.ID); err != nil {
+				return
+			}
+
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "allin") {
+				t.Logf(fmt.Sprintf("[recovered] %s's move: allin", playerID))
+				assert.Nil(t, recoveredManager.PlayerAllin(table.ID, playerID), fmt.Sprintf("%s allin error", playerID))
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			settledOnce.Do(func() {
+				assert.NotNil(t, table.State.GameState.Result, "invalid game result")
+				for _, playerResult := range table.State.GameState.Result.Players {
+					playerIdx := table.State.GamePlayerIndexes[playerResult.Idx]
+					player := table.State.PlayerStates[playerIdx]
+					assert.Equal(t, playerResult.Final, player.Bankroll)
+				}
+
+				DebugPrintTableGameSettled(*table)
+				wg.Done()
+			})
+		}
+	}
+
+	recoverTables := func(tableID string) {
+		store, err := pokertable.NewFileTableStore(storeDir)
+		assert.Nil(t, err, "create file table store failed")
+
+		tableIDs, err := store.List()
+		assert.Nil(t, err, "list tables failed")
+		assert.Equal(t, []string{tableID}, tableIDs)
+
+		recoveredManager = pokertable.NewManager(pokertable.WithManagerTableStore(store))
+		tables, err := recoveredManager.Recover(tableEngineOption, recoveredCallbacks)
+		assert.Nil(t, err, "recover tables failed")
+		assert.Equal(t, 1, len(tables))
+
+		// first move after recovering
+		table := tables[0]
+		playerID, actions := currentPlayerMove(table)
+		if funk.Contains(actions, "allin") {
+			t.Logf(fmt.Sprintf("[recovered] %s's move: allin", playerID))
+			assert.Nil(t, recoveredManager.PlayerAllin(table.ID, playerID), fmt.Sprintf("%s allin error", playerID))
+		}
+	}
+
+	// original manager (before crash)
+	store, err := pokertable.NewFileTableStore(storeDir)
+	assert.Nil(t, err, "create file table store failed")
+
+	var tableEngine pokertable.TableEngine
+	var crashOnce sync.Once
+	manager := pokertable.NewManager(pokertable.WithManagerTableStore(store))
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGameOpened:
+			DebugPrintTableGameOpened(*table)
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerID := range playerIDs {
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				sbPlayerID := findPlayerID(table, "sb")
+				assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				// stop playing at preflop, then recover tables from disk
+				crashOnce.Do(func() {
+					go recoverTables(table.ID)
+				})
+			}
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, NewDefaultTableSetting())
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	err = tableEngine.StartTableGame()
+	assert.Nil(t, err)
+
+	wg.Wait()
+
+	// closed tables are removed from store
+	assert.Nil(t, recoveredManager.CloseTable(table.ID))
+	tableIDs, err := store.List()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(tableIDs))
+}
+
+func TestFileTableStore_LoadAt_CompactAfterRestart(t *testing.T) {
+	storeDir := t.TempDir()
+	tableID := "table-store-load-at"
+	compactThreshold := 5
+
+	newSnapshot := func(updateSerial int64) *pokertable.TableEngineSnapshot {
+		return &pokertable.TableEngineSnapshot{
+			Table: &pokertable.Table{ID: tableID, UpdateSerial: updateSerial},
+		}
+	}
+
+	// 重新啟動前寫入 3 筆快照
+	store, err := pokertable.NewFileTableStore(storeDir)
+	assert.Nil(t, err, "create file table store failed")
+	store.CompactThreshold = compactThreshold
+	for serial := int64(1); serial <= 3; serial++ {
+		assert.Nil(t, store.Save(newSnapshot(serial)))
+	}
+
+	snapshot, err := store.LoadAt(tableID, 2)
+	if assert.Nil(t, err, "load at serial #2 failed") {
+		assert.Equal(t, int64(2), snapshot.Table.UpdateSerial)
+	}
+	_, err = store.LoadAt(tableID, 10)
+	assert.ErrorIs(t, err, pokertable.ErrTableStoreUpdateSerialNotFound)
+	_, err = store.LoadAt("unknown", 1)
+	assert.ErrorIs(t, err, pokertable.ErrTableStoreTableNotFound)
+
+	// 重新啟動後沿用既有行數，第 5 筆寫入時壓縮
+	restartedStore, err := pokertable.NewFileTableStore(storeDir)
+	assert.Nil(t, err, "create restarted file table store failed")
+	restartedStore.CompactThreshold = compactThreshold
+	for serial := int64(4); serial <= 5; serial++ {
+		assert.Nil(t, restartedStore.Save(newSnapshot(serial)))
+	}
+
+	_, err = restartedStore.LoadAt(tableID, 2)
+	assert.ErrorIs(t, err, pokertable.ErrTableStoreUpdateSerialNotFound, "snapshots before compaction should be dropped")
+
+	snapshot, err = restartedStore.Load(tableID)
+	if assert.Nil(t, err, "load latest failed") {
+		assert.Equal(t, int64(5), snapshot.Table.UpdateSerial)
+	}
+}
+
+func TestTableGame_TableStore_Recover_SkipCorruptTable(t *testing.T) {
+	storeDir := t.TempDir()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+
+	// 正常桌次
+	store, err := pokertable.NewFileTableStore(storeDir)
+	assert.Nil(t, err, "create file table store failed")
+	manager := pokertable.NewManager(pokertable.WithManagerTableStore(store))
+	table, err := manager.CreateTable(tableEngineOption, nil, NewDefaultTableSetting())
+	assert.Nil(t, err, "create table failed")
+
+	// 損毀的桌次檔案 (排在正常桌次之前)
+	corruptTableID := "0-corrupt-table"
+	assert.Nil(t, os.WriteFile(filepath.Join(storeDir, corruptTableID+".jsonl"), []byte("{broken\n"), 0644))
+
+	// 損毀的桌次不影響其他桌次還原
+	recoveredStore, err := pokertable.NewFileTableStore(storeDir)
+	assert.Nil(t, err, "create file table store failed")
+	recoveredManager := pokertable.NewManager(pokertable.WithManagerTableStore(recoveredStore))
+	tables, err := recoveredManager.Recover(tableEngineOption, nil)
+	if assert.Equal(t, 1, len(tables)) {
+		assert.Equal(t, table.ID, tables[0].ID)
+	}
+
+	recoverErr, ok := err.(pokertable.ManagerRecoverError)
+	if assert.True(t, ok, "recover error should list failed tables") {
+		assert.Equal(t, 1, len(recoverErr))
+		assert.ErrorIs(t, recoverErr[corruptTableID], pokertable.ErrTableStoreTableNotFound)
+	}
+
+	assert.Nil(t, recoveredManager.ReleaseTable(table.ID))
+}
@@ -0,0 +1,187 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_ViewFor(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey", "Chuck"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+
+	// SB 翻牌前棄牌，其他玩家跟注到攤牌
+	var sbPlayerID string
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isSettled := false
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		if table.State.GameState == nil {
+			return
+		}
+
+		// 攤牌後可看到未棄牌玩家的底牌，棄牌玩家的底牌仍隱藏
+		if table.State.Status == pokertable.TableStateStatus_TableGameSettled {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if isSettled {
+				return
+			}
+			isSettled = true
+			defer wg.Done()
+
+			observerView, err := table.ViewForObserver()
+			assert.Nil(t, err, "observer view error")
+			assert.Empty(t, observerView.State.GameState.Meta.Deck, "deck should be hidden")
+			for _, p := range observerView.State.GameState.Players {
+				if p.Fold {
+					assert.Empty(t, p.HoleCards, "folded hole cards should be hidden after showdown")
+					assert.Nil(t, p.Combination)
+				} else {
+					assert.Len(t, p.HoleCards, 2, "showdown hole cards should be visible")
+				}
+			}
+			assert.Equal(t, len(playerIDs)-1, len(funk.Filter(observerView.State.GameState.Players, func(p *pokerface.PlayerState) bool {
+				return len(p.HoleCards) > 0
+			}).([]*pokerface.PlayerState)))
+			return
+		}
+
+		if table.State.Status != pokertable.TableStateStatus_TableGamePlaying {
+			return
+		}
+
+		event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+		if !ok {
+			return
+		}
+
+		// 同一個遊戲狀態只處理一次
+		mu.Lock()
+		if handledStates[table.State.GameState.UpdatedAt] {
+			mu.Unlock()
+			return
+		}
+		handledStates[table.State.GameState.UpdatedAt] = true
+		mu.Unlock()
+
+		switch event {
+		case pokerface.GameEvent_ReadyRequested:
+			for _, playerID := range playerIDs {
+				assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+			}
+		case pokerface.GameEvent_BlindsRequested:
+			blind := table.State.BlindState
+
+			mu.Lock()
+			sbPlayerID = findPlayerID(table, "sb")
+			mu.Unlock()
+			assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+			bbPlayerID := findPlayerID(table, "bb")
+			assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+		case pokerface.GameEvent_RoundStarted:
+			// 遊戲進行中，玩家只能看到自己的底牌，旁觀者看不到任何底牌
+			for _, playerID := range playerIDs {
+				view, err := table.ViewFor(playerID)
+				assert.Nil(t, err, fmt.Sprintf("%s view error", playerID))
+				assert.Empty(t, view.State.GameState.Meta.Deck, "deck should be hidden")
+				assert.Empty(t, view.State.GameState.Status.Burned, "burned cards should be hidden")
+
+				gamePlayerIdx := view.FindGamePlayerIdx(playerID)
+				for _, p := range view.State.GameState.Players {
+					if p.Idx == gamePlayerIdx {
+						assert.Equal(t, table.State.GameState.Players[p.Idx].HoleCards, p.HoleCards, fmt.Sprintf("%s should see own hole cards", playerID))
+					} else {
+						assert.Empty(t, p.HoleCards, fmt.Sprintf("%s should not see others' hole cards", playerID))
+						assert.Nil(t, p.Combination)
+					}
+				}
+			}
+
+			observerView, err := table.ViewForObserver()
+			assert.Nil(t, err, "observer view error")
+			for _, p := range observerView.State.GameState.Players {
+				assert.Empty(t, p.HoleCards, "observer should not see hole cards")
+			}
+
+			// 原本的桌次不受影響
+			assert.NotEmpty(t, table.State.GameState.Meta.Deck)
+			for _, p := range table.State.GameState.Players {
+				assert.Len(t, p.HoleCards, 2, "original table should keep hole cards")
+			}
+
+			playerID, actions := currentPlayerMove(table)
+			if funk.Contains(actions, "pass") {
+				assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+			} else if playerID == findPlayerID(table, "sb") && funk.Contains(actions, "fold") {
+				assert.Nil(t, tableEngine.PlayerFold(playerID), fmt.Sprintf("%s fold error", playerID))
+			} else if funk.Contains(actions, "check") {
+				assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+			} else if funk.Contains(actions, "call") {
+				assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+			}
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.MaxDuration = 60
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}
@@ -0,0 +1,173 @@
+package testcases
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thoas/go-funk"
+	"github.com/weedbox/pokerface"
+	"github.com/weedbox/pokertable"
+)
+
+func TestTableGame_TimeBank(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	// given conditions
+	playerIDs := []string{"Fred", "Jeffrey"}
+	redeemChips := int64(15000)
+	players := funk.Map(playerIDs, func(playerID string) pokertable.JoinPlayer {
+		return pokertable.JoinPlayer{
+			PlayerID:    playerID,
+			RedeemChips: redeemChips,
+			Seat:        pokertable.UnsetValue,
+		}
+	}).([]pokertable.JoinPlayer)
+	timeBankInitial := 10
+	timeBankLevelTopUp := 5
+
+	// create manager & table
+	var tableEngine pokertable.TableEngine
+	var settledOnce sync.Once
+	var mu sync.Mutex
+	handledStates := make(map[int64]bool)
+	isTimeBankTested := false
+	timeBankUsages := make([]int, 0)
+	manager := pokertable.NewManager()
+	tableEngineOption := pokertable.NewTableEngineOptions()
+	tableEngineOption.GameContinueInterval = 1
+	tableEngineOption.OpenGameTimeout = 2
+	tableEngineCallbacks := pokertable.NewTableEngineCallbacks()
+	tableEngineCallbacks.OnTablePlayerTimeBankUsed = func(competitionID, tableID string, playerState *pokertable.TablePlayerState, duration int) {
+		timeBankUsages = append(timeBankUsages, duration)
+	}
+	tableEngineCallbacks.OnTableUpdated = func(table *pokertable.Table) {
+		switch table.State.Status {
+		case pokertable.TableStateStatus_TableGamePlaying:
+			event, ok := pokerface.GameEventBySymbol[table.State.GameState.Status.CurrentEvent]
+			if !ok {
+				return
+			}
+
+			// 延長動作時間也會更新桌次，同一個遊戲狀態只處理一次
+			mu.Lock()
+			if handledStates[table.State.GameState.UpdatedAt] {
+				mu.Unlock()
+				return
+			}
+			handledStates[table.State.GameState.UpdatedAt] = true
+			mu.Unlock()
+
+			switch event {
+			case pokerface.GameEvent_ReadyRequested:
+				for _, playerID := range playerIDs {
+					assert.Nil(t, tableEngine.PlayerReady(playerID), fmt.Sprintf("%s ready error", playerID))
+				}
+			case pokerface.GameEvent_BlindsRequested:
+				blind := table.State.BlindState
+
+				sbPlayerID := findPlayerID(table, "sb")
+				assert.Nil(t, tableEngine.PlayerPay(sbPlayerID, blind.SB), fmt.Sprintf("%s pay sb error", sbPlayerID))
+
+				bbPlayerID := findPlayerID(table, "bb")
+				assert.Nil(t, tableEngine.PlayerPay(bbPlayerID, blind.BB), fmt.Sprintf("%s pay bb error", bbPlayerID))
+			case pokerface.GameEvent_RoundStarted:
+				playerID, actions := currentPlayerMove(table)
+
+				if !isTimeBankTested {
+					isTimeBankTested = true
+
+					// 非當前動作玩家不能使用時間銀行
+					otherPlayerID := playerIDs[0]
+					if otherPlayerID == playerID {
+						otherPlayerID = playerIDs[1]
+					}
+					_, err := tableEngine.PlayerExtendActionDeadline(otherPlayerID, 3)
+					assert.ErrorIs(t, err, pokertable.ErrTablePlayerNotCurrentActor)
+
+					// 扣除時間銀行
+					endAt := table.State.CurrentActionEndAt
+					currentActionEndAt, err := tableEngine.PlayerExtendActionDeadline(playerID, 6)
+					assert.Nil(t, err, fmt.Sprintf("%s extend action deadline error", playerID))
+					assert.Equal(t, endAt+6, currentActionEndAt)
+
+					// 時間銀行不足時只延長剩餘秒數
+					currentActionEndAt, err = tableEngine.PlayerExtendActionDeadline(playerID, 10)
+					assert.Nil(t, err, fmt.Sprintf("%s extend action deadline error", playerID))
+					assert.Equal(t, endAt+int64(timeBankInitial), currentActionEndAt)
+
+					// 時間銀行用完
+					_, err = tableEngine.PlayerExtendActionDeadline(playerID, 1)
+					assert.ErrorIs(t, err, pokertable.ErrTablePlayerTimeBankExhausted)
+
+					playerState := tableEngine.GetTable().State.PlayerStates[tableEngine.GetTable().FindPlayerIdx(playerID)]
+					assert.Equal(t, 0, playerState.TimeBank)
+					assert.Equal(t, []int{6, 4}, timeBankUsages)
+				}
+
+				if funk.Contains(actions, "pass") {
+					assert.Nil(t, tableEngine.PlayerPass(playerID), fmt.Sprintf("%s pass error", playerID))
+				} else if funk.Contains(actions, "check") {
+					assert.Nil(t, tableEngine.PlayerCheck(playerID), fmt.Sprintf("%s check error", playerID))
+				} else if funk.Contains(actions, "call") {
+					assert.Nil(t, tableEngine.PlayerCall(playerID), fmt.Sprintf("%s call error", playerID))
+				}
+			}
+		case pokertable.TableStateStatus_TableGameSettled:
+			settledOnce.Do(func() {
+				wg.Done()
+			})
+		}
+	}
+	tableEngineCallbacks.OnTableErrorUpdated = func(table *pokertable.Table, err error) {
+		t.Log("[Table] Error:", err)
+	}
+	tableEngineCallbacks.OnReadyOpenFirstTableGame = func(competitionID, tableID string, gameCount int, players []*pokertable.TablePlayerState) {
+		participants := map[string]int{}
+		for idx, p := range players {
+			participants[p.PlayerID] = idx
+		}
+		tableEngine.SetUpTableGame(gameCount, participants)
+	}
+	tableSetting := NewDefaultTableSetting()
+	tableSetting.Meta.TimeBankInitial = timeBankInitial
+	tableSetting.Meta.TimeBankLevelTopUp = timeBankLevelTopUp
+	table, err := manager.CreateTable(tableEngineOption, tableEngineCallbacks, tableSetting)
+	assert.Nil(t, err, "create table failed")
+
+	// get table engine
+	tableEngine, err = manager.GetTableEngine(table.ID)
+	assert.Nil(t, err, "get table engine failed")
+
+	// players buy in
+	for _, joinPlayer := range players {
+		assert.Nil(t, tableEngine.PlayerReserve(joinPlayer), fmt.Sprintf("%s reserve error", joinPlayer.PlayerID))
+
+		go func(player pokertable.JoinPlayer) {
+			time.Sleep(time.Microsecond * 10)
+			assert.Nil(t, tableEngine.PlayerJoin(player.PlayerID), fmt.Sprintf("%s join error", player.PlayerID))
+		}(joinPlayer)
+	}
+
+	// Start game
+	time.Sleep(time.Microsecond * 100)
+	assert.Nil(t, tableEngine.StartTableGame())
+
+	wg.Wait()
+
+	// 盲注升級時補充時間銀行
+	timeBanks := make(map[string]int)
+	for _, player := range tableEngine.GetTable().State.PlayerStates {
+		timeBanks[player.PlayerID] = player.TimeBank
+	}
+	blind := tableEngine.GetTable().State.BlindState
+	assert.Nil(t, manager.UpdateBlind(table.ID, blind.Level+1, blind.Ante, blind.Dealer, blind.SB*2, blind.BB*2))
+	for _, player := range tableEngine.GetTable().State.PlayerStates {
+		assert.Equal(t, timeBanks[player.PlayerID]+timeBankLevelTopUp, player.TimeBank, fmt.Sprintf("%s time bank top up error", player.PlayerID))
+	}
+
+	assert.Nil(t, manager.ReleaseTable(table.ID))
+}